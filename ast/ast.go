@@ -2,7 +2,10 @@
 package ast
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/teleivo/dot/token"
 )
@@ -86,7 +89,7 @@ type Stmt interface {
 }
 
 // ID is a dot identifier as defined by https://graphviz.org/doc/info/lang.html#ids. HTML strings
-// are not supported.
+// like <<b>bold</b>> are IDs as well. Their contents are not validated.
 type ID struct {
 	Literal  string         // Identifier literal
 	StartPos token.Position // Position of the first rune of the ID
@@ -97,6 +100,145 @@ func (id ID) String() string {
 	return string(id.Literal)
 }
 
+// IsQuoted reports whether the ID is a double-quoted string like "A". Quotes are part of the
+// [ID.Literal].
+func (id ID) IsQuoted() bool {
+	return len(id.Literal) > 0 && id.Literal[0] == '"'
+}
+
+// IsHTML reports whether the ID is an HTML string like <<b>bold</b>>. The delimiting '<' and '>'
+// are part of the [ID.Literal].
+func (id ID) IsHTML() bool {
+	return len(id.Literal) > 0 && id.Literal[0] == '<'
+}
+
+// Unquoted returns the ID as interpreted by Graphviz. Unquoted strings and numerals are returned as
+// is. Quoted strings are returned without their quotes, without any line continuation made of a
+// backslash followed by a newline and with escaped quotes \" replaced by a quote. The IDs A and "A"
+// thus refer to the same node. HTML strings are returned without their delimiting '<' and '>'.
+func (id ID) Unquoted() string {
+	if id.IsHTML() && len(id.Literal) >= 2 {
+		return id.Literal[1 : len(id.Literal)-1]
+	}
+	if !id.IsQuoted() || len(id.Literal) < 2 {
+		return id.Literal
+	}
+
+	in := id.Literal[1 : len(id.Literal)-1]
+	if !strings.Contains(in, `\`) {
+		return in
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(in); i++ {
+		if in[i] == '\\' && i+1 < len(in) {
+			switch in[i+1] {
+			case '"':
+				out.WriteByte('"')
+				i++
+				continue
+			case '\n':
+				i++
+				continue
+			case '\r':
+				if i+2 < len(in) && in[i+2] == '\n' {
+					i += 2
+					continue
+				}
+			}
+		}
+		out.WriteByte(in[i])
+	}
+	return out.String()
+}
+
+// NewID creates an ID from the string s as interpreted by Graphviz. It is the inverse of
+// [ID.Unquoted]. The ID is quoted unless s is an unquoted string that is not a keyword or a
+// numeral. Quotes in s are escaped. A backslash at the end of s or before a newline is followed by
+// a line continuation. It would otherwise escape the closing quote or form a line continuation
+// itself. Doubling it does not work as Graphviz does not unescape backslashes.
+func NewID(s string) ID {
+	if CanUnquote(s) {
+		return ID{Literal: s}
+	}
+
+	var out strings.Builder
+	out.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '"':
+			out.WriteString(`\"`)
+		case s[i] == '\\' && (i+1 == len(s) || s[i+1] == '\n' || strings.HasPrefix(s[i+1:], "\r\n")):
+			out.WriteString("\\\\\n")
+		default:
+			out.WriteByte(s[i])
+		}
+	}
+	out.WriteByte('"')
+	return ID{Literal: out.String()}
+}
+
+// UniqueID returns an ID for a new node that is not taken. It returns base if it is not taken and
+// otherwise base followed by an underscore and the smallest number starting at 1 that is not taken
+// like legend_1. IDs are compared unquoted so legend and "legend" collide. Use [NewID] to quote the
+// returned ID if needed.
+func UniqueID(base string, taken func(id string) bool) string {
+	if !taken(base) {
+		return base
+	}
+	for i := 1; ; i++ {
+		id := base + "_" + strconv.Itoa(i)
+		if !taken(id) {
+			return id
+		}
+	}
+}
+
+// CanUnquote determines if the string s is a valid unquoted string or numeral as defined in
+// https://graphviz.org/doc/info/lang.html#ids. Keywords need to stay quoted.
+func CanUnquote(s string) bool {
+	return (isUnquotedString(s) && token.Lookup(s) == token.Identifier) || isNumeral(s)
+}
+
+// isUnquotedString determines if the input is an unquoted string as defined in
+// https://graphviz.org/doc/info/lang.html#ids.
+func isUnquotedString(in string) bool {
+	if in == "" {
+		return false
+	}
+	for i, r := range in {
+		if r != '_' && !isAlphabetic(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// isAlphabetic determines if the rune is part of the allowed alphabetic characters of an unquoted
+// identifier as defined in https://graphviz.org/doc/info/lang.html#ids.
+func isAlphabetic(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '\200' && r <= '\377')
+}
+
+// isNumeral determines if the input is a numeral [-]?(.[0-9]⁺ | [0-9]⁺(.[0-9]*)? ).
+func isNumeral(in string) bool {
+	if len(in) > 0 && in[0] == '-' {
+		in = in[1:]
+	}
+
+	var hasDigit, hasDot bool
+	for _, r := range in {
+		if r == '.' && !hasDot {
+			hasDot = true
+		} else if unicode.IsDigit(r) {
+			hasDigit = true
+		} else {
+			return false
+		}
+	}
+	return hasDigit
+}
+
 func (id ID) Start() token.Position {
 	return id.StartPos
 }
@@ -263,11 +405,48 @@ var compassPoints = map[string]CompassPointType{
 	"c":  CompassPointCenter,
 }
 
+// IsCompassPoint parses the compass point in like ne. It returns false if in is not a compass
+// point.
 func IsCompassPoint(in string) (CompassPointType, bool) {
 	v, ok := compassPoints[in]
 	return v, ok
 }
 
+// EdgeOp is the operator connecting the operands of an edge statement.
+type EdgeOp int
+
+const (
+	UndirectedEdgeOp EdgeOp = iota // UndirectedEdgeOp is the operator '--' of edges in undirected graphs.
+	DirectedEdgeOp                 // DirectedEdgeOp is the operator '->' of edges in directed graphs.
+)
+
+func (op EdgeOp) String() string {
+	if op == DirectedEdgeOp {
+		return "->"
+	}
+	return "--"
+}
+
+// NewEdgeOp returns the edge operator of directed or undirected graphs.
+func NewEdgeOp(directed bool) EdgeOp {
+	if directed {
+		return DirectedEdgeOp
+	}
+	return UndirectedEdgeOp
+}
+
+// ParseEdgeOp parses the edge operator '->' or '--'. It returns false if in is not an edge
+// operator.
+func ParseEdgeOp(in string) (EdgeOp, bool) {
+	switch in {
+	case "->":
+		return DirectedEdgeOp, true
+	case "--":
+		return UndirectedEdgeOp, true
+	}
+	return UndirectedEdgeOp, false
+}
+
 // EdgeStmt is a dot edge statement connecting nodes or subgraphs.
 type EdgeStmt struct {
 	Left     EdgeOperand // Left is the left node identifier or subgraph of the edge statement.
@@ -313,32 +492,28 @@ type EdgeRHS struct {
 func (er EdgeRHS) String() string {
 	var out strings.Builder
 
-	if er.Directed {
-		out.WriteString(" -> ")
-	} else {
-		out.WriteString(" -- ")
-	}
-	out.WriteString(er.Right.String())
-
-	for cur := er.Next; cur != nil; cur = cur.Next {
-		if cur.Directed {
-			out.WriteString(" -> ")
-		} else {
-			out.WriteString(" -- ")
-		}
+	for cur := &er; cur != nil; cur = cur.Next {
+		out.WriteRune(' ')
+		out.WriteString(cur.Op().String())
+		out.WriteRune(' ')
 		out.WriteString(cur.Right.String())
 	}
 
 	return out.String()
 }
 
+// Op returns the edge operator of the right-hand side.
+func (er EdgeRHS) Op() EdgeOp {
+	return NewEdgeOp(er.Directed)
+}
+
 func (er EdgeRHS) Start() token.Position {
 	return er.StartPos
 }
 
 func (er EdgeRHS) End() token.Position {
 	var last EdgeOperand
-	for cur := &er; cur != nil; cur = er.Next {
+	for cur := &er; cur != nil; cur = cur.Next {
 		last = cur.Right
 	}
 	return last.End()
@@ -548,3 +723,34 @@ func (c Comment) Start() token.Position {
 func (c Comment) End() token.Position {
 	return c.EndPos
 }
+
+// generatedRegexp matches a comment marking dot code as generated following the convention of Go
+// https://go.dev/s/generatedcode.
+var generatedRegexp = regexp.MustCompile(`^(//|#) Code generated .* DO NOT EDIT\.$`)
+
+// IsGeneratedComment reports whether the comment marks the dot code as generated. Such a comment
+// matches the regular expression
+//
+//	^(//|#) Code generated .* DO NOT EDIT\.$
+//
+// which follows the [convention] used by Go.
+//
+// [convention]: https://go.dev/s/generatedcode
+func IsGeneratedComment(c Comment) bool {
+	return generatedRegexp.MatchString(c.Text)
+}
+
+// IsGenerated reports whether the graph was generated by a program and should thus not be edited by
+// hand. A graph is generated if any comment before the graph start is a generated comment as
+// defined by [IsGeneratedComment].
+func IsGenerated(g Graph) bool {
+	for _, c := range g.Comments {
+		if !c.StartPos.Before(g.Start()) {
+			break
+		}
+		if IsGeneratedComment(c) {
+			return true
+		}
+	}
+	return false
+}
@@ -912,3 +912,200 @@ func TestPosition(t *testing.T) {
 		})
 	}
 }
+
+func TestIDIsQuoted(t *testing.T) {
+	tests := map[string]struct {
+		in   ID
+		want bool
+	}{
+		"Unquoted": {
+			in:   ID{Literal: "A"},
+			want: false,
+		},
+		"Numeral": {
+			in:   ID{Literal: "-1.5"},
+			want: false,
+		},
+		"Quoted": {
+			in:   ID{Literal: `"A"`},
+			want: true,
+		},
+		"HTML": {
+			in:   ID{Literal: `<<b>A</b>>`},
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualValuesf(t, test.in.IsQuoted(), test.want, "IsQuoted(%q)", test.in.Literal)
+		})
+	}
+}
+
+func TestIDIsHTML(t *testing.T) {
+	tests := map[string]struct {
+		in   ID
+		want bool
+	}{
+		"Unquoted": {
+			in:   ID{Literal: "A"},
+			want: false,
+		},
+		"Quoted": {
+			in:   ID{Literal: `"<b>A</b>"`},
+			want: false,
+		},
+		"HTML": {
+			in:   ID{Literal: `<<b>A</b>>`},
+			want: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualValuesf(t, test.in.IsHTML(), test.want, "IsHTML(%q)", test.in.Literal)
+		})
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	tests := map[string]struct {
+		in   Graph
+		want bool
+	}{
+		"WithoutComments": {
+			in: Graph{
+				GraphStart: token.Position{Row: 1, Column: 1},
+			},
+			want: false,
+		},
+		"WithGeneratedComment": {
+			in: Graph{
+				GraphStart: token.Position{Row: 3, Column: 1},
+				Comments: []Comment{
+					{Text: "// header", StartPos: token.Position{Row: 1, Column: 1}},
+					{Text: "// Code generated by dotgen. DO NOT EDIT.", StartPos: token.Position{Row: 2, Column: 1}},
+				},
+			},
+			want: true,
+		},
+		"WithGeneratedCommentUsingHashMarker": {
+			in: Graph{
+				GraphStart: token.Position{Row: 2, Column: 1},
+				Comments: []Comment{
+					{Text: "# Code generated by dotgen v1.0.0. DO NOT EDIT.", StartPos: token.Position{Row: 1, Column: 1}},
+				},
+			},
+			want: true,
+		},
+		"WithGeneratedCommentAfterGraphStart": {
+			in: Graph{
+				GraphStart: token.Position{Row: 1, Column: 1},
+				Comments: []Comment{
+					{Text: "// Code generated by dotgen. DO NOT EDIT.", StartPos: token.Position{Row: 2, Column: 1}},
+				},
+			},
+			want: false,
+		},
+		"WithCommentNotFollowingConvention": {
+			in: Graph{
+				GraphStart: token.Position{Row: 2, Column: 1},
+				Comments: []Comment{
+					{Text: "/* Code generated by dotgen. DO NOT EDIT. */", StartPos: token.Position{Row: 1, Column: 1}},
+				},
+			},
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualValuesf(t, IsGenerated(test.in), test.want, "IsGenerated(%v)", test.in.Comments)
+		})
+	}
+}
+
+func TestIDUnquoted(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"Unquoted":             {in: "A", want: "A"},
+		"Numeral":              {in: "-1.5", want: "-1.5"},
+		"Quoted":               {in: `"A"`, want: "A"},
+		"QuotedEmpty":          {in: `""`, want: ""},
+		"QuotedWithEscapes":    {in: `"say \"hi\" \n"`, want: `say "hi" \n`},
+		"QuotedWithLineBreaks": {in: "\"multi \\\nline \\\r\nlabel\"", want: "multi line label"},
+		"HTML":                 {in: `<<b>"A"</b>>`, want: `<b>"A"</b>`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id := ID{Literal: test.in}
+			assert.EqualValuesf(t, id.Unquoted(), test.want, "Unquoted(%q)", test.in)
+		})
+	}
+}
+
+func TestNewID(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"Unquoted":     {in: "A_1", want: "A_1"},
+		"Numeral":      {in: "-1.5", want: "-1.5"},
+		"Keyword":      {in: "node", want: `"node"`},
+		"Empty":        {in: "", want: `""`},
+		"LeadingDigit": {in: "1A", want: `"1A"`},
+		"Spaces":       {in: "a b", want: `"a b"`},
+		"Quotes":       {in: `say "hi"`, want: `"say \"hi\""`},
+		"Backslash":    {in: `a\b`, want: `"a\b"`},
+		// a trailing backslash would escape the closing quote
+		"TrailingBackslash":      {in: `a\`, want: "\"a\\\\\n\""},
+		"BackslashBeforeNewline": {in: "a\\\nb", want: "\"a\\\\\n\nb\""},
+		"BackslashBeforeQuote":   {in: `a\"`, want: `"a\\""`},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := NewID(test.in)
+
+			assert.EqualValuesf(t, got.Literal, test.want, "NewID(%q)", test.in)
+			assert.EqualValuesf(t, got.Unquoted(), test.in, "NewID(%q).Unquoted()", test.in)
+		})
+	}
+}
+
+func TestCanUnquote(t *testing.T) {
+	tests := map[string]bool{
+		"A_1":  true,
+		"-1.5": true,
+		".5":   true,
+		"":     false,
+		"-":    false,
+		"node": false,
+		"1A":   false,
+		"a b":  false,
+	}
+
+	for in, want := range tests {
+		assert.EqualValuesf(t, CanUnquote(in), want, "CanUnquote(%q)", in)
+	}
+}
+
+func TestEdgeOp(t *testing.T) {
+	for _, op := range []EdgeOp{UndirectedEdgeOp, DirectedEdgeOp} {
+		got, ok := ParseEdgeOp(op.String())
+
+		assert.Truef(t, ok, "ParseEdgeOp(%q)", op)
+		assert.EqualValuesf(t, got, op, "ParseEdgeOp(%q)", op)
+	}
+
+	_, ok := ParseEdgeOp("<-")
+	assert.Falsef(t, ok, "ParseEdgeOp(%q)", "<-")
+
+	assert.EqualValuesf(t, NewEdgeOp(true), DirectedEdgeOp, "NewEdgeOp(true)")
+	assert.EqualValuesf(t, NewEdgeOp(false), UndirectedEdgeOp, "NewEdgeOp(false)")
+	assert.EqualValuesf(t, EdgeRHS{Directed: true}.Op(), DirectedEdgeOp, "Op()")
+}
@@ -0,0 +1,121 @@
+package ast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Checksum returns a checksum of the graph made of 16 hexadecimal digits. It only depends on what
+// the graph declares and not on its formatting, comments, how IDs are quoted or how attributes are
+// split into attribute lists. Formatting a graph thus keeps its checksum while editing its
+// statements changes it. The printer records it in the header of generated graphs so hand edits
+// can be detected.
+func Checksum(g Graph) string {
+	h := sha256.New()
+	if g.IsStrict() {
+		io.WriteString(h, "strict ")
+	}
+	if g.Directed {
+		io.WriteString(h, "digraph ")
+	} else {
+		io.WriteString(h, "graph ")
+	}
+	if g.ID != nil {
+		writeID(h, *g.ID)
+	}
+	writeStmts(h, g.Stmts)
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func writeStmts(h hash.Hash, stmts []Stmt) {
+	io.WriteString(h, "{")
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *NodeStmt:
+			io.WriteString(h, "node ")
+			writeNodeID(h, st.NodeID)
+			writeAttrList(h, st.AttrList)
+		case *EdgeStmt:
+			io.WriteString(h, "edge ")
+			writeEdgeOperand(h, st.Left)
+			for rhs := &st.Right; rhs != nil; rhs = rhs.Next {
+				writeEdgeOperand(h, rhs.Right)
+			}
+			writeAttrList(h, st.AttrList)
+		case *AttrStmt:
+			// keywords are case-insensitive
+			io.WriteString(h, strings.ToLower(st.ID.Literal)+" ")
+			writeAttrList(h, &st.AttrList)
+		case Attribute:
+			writeAttribute(h, st)
+		case Subgraph:
+			writeSubgraph(h, st)
+		}
+		io.WriteString(h, ";")
+	}
+	io.WriteString(h, "}")
+}
+
+func writeNodeID(h hash.Hash, nodeID NodeID) {
+	writeID(h, nodeID.ID)
+	if nodeID.Port == nil {
+		return
+	}
+	if nodeID.Port.Name != nil {
+		io.WriteString(h, ":")
+		writeID(h, *nodeID.Port.Name)
+	}
+	// the compass point _ is the default so a:p:_ and a:p are the same
+	if cp := nodeID.Port.CompassPoint; cp != nil && cp.Type != CompassPointUnderscore {
+		io.WriteString(h, ":"+cp.Type.String())
+	}
+}
+
+func writeEdgeOperand(h hash.Hash, operand EdgeOperand) {
+	switch op := operand.(type) {
+	case NodeID:
+		writeNodeID(h, op)
+	case Subgraph:
+		writeSubgraph(h, op)
+	}
+	io.WriteString(h, " ")
+}
+
+func writeSubgraph(h hash.Hash, subgraph Subgraph) {
+	io.WriteString(h, "subgraph ")
+	if subgraph.ID != nil {
+		writeID(h, *subgraph.ID)
+	}
+	writeStmts(h, subgraph.Stmts)
+}
+
+// writeAttrList writes the attributes of all lists as a single list.
+func writeAttrList(h hash.Hash, attrList *AttrList) {
+	io.WriteString(h, "[")
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			writeAttribute(h, aList.Attribute)
+		}
+	}
+	io.WriteString(h, "]")
+}
+
+func writeAttribute(h hash.Hash, attribute Attribute) {
+	writeID(h, attribute.Name)
+	io.WriteString(h, "=")
+	writeID(h, attribute.Value)
+	io.WriteString(h, " ")
+}
+
+// writeID writes the ID as interpreted by Graphviz. HTML strings are marked as such as they differ
+// from a quoted string of the same content.
+func writeID(h hash.Hash, id ID) {
+	if id.IsHTML() {
+		io.WriteString(h, "<")
+	}
+	io.WriteString(h, strconv.Quote(id.Unquoted()))
+}
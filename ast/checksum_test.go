@@ -0,0 +1,85 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestChecksum(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"IgnoresFormattingAndComments": {
+			a:    `graph { A -- B; C [color=red] }`,
+			b:    "// my graph\ngraph {\n\tA -- B // edge\n\tC [\n\t\tcolor=red\n\t]\n}",
+			want: true,
+		},
+		"IgnoresQuotingAndLineContinuations": {
+			a:    `digraph "G" { "A" -> B:"p" [label="long label"] }`,
+			b:    "digraph G { A -> \"B\":p [label=\"long \\\nlabel\"] }",
+			want: true,
+		},
+		"IgnoresHowAttributesAreSplitIntoLists": {
+			a:    `graph { A [a=1] [b=2] [] }`,
+			b:    `graph { A [a=1, b=2] }`,
+			want: true,
+		},
+		"IgnoresKeywordCase": {
+			a:    `graph { node [shape=box] }`,
+			b:    `graph { NODE [shape=box] }`,
+			want: true,
+		},
+		"IgnoresDefaultCompassPoint": {
+			a:    `graph { A:p:_ -- B }`,
+			b:    `graph { A:p -- B }`,
+			want: true,
+		},
+		"AttributeValue": {
+			a: `graph { A [color=red] }`,
+			b: `graph { A [color=blue] }`,
+		},
+		"AttributeOrder": {
+			a: `graph { A [a=1, b=2] }`,
+			b: `graph { A [b=2, a=1] }`,
+		},
+		"Kind": {
+			a: `graph { A }`,
+			b: `digraph { A }`,
+		},
+		"Strict": {
+			a: `graph { A }`,
+			b: `strict graph { A }`,
+		},
+		"HTMLString": {
+			a: `graph { A [label="<b>x</b>"] }`,
+			b: `graph { A [label=<<b>x</b>>] }`,
+		},
+		"SubgraphBoundary": {
+			a: `graph { { A } B }`,
+			b: `graph { { A B } }`,
+		},
+		"StatementsAreNotSplitIntoIDs": {
+			a: `graph { A; B }`,
+			b: `graph { "A; B" }`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := dot.Parse([]byte(test.a))
+			require.NoErrorf(t, err, "Parse(%q)", test.a)
+			b, err := dot.Parse([]byte(test.b))
+			require.NoErrorf(t, err, "Parse(%q)", test.b)
+
+			got := ast.Checksum(a) == ast.Checksum(b)
+
+			assert.EqualValuesf(t, got, test.want, "Checksum(%q) == Checksum(%q)", test.a, test.b)
+			assert.EqualValuesf(t, len(ast.Checksum(a)), 16, "len(Checksum(%q))", test.a)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package ast
+
+import "github.com/teleivo/dot/token"
+
+// Clusterize groups the node statements of the graph by the value of the attribute with given name
+// into clusters. Each cluster is named cluster_ followed by the value and labeled with the value.
+// Node statements are moved into an existing cluster of that name or into a new cluster inserted at
+// the position of the first node statement moved into it. Only node statements of the root graph
+// that set the attribute themselves are moved. Edges are left as is as nodes belong to the cluster
+// they are declared in. The graph is modified in place.
+//
+// Clusterize is the inverse of contracting clusters into nodes. It turns
+//
+//	A [team=core]
+//	B [team=web]
+//	C [team=core]
+//
+// into
+//
+//	subgraph cluster_core {label=core A [team=core] C [team=core]}
+//	subgraph cluster_web {label=web B [team=web]}
+func Clusterize(g *Graph, name string) {
+	clusters := make(map[string]int) // index of the cluster in the result by its name
+	for i, stmt := range g.Stmts {
+		if subgraph, ok := stmt.(Subgraph); ok && subgraph.ID != nil {
+			if _, ok := clusters[subgraph.ID.Unquoted()]; !ok {
+				clusters[subgraph.ID.Unquoted()] = i
+			}
+		}
+	}
+
+	result := make([]Stmt, 0, len(g.Stmts))
+	moved := make(map[int]bool) // index of statements of g.Stmts moved into a cluster
+	for i, stmt := range g.Stmts {
+		ns, ok := stmt.(*NodeStmt)
+		if !ok {
+			continue
+		}
+		value, ok := attrValue(ns.AttrList, name)
+		if !ok {
+			continue
+		}
+
+		cluster := "cluster_" + value
+		if _, ok := clusters[cluster]; !ok {
+			id := NewID(cluster)
+			g.Stmts[i] = Subgraph{
+				SubgraphStart: &token.Position{},
+				ID:            &id,
+				Stmts: []Stmt{
+					Attribute{Name: ID{Literal: "label"}, Value: NewID(value)},
+				},
+			}
+			clusters[cluster] = i
+		} else {
+			moved[i] = true
+		}
+		index := clusters[cluster]
+		subgraph := g.Stmts[index].(Subgraph)
+		subgraph.Stmts = append(subgraph.Stmts, ns)
+		g.Stmts[index] = subgraph
+	}
+
+	for i, stmt := range g.Stmts {
+		if !moved[i] {
+			result = append(result, stmt)
+		}
+	}
+	g.Stmts = result
+}
+
+// attrValue returns the unquoted value of the last attribute with given name.
+func attrValue(attrList *AttrList, name string) (string, bool) {
+	var value string
+	var found bool
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			if aList.Attribute.Name.Unquoted() == name {
+				value, found = aList.Attribute.Value.Unquoted(), true
+			}
+		}
+	}
+	return value, found
+}
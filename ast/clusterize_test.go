@@ -0,0 +1,68 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestClusterize(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"WithoutAttribute": {
+			in: `digraph {
+	A -> B
+	C [color=red]
+}`,
+			want: `digraph {
+	A -> B
+	C [color=red]
+}`,
+		},
+		"GroupsNodesIntoNewClusters": {
+			in: `digraph {
+	A [team=core]
+	A -> B
+	B [team="web ui"]
+	C [team=core,shape=box]
+	D
+}`,
+			want: `digraph {
+	subgraph cluster_core {label=core A [team=core] C [team=core,shape=box]}
+	A -> B
+	subgraph "cluster_web ui" {label="web ui" B [team="web ui"]}
+	D
+}`,
+		},
+		"MovesNodesIntoExistingClusters": {
+			in: `digraph {
+	A [team=core]
+	subgraph cluster_core {
+		label="Core Team"
+		X
+	}
+	{ B [team=core] }
+}`,
+			want: `digraph {
+	subgraph cluster_core {label="Core Team" X A [team=core]}
+	subgraph {B [team=core]}
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			ast.Clusterize(&g, "team")
+
+			assert.EqualValuesf(t, g.String(), test.want, "Clusterize(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package ast
+
+import "github.com/teleivo/dot/token"
+
+// StmtComments are the comments attached to a statement.
+type StmtComments struct {
+	Leading  []Comment // Leading are the comments on their own lines directly preceding the statement.
+	Inner    []Comment // Inner are the comments in between the tokens of the statement.
+	Trailing []Comment // Trailing are the comments following the statement on its last line.
+}
+
+// CommentMap attaches the comments of a graph to the statements they document like the CommentMap
+// of go/ast. A comment is attached to a statement as
+//
+//   - leading comment if it is part of a group of comments on their own lines that directly
+//     precedes the statement without a blank line in between
+//   - inner comment if it is in between the tokens of the statement
+//   - trailing comment if it follows the statement on the line the statement ends on
+//
+// Comments in subgraphs are attached to the statements of the subgraph. All other comments are
+// standalone like comments separated from the next statement by a blank line or comments before
+// and after the graph.
+type CommentMap struct {
+	Standalone []Comment // Standalone lists the comments not attached to any statement.
+
+	stmts map[token.Position]*StmtComments // stmts maps the start of a statement to its comments
+}
+
+// NewCommentMap creates a comment map of the comments of the graph. The comments must be in the
+// order of the source like the comments of a parsed graph.
+func NewCommentMap(g Graph) CommentMap {
+	cm := CommentMap{stmts: make(map[token.Position]*StmtComments)}
+
+	comments := g.Comments
+	var i int
+	for ; i < len(comments) && comments[i].StartPos.Before(g.LeftBrace); i++ {
+		cm.Standalone = append(cm.Standalone, comments[i])
+	}
+	i = cm.attachBlock(comments, i, g.Stmts, g.RightBrace)
+	cm.Standalone = append(cm.Standalone, comments[i:]...)
+
+	return cm
+}
+
+// Comments returns the comments attached to the statement. Statements are identified by their start
+// position so the comments of a statement are found even if it has been moved or copied.
+func (cm CommentMap) Comments(stmt Stmt) StmtComments {
+	if sc, ok := cm.stmts[stmt.Start()]; ok {
+		return *sc
+	}
+	return StmtComments{}
+}
+
+// attachBlock attaches the comments starting at index i that precede the closing brace at given
+// end position to the statements of a graph or subgraph. The index of the first comment after the
+// block is returned.
+func (cm *CommentMap) attachBlock(comments []Comment, i int, stmts []Stmt, end token.Position) int {
+	var prev Stmt
+	var group []Comment // group holds the comments on their own lines that precede the next statement
+
+	// attach comments preceding given position to the previous statement or the group
+	attachUntil := func(pos token.Position) {
+		for ; i < len(comments) && comments[i].StartPos.Before(pos); i++ {
+			c := comments[i]
+			if prev != nil && len(group) == 0 && c.StartPos.Row == prev.End().Row {
+				cm.stmt(prev).Trailing = append(cm.stmt(prev).Trailing, c)
+				continue
+			}
+			// a blank line ends the group
+			if len(group) > 0 && c.StartPos.Row > group[len(group)-1].EndPos.Row+1 {
+				cm.Standalone = append(cm.Standalone, group...)
+				group = nil
+			}
+			group = append(group, c)
+		}
+	}
+
+	for _, stmt := range stmts {
+		attachUntil(stmt.Start())
+		if len(group) > 0 && group[len(group)-1].EndPos.Row+1 >= stmt.Start().Row {
+			cm.stmt(stmt).Leading = group
+		} else {
+			cm.Standalone = append(cm.Standalone, group...)
+		}
+		group = nil
+
+		subgraph, isSubgraph := stmt.(Subgraph)
+		for ; i < len(comments) && comments[i].StartPos.Before(stmt.End()); i++ {
+			if isSubgraph && subgraph.LeftBrace.Before(comments[i].StartPos) {
+				i = cm.attachBlock(comments, i, subgraph.Stmts, subgraph.RightBrace)
+				break
+			}
+			cm.stmt(stmt).Inner = append(cm.stmt(stmt).Inner, comments[i])
+		}
+		prev = stmt
+	}
+	attachUntil(end)
+	cm.Standalone = append(cm.Standalone, group...)
+
+	return i
+}
+
+// stmt returns the comments of the statement creating them if needed.
+func (cm *CommentMap) stmt(stmt Stmt) *StmtComments {
+	sc, ok := cm.stmts[stmt.Start()]
+	if !ok {
+		sc = &StmtComments{}
+		cm.stmts[stmt.Start()] = sc
+	}
+	return sc
+}
@@ -0,0 +1,80 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestCommentMap(t *testing.T) {
+	in := `// standalone before the graph
+graph {
+	// leading A
+	// still leading A
+	A // trailing A
+
+	// standalone as separated by a blank line
+
+	// leading B
+	B [color=blue, // inner B
+		style=filled] /* trailing B */ // trailing B
+	subgraph {
+		// leading C
+		C -- D // trailing C -- D
+		// standalone at the end of the subgraph
+	}
+	// standalone at the end of the graph
+}
+// standalone after the graph`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	cm := ast.NewCommentMap(g)
+
+	subgraph := g.Stmts[2].(ast.Subgraph)
+	tests := map[string]struct {
+		stmt ast.Stmt
+		want ast.StmtComments
+	}{
+		"A": {
+			stmt: g.Stmts[0],
+			want: ast.StmtComments{
+				Leading:  []ast.Comment{g.Comments[1], g.Comments[2]},
+				Trailing: []ast.Comment{g.Comments[3]},
+			},
+		},
+		"B": {
+			stmt: g.Stmts[1],
+			want: ast.StmtComments{
+				Leading:  []ast.Comment{g.Comments[5]},
+				Inner:    []ast.Comment{g.Comments[6]},
+				Trailing: []ast.Comment{g.Comments[7], g.Comments[8]},
+			},
+		},
+		"Subgraph": {
+			stmt: subgraph,
+			want: ast.StmtComments{},
+		},
+		"C -- D": {
+			stmt: subgraph.Stmts[0],
+			want: ast.StmtComments{
+				Leading:  []ast.Comment{g.Comments[9]},
+				Trailing: []ast.Comment{g.Comments[10]},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualValuesf(t, cm.Comments(test.stmt), test.want, "Comments(%s)", name)
+		})
+	}
+
+	t.Run("Standalone", func(t *testing.T) {
+		want := []ast.Comment{g.Comments[0], g.Comments[4], g.Comments[11], g.Comments[12], g.Comments[13]}
+		assert.EqualValuesf(t, cm.Standalone, want, "Standalone")
+	})
+}
@@ -0,0 +1,54 @@
+package ast
+
+// ExpandEdges expands the edge statement into edge statements declaring a single edge between two
+// node IDs each, as Graphviz interprets it. Every node of an operand is connected to every node of
+// the operand following it so A -> {B C} -> D declares the edges A -> B, A -> C, B -> D and C -> D
+// in that order. The nodes of a subgraph operand are the nodes declared in the subgraph and its
+// subgraphs in the order they first appear. They are identified by their [ID.Unquoted] value and
+// do not carry a port. Node IDs used as operands keep their port.
+//
+// The expanded statements share the attribute list of the edge statement. Expanding an edge
+// statement that declares a single edge between two node IDs returns an equal statement.
+func ExpandEdges(stmt *EdgeStmt) []*EdgeStmt {
+	tails := operandNodes(stmt.Left)
+	var result []*EdgeStmt
+	for cur := &stmt.Right; cur != nil; cur = cur.Next {
+		heads := operandNodes(cur.Right)
+		for _, tail := range tails {
+			for _, head := range heads {
+				result = append(result, &EdgeStmt{
+					Left:     tail,
+					Right:    EdgeRHS{StartPos: cur.StartPos, Directed: cur.Directed, Right: head},
+					AttrList: stmt.AttrList,
+				})
+			}
+		}
+		tails = heads
+	}
+	return result
+}
+
+// operandNodes returns the node IDs the edge operand stands for.
+func operandNodes(operand EdgeOperand) []NodeID {
+	subgraph, ok := operand.(Subgraph)
+	if !ok {
+		return []NodeID{operand.(NodeID)}
+	}
+
+	var result []NodeID
+	seen := make(map[string]bool)
+	Inspect(subgraph, func(n Node) bool {
+		switch n := n.(type) {
+		case NodeID:
+			if id := n.ID.Unquoted(); !seen[id] {
+				seen[id] = true
+				result = append(result, NodeID{ID: n.ID})
+			}
+			return false
+		case *AttrList, Attribute:
+			return false
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,72 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestExpandEdges(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []string
+	}{
+		"SingleEdge": {
+			in:   `digraph { A:p1 -> B }`,
+			want: []string{"A:p1 -> B"},
+		},
+		"Chain": {
+			in:   `graph { A -- B -- C }`,
+			want: []string{"A -- B", "B -- C"},
+		},
+		"SubgraphOperands": {
+			in:   `digraph { A -> {B C} -> D:p2 }`,
+			want: []string{"A -> B", "A -> C", "B -> D:p2", "C -> D:p2"},
+		},
+		"SubgraphNodesAreUniqueAndIncludeNestedSubgraphs": {
+			in: `digraph {
+	subgraph s {
+		node [shape=box]
+		label="B"
+		B:n -> C
+		subgraph { "B" D }
+	} -> E
+}`,
+			want: []string{"B -> E", "C -> E", "D -> E"},
+		},
+		"EmptySubgraph": {
+			in: `digraph { A -> {} }`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+			stmt := g.Stmts[0].(*ast.EdgeStmt)
+
+			var got []string
+			for _, edge := range ast.ExpandEdges(stmt) {
+				_, leftIsNode := edge.Left.(ast.NodeID)
+				_, rightIsNode := edge.Right.Right.(ast.NodeID)
+				require.Truef(t, leftIsNode && rightIsNode && edge.Right.Next == nil, "ExpandEdges(%q) returned %s", test.in, edge)
+				got = append(got, edge.Left.String()+" "+edge.Right.Op().String()+" "+edge.Right.Right.String())
+			}
+			assert.EqualValuesf(t, got, test.want, "ExpandEdges(%q)", test.in)
+		})
+	}
+
+	t.Run("SharesAttrList", func(t *testing.T) {
+		in := `digraph { A -> {B C} [color=red] }`
+		g, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+		stmt := g.Stmts[0].(*ast.EdgeStmt)
+
+		for _, edge := range ast.ExpandEdges(stmt) {
+			assert.Truef(t, edge.AttrList == stmt.AttrList, "ExpandEdges(%q) edge %s does not share the attribute list", in, edge)
+		}
+	})
+}
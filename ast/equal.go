@@ -0,0 +1,106 @@
+package ast
+
+import (
+	"cmp"
+	"slices"
+)
+
+// EqualUnordered reports whether the graphs are equal ignoring positions, comments and the order of
+// attributes of a statement. The attribute lists of a statement are merged into one as Graphviz
+// does so
+//
+//	A [a=1,b=2]
+//
+// equals
+//
+//	A [b=2] [a=1]
+//
+// An attribute that is set more than once only counts with its last value. Statements and
+// identifiers including their quotes are compared as they are.
+func EqualUnordered(a, b Graph) bool {
+	return unorderedGraph(a).String() == unorderedGraph(b).String()
+}
+
+func unorderedGraph(g Graph) Graph {
+	return Graph{
+		StrictStart: g.StrictStart,
+		Directed:    g.Directed,
+		ID:          g.ID,
+		Stmts:       unorderedStmts(g.Stmts),
+	}
+}
+
+// unorderedStmts returns copies of the statements with their attributes sorted. The statements are
+// copied so the graph is not modified.
+func unorderedStmts(stmts []Stmt) []Stmt {
+	result := make([]Stmt, 0, len(stmts))
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *NodeStmt:
+			stmt = &NodeStmt{NodeID: st.NodeID, AttrList: unorderedAttrList(st.AttrList)}
+		case *EdgeStmt:
+			es := &EdgeStmt{
+				Left:     unorderedEdgeOperand(st.Left),
+				AttrList: unorderedAttrList(st.AttrList),
+			}
+			prev := &es.Right
+			for cur := &st.Right; cur != nil; cur = cur.Next {
+				*prev = EdgeRHS{Directed: cur.Directed, Right: unorderedEdgeOperand(cur.Right)}
+				if cur.Next != nil {
+					prev.Next = &EdgeRHS{}
+					prev = prev.Next
+				}
+			}
+			stmt = es
+		case *AttrStmt:
+			as := &AttrStmt{ID: st.ID}
+			if attrList := unorderedAttrList(&st.AttrList); attrList != nil {
+				as.AttrList = *attrList
+			}
+			stmt = as
+		case Subgraph:
+			st.Stmts = unorderedStmts(st.Stmts)
+			stmt = st
+		}
+		result = append(result, stmt)
+	}
+	return result
+}
+
+func unorderedEdgeOperand(operand EdgeOperand) EdgeOperand {
+	subgraph, ok := operand.(Subgraph)
+	if !ok {
+		return operand
+	}
+	subgraph.Stmts = unorderedStmts(subgraph.Stmts)
+	return subgraph
+}
+
+// unorderedAttrList merges the attribute lists into a single one with its attributes sorted by
+// name. Nil is returned if there are no attributes.
+func unorderedAttrList(attrList *AttrList) *AttrList {
+	var attrs []Attribute
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			i := slices.IndexFunc(attrs, func(attr Attribute) bool {
+				return attr.Name.Unquoted() == aList.Attribute.Name.Unquoted()
+			})
+			if i >= 0 {
+				attrs = slices.Delete(attrs, i, i+1)
+			}
+			attrs = append(attrs, aList.Attribute)
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	slices.SortFunc(attrs, func(a, b Attribute) int {
+		return cmp.Compare(a.Name.Unquoted(), b.Name.Unquoted())
+	})
+	result := &AttrList{}
+	for i := len(attrs) - 1; i >= 0; i-- {
+		result.AList = &AList{Attribute: attrs[i], Next: result.AList}
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestEqualUnordered(t *testing.T) {
+	tests := map[string]struct {
+		a, b string
+		want bool
+	}{
+		"IgnoresWhitespaceAndComments": {
+			a:    `graph { A -- B }`,
+			b:    "graph {\n\t// edge\n\tA -- B\n}",
+			want: true,
+		},
+		"IgnoresAttributeOrder": {
+			a:    `digraph { node [shape=box,color=red] A [a=1,b=2] A -> B [label=x,color=blue] }`,
+			b:    `digraph { node [color=red,shape=box] A [b=2,a=1] A -> B [color=blue,label=x] }`,
+			want: true,
+		},
+		"MergesAttributeLists": {
+			a:    `graph { A [a=1] [b=2] [] }`,
+			b:    `graph { A [b=2,a=1] }`,
+			want: true,
+		},
+		"LastAttributeWins": {
+			a:    `graph { A [a=1,b=2,a=3] }`,
+			b:    `graph { A [a=3,b=2] }`,
+			want: true,
+		},
+		"IgnoresAttributeOrderInSubgraphs": {
+			a:    `graph { subgraph { A [a=1,b=2] } -- { B [c=3,d=4] } }`,
+			b:    `graph { subgraph { A [b=2,a=1] } -- { B [d=4,c=3] } }`,
+			want: true,
+		},
+		"DifferentAttributeValues": {
+			a:    `graph { A [a=1,b=2] }`,
+			b:    `graph { A [b=2,a=3] }`,
+			want: false,
+		},
+		"DifferentStatementOrder": {
+			a:    `graph { A B }`,
+			b:    `graph { B A }`,
+			want: false,
+		},
+		"DifferentEdges": {
+			a:    `graph { A -- B -- C }`,
+			b:    `graph { A -- B }`,
+			want: false,
+		},
+		"DifferentKind": {
+			a:    `graph { A }`,
+			b:    `strict graph { A }`,
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := dot.Parse([]byte(test.a))
+			require.NoErrorf(t, err, "Parse(%q)", test.a)
+			b, err := dot.Parse([]byte(test.b))
+			require.NoErrorf(t, err, "Parse(%q)", test.b)
+			before := a.String()
+
+			got := ast.EqualUnordered(a, b)
+
+			assert.EqualValuesf(t, got, test.want, "EqualUnordered(%q, %q)", test.a, test.b)
+			assert.EqualValuesf(t, a.String(), before, "EqualUnordered(%q, %q) modified the graph", test.a, test.b)
+		})
+	}
+}
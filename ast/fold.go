@@ -0,0 +1,192 @@
+package ast
+
+import "github.com/teleivo/dot/token"
+
+// FoldNodeStmts removes redundant node statements. A node statement like A without attributes is
+// redundant if the node is used as an edge operand in the same graph or subgraph. This shrinks
+// noisy generated code listing every node before its edges. Only g is modified. Statements are
+// copied before removing node statements so other graphs sharing them are left unchanged.
+//
+// Node statements are only removed if Graphviz interprets the graph the same way. Nodes keep the
+// order they first appear in, the subgraphs they belong to and the default attributes set by
+// node [...] statements at the point they first appear. Node statements with a port or comments
+// are kept.
+func FoldNodeStmts(g *Graph) {
+	cm := NewCommentMap(*g)
+	remove := make(map[*NodeStmt]bool)
+	foldable(g.Stmts, cm, remove)
+	if len(remove) == 0 {
+		return
+	}
+
+	// removing the statement that first declares a node delays the creation of the node to its next
+	// occurrence. Keep statements until the nodes are created in the same order and scope.
+	want := creations(*g, nil)
+	for {
+		got := creations(*g, remove)
+		i := 0
+		for i < len(want) && i < len(got) && want[i].equal(got[i]) {
+			i++
+		}
+		if i == len(want) && i == len(got) {
+			break
+		}
+		if i == len(want) || want[i].stmt == nil || !remove[want[i].stmt] {
+			return // not expected to happen, rather keep the graph as is
+		}
+		delete(remove, want[i].stmt)
+	}
+
+	g.Stmts = foldStmts(g.Stmts, remove)
+}
+
+// foldable marks the node statements that are candidates for removal. These are node statements
+// without attributes, port and comments of nodes used as edge operands in the same block.
+func foldable(stmts []Stmt, cm CommentMap, remove map[*NodeStmt]bool) {
+	operands := make(map[string]bool)
+	for _, stmt := range stmts {
+		es, ok := stmt.(*EdgeStmt)
+		if !ok {
+			continue
+		}
+		if nid, ok := es.Left.(NodeID); ok {
+			operands[nid.ID.Unquoted()] = true
+		}
+		for cur := &es.Right; cur != nil; cur = cur.Next {
+			if nid, ok := cur.Right.(NodeID); ok {
+				operands[nid.ID.Unquoted()] = true
+			}
+		}
+	}
+
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *NodeStmt:
+			comments := cm.Comments(st)
+			if operands[st.NodeID.ID.Unquoted()] && st.NodeID.Port == nil && !hasAttrs(st.AttrList) &&
+				len(comments.Leading)+len(comments.Inner)+len(comments.Trailing) == 0 {
+				remove[st] = true
+			}
+		case *EdgeStmt:
+			foldableOperand(st.Left, cm, remove)
+			for cur := &st.Right; cur != nil; cur = cur.Next {
+				foldableOperand(cur.Right, cm, remove)
+			}
+		case Subgraph:
+			foldable(st.Stmts, cm, remove)
+		}
+	}
+}
+
+func foldableOperand(operand EdgeOperand, cm CommentMap, remove map[*NodeStmt]bool) {
+	if subgraph, ok := operand.(Subgraph); ok {
+		foldable(subgraph.Stmts, cm, remove)
+	}
+}
+
+func hasAttrs(attrList *AttrList) bool {
+	for cur := attrList; cur != nil; cur = cur.Next {
+		if cur.AList != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// creation is the point at which a node is first declared.
+type creation struct {
+	id       string         // id is the unquoted ID of the node
+	block    token.Position // block is the position of the '{' of the graph or subgraph the node is declared in
+	defaults int            // defaults is the number of node attribute statements in effect
+	stmt     *NodeStmt      // stmt is the node statement declaring the node if it is declared by one
+}
+
+// equal reports whether the nodes are declared in the same scope regardless of the statement.
+func (c creation) equal(other creation) bool {
+	return c.id == other.id && c.block == other.block && c.defaults == other.defaults
+}
+
+// creations returns the points at which nodes are first declared in the order they are declared if
+// the node statements marked for removal are removed.
+func creations(g Graph, remove map[*NodeStmt]bool) []creation {
+	var result []creation
+	seen := make(map[string]bool)
+	add := func(nid NodeID, block token.Position, defaults int, stmt *NodeStmt) {
+		id := nid.ID.Unquoted()
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		result = append(result, creation{id: id, block: block, defaults: defaults, stmt: stmt})
+	}
+
+	var walk func(stmts []Stmt, block token.Position, defaults int)
+	operand := func(operand EdgeOperand, block token.Position, defaults int) {
+		switch op := operand.(type) {
+		case NodeID:
+			add(op, block, defaults, nil)
+		case Subgraph:
+			walk(op.Stmts, op.LeftBrace, defaults)
+		}
+	}
+	walk = func(stmts []Stmt, block token.Position, defaults int) {
+		for _, stmt := range stmts {
+			switch st := stmt.(type) {
+			case *AttrStmt:
+				if token.Lookup(st.ID.Literal) == token.Node {
+					defaults++
+				}
+			case *NodeStmt:
+				if !remove[st] {
+					add(st.NodeID, block, defaults, st)
+				}
+			case *EdgeStmt:
+				operand(st.Left, block, defaults)
+				for cur := &st.Right; cur != nil; cur = cur.Next {
+					operand(cur.Right, block, defaults)
+				}
+			case Subgraph:
+				walk(st.Stmts, st.LeftBrace, defaults)
+			}
+		}
+	}
+	walk(g.Stmts, g.LeftBrace, 0)
+	return result
+}
+
+func foldStmts(stmts []Stmt, remove map[*NodeStmt]bool) []Stmt {
+	result := make([]Stmt, 0, len(stmts))
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *NodeStmt:
+			if remove[st] {
+				continue
+			}
+		case *EdgeStmt:
+			edge := *st
+			edge.Left = foldEdgeOperand(st.Left, remove)
+			for cur := &edge.Right; cur != nil; cur = cur.Next {
+				cur.Right = foldEdgeOperand(cur.Right, remove)
+				if cur.Next != nil {
+					next := *cur.Next
+					cur.Next = &next
+				}
+			}
+			stmt = &edge
+		case Subgraph:
+			st.Stmts = foldStmts(st.Stmts, remove)
+			stmt = st
+		}
+		result = append(result, stmt)
+	}
+	return result
+}
+
+func foldEdgeOperand(operand EdgeOperand, remove map[*NodeStmt]bool) EdgeOperand {
+	subgraph, ok := operand.(Subgraph)
+	if !ok {
+		return operand
+	}
+	subgraph.Stmts = foldStmts(subgraph.Stmts, remove)
+	return subgraph
+}
@@ -0,0 +1,117 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestFoldNodeStmts(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"NodesListedBeforeEdges": {
+			in: `digraph { A; B; C; A -> B; B -> C }`,
+			want: `digraph {
+	A -> B
+	B -> C
+}`,
+		},
+		"NodesListedAfterEdges": {
+			in: `digraph { A -> B; A; B [] }`,
+			want: `digraph {
+	A -> B
+}`,
+		},
+		"NodesWithAttributesAreKept": {
+			in: `digraph { A [color=red]; B; A -> B }`,
+			want: `digraph {
+	A [color=red]
+	A -> B
+}`,
+		},
+		"NodesFirstDeclaredOutOfOrderAreKept": {
+			in: `digraph { A; B; A -> C }`,
+			want: `digraph {
+	A
+	B
+	A -> C
+}`,
+		},
+		"NodesWithPortsOrCommentsAreKept": {
+			in: `digraph {
+	A:p
+	// keep B
+	B
+	A -> B
+}`,
+			want: `digraph {
+	A:p
+	B
+	A -> B
+}`,
+		},
+		"ChainedEdgesWithComments": {
+			in: `digraph {
+	A
+	B
+	A -> B -> C
+	// C is documented
+	C
+}`,
+			want: `digraph {
+	A -> B -> C
+	C
+}`,
+		},
+		"OrderOfNodesIsKept": {
+			in: `digraph { A; B; B -> A }`,
+			want: `digraph {
+	A
+	B -> A
+}`,
+		},
+		"NodeDefaultsAreKept": {
+			in: `digraph { A; node [shape=box]; B -> A; C; C -> B }`,
+			want: `digraph {
+	A
+	node [shape=box]
+	B -> A
+	C -> B
+}`,
+		},
+		"SubgraphMembershipIsKept": {
+			in: `digraph { A -> B; subgraph cluster_a { A }; subgraph { B; B -> C } }`,
+			want: `digraph {
+	A -> B
+	subgraph cluster_a {A}
+	subgraph {B -> C}
+}`,
+		},
+		"EdgeOperandSubgraphs": {
+			in: `digraph { {A; A -> B} -> C }`,
+			want: `digraph {
+	subgraph {A -> B} -> C
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			original := g.String()
+
+			folded := g
+			ast.FoldNodeStmts(&folded)
+
+			assert.EqualValuesf(t, folded.String(), test.want, "FoldNodeStmts(%q)", test.in)
+			assert.EqualValuesf(t, g.String(), original, "FoldNodeStmts(%q) modified a copy of the graph", test.in)
+		})
+	}
+}
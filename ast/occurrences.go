@@ -0,0 +1,21 @@
+package ast
+
+// Occurrences maps every node of the graph to all the node IDs referring to it in the order they
+// appear in the source. Nodes are identified by their [ID.Unquoted] value so A and "A" are
+// occurrences of the same node. Node IDs of node statements and edge operands are considered
+// including ones with a port like A:n.
+func Occurrences(g Graph) map[string][]NodeID {
+	occurrences := make(map[string][]NodeID)
+	Inspect(g, func(n Node) bool {
+		switch n := n.(type) {
+		case NodeID:
+			id := n.ID.Unquoted()
+			occurrences[id] = append(occurrences[id], n)
+			return false
+		case *AttrList, Attribute:
+			return false
+		}
+		return true
+	})
+	return occurrences
+}
@@ -0,0 +1,49 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+func TestOccurrences(t *testing.T) {
+	in := `graph {
+	A [label=B]
+	"A":n -- B
+	{ B "\"C\"" } -- A:p1
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	got := ast.Occurrences(g)
+
+	type occurrence struct {
+		Literal    string
+		Start, End token.Position
+	}
+	positions := func(nids []ast.NodeID) []occurrence {
+		var result []occurrence
+		for _, nid := range nids {
+			result = append(result, occurrence{Literal: nid.ID.Literal, Start: nid.Start(), End: nid.End()})
+		}
+		return result
+	}
+
+	assert.EqualValuesf(t, len(got), 3, "Occurrences(%q)", in)
+	assert.EqualValuesf(t, positions(got["A"]), []occurrence{
+		{Literal: "A", Start: token.Position{Row: 2, Column: 2}, End: token.Position{Row: 2, Column: 2}},
+		{Literal: `"A"`, Start: token.Position{Row: 3, Column: 2}, End: token.Position{Row: 3, Column: 6}},
+		{Literal: "A", Start: token.Position{Row: 4, Column: 19}, End: token.Position{Row: 4, Column: 22}},
+	}, "Occurrences(%q)", in)
+	assert.EqualValuesf(t, positions(got["B"]), []occurrence{
+		{Literal: "B", Start: token.Position{Row: 3, Column: 11}, End: token.Position{Row: 3, Column: 11}},
+		{Literal: "B", Start: token.Position{Row: 4, Column: 4}, End: token.Position{Row: 4, Column: 4}},
+	}, "Occurrences(%q)", in)
+	assert.EqualValuesf(t, positions(got[`"C"`]), []occurrence{
+		{Literal: `"\"C\""`, Start: token.Position{Row: 4, Column: 6}, End: token.Position{Row: 4, Column: 12}},
+	}, "Occurrences(%q)", in)
+}
@@ -0,0 +1,110 @@
+package ast
+
+import "strings"
+
+// StripAttrs removes all attributes with a name starting with given prefix from the graph. The
+// prefix is matched against the attribute name without its quotes so attributes like
+// "x-owner"=alice can be removed using the prefix x-. Attribute lists and attribute statements that
+// are left without any attribute are removed as well. Only g is modified. Statements and attribute
+// lists are copied before removing attributes so other graphs sharing them are left unchanged.
+func StripAttrs(g *Graph, prefix string) {
+	g.Stmts = stripStmts(g.Stmts, prefix)
+}
+
+func stripStmts(stmts []Stmt, prefix string) []Stmt {
+	result := make([]Stmt, 0, len(stmts))
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case Attribute:
+			if hasNamePrefix(st, prefix) {
+				continue
+			}
+		case *NodeStmt:
+			node := *st
+			node.AttrList = stripAttrList(st.AttrList, prefix)
+			stmt = &node
+		case *EdgeStmt:
+			edge := *st
+			edge.Left = stripEdgeOperand(st.Left, prefix)
+			for cur := &edge.Right; cur != nil; cur = cur.Next {
+				cur.Right = stripEdgeOperand(cur.Right, prefix)
+				if cur.Next != nil {
+					next := *cur.Next
+					cur.Next = &next
+				}
+			}
+			edge.AttrList = stripAttrList(st.AttrList, prefix)
+			stmt = &edge
+		case *AttrStmt:
+			attrList := stripAttrList(&st.AttrList, prefix)
+			if attrList == nil {
+				continue
+			}
+			attr := *st
+			attr.AttrList = *attrList
+			stmt = &attr
+		case Subgraph:
+			st.Stmts = stripStmts(st.Stmts, prefix)
+			stmt = st
+		}
+		result = append(result, stmt)
+	}
+	return result
+}
+
+func stripEdgeOperand(operand EdgeOperand, prefix string) EdgeOperand {
+	subgraph, ok := operand.(Subgraph)
+	if !ok {
+		return operand
+	}
+	subgraph.Stmts = stripStmts(subgraph.Stmts, prefix)
+	return subgraph
+}
+
+// stripAttrList returns a copy of the attribute list without the attributes matching the prefix.
+// Nil is returned if all attributes of the list have been removed. Lists that did not contain any
+// attribute like [] are kept.
+func stripAttrList(attrList *AttrList, prefix string) *AttrList {
+	var result, last *AttrList
+	var stripped, remaining bool
+	for cur := attrList; cur != nil; cur = cur.Next {
+		list := *cur
+		list.AList, list.Next = nil, nil
+		var lastAList *AList
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			if hasNamePrefix(aList.Attribute, prefix) {
+				stripped = true
+				continue
+			}
+
+			remaining = true
+			kept := &AList{Attribute: aList.Attribute}
+			if lastAList == nil {
+				list.AList = kept
+			} else {
+				lastAList.Next = kept
+			}
+			lastAList = kept
+		}
+
+		if last == nil {
+			result = &list
+		} else {
+			last.Next = &list
+		}
+		last = &list
+	}
+
+	if stripped && !remaining {
+		return nil
+	}
+	return result
+}
+
+func hasNamePrefix(attr Attribute, prefix string) bool {
+	name := attr.Name.Literal
+	if attr.Name.IsQuoted() {
+		name = strings.TrimSuffix(name[1:], `"`)
+	}
+	return strings.HasPrefix(name, prefix)
+}
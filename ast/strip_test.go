@@ -0,0 +1,80 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestStripAttrs(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		prefix string
+		want   string
+	}{
+		"NoMatchingAttributes": {
+			in:     `graph { A [color=blue] }`,
+			prefix: "x-",
+			want: `graph {
+	A [color=blue]
+}`,
+		},
+		"AttributeStmts": {
+			in:     `graph { "x-owner"=alice; label=G; "x-link"="https://example.com" }`,
+			prefix: "x-",
+			want: `graph {
+	label=G
+}`,
+		},
+		"NodeStmts": {
+			in:     `graph { A ["x-owner"=alice, color=blue] ["x-link"=a] B ["x-owner"=bob] C [] }`,
+			prefix: "x-",
+			want: `graph {
+	A [color=blue] []
+	B
+	C []
+}`,
+		},
+		"EdgeStmts": {
+			in:     `graph { A -- B ["x-owner"=alice] {C ["x-owner"=bob]} -- D [color=red;"x-link"=a] }`,
+			prefix: "x-",
+			want: `graph {
+	A -- B
+	subgraph {C} -- D [color=red]
+}`,
+		},
+		"AttrStmts": {
+			in:     `graph { node ["x-owner"=alice] edge [color=red,"x-link"=a] graph [] }`,
+			prefix: "x-",
+			want: `graph {
+	edge [color=red]
+	graph []
+}`,
+		},
+		"Subgraphs": {
+			in:     `graph { subgraph cluster { "x-owner"=alice; A ["x-owner"=alice] } }`,
+			prefix: "x-",
+			want: `graph {
+	subgraph cluster {A}
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			original := g.String()
+
+			stripped := g
+			ast.StripAttrs(&stripped, test.prefix)
+
+			assert.EqualValuesf(t, stripped.String(), test.want, "StripAttrs(%q, %q)", test.in, test.prefix)
+			assert.EqualValuesf(t, g.String(), original, "StripAttrs(%q, %q) modified a copy of the graph", test.in, test.prefix)
+		})
+	}
+}
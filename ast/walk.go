@@ -0,0 +1,73 @@
+package ast
+
+// Inspect traverses the AST in depth-first order starting at given node. It calls f(node) for every
+// node in the order they appear in the source. The children of a node are only traversed if
+// f(node) returns true. Comments are not traversed, use [Graph.Comments] to access them.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case Graph:
+		if n.ID != nil {
+			Inspect(*n.ID, f)
+		}
+		for _, stmt := range n.Stmts {
+			Inspect(stmt, f)
+		}
+	case *NodeStmt:
+		Inspect(n.NodeID, f)
+		if n.AttrList != nil {
+			Inspect(n.AttrList, f)
+		}
+	case NodeID:
+		Inspect(n.ID, f)
+		if n.Port != nil {
+			Inspect(*n.Port, f)
+		}
+	case Port:
+		if n.Name != nil {
+			Inspect(*n.Name, f)
+		}
+	case *EdgeStmt:
+		Inspect(n.Left, f)
+		Inspect(n.Right, f)
+		if n.AttrList != nil {
+			Inspect(n.AttrList, f)
+		}
+	case EdgeRHS:
+		Inspect(n.Right, f)
+		if n.Next != nil {
+			Inspect(*n.Next, f)
+		}
+	case *AttrStmt:
+		Inspect(n.ID, f)
+		Inspect(&n.AttrList, f)
+	case AttrStmt:
+		Inspect(n.ID, f)
+		Inspect(&n.AttrList, f)
+	case *AttrList:
+		if n.AList != nil {
+			Inspect(n.AList, f)
+		}
+		if n.Next != nil {
+			Inspect(n.Next, f)
+		}
+	case *AList:
+		Inspect(n.Attribute, f)
+		if n.Next != nil {
+			Inspect(n.Next, f)
+		}
+	case Attribute:
+		Inspect(n.Name, f)
+		Inspect(n.Value, f)
+	case Subgraph:
+		if n.ID != nil {
+			Inspect(*n.ID, f)
+		}
+		for _, stmt := range n.Stmts {
+			Inspect(stmt, f)
+		}
+	}
+}
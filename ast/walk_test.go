@@ -0,0 +1,83 @@
+package ast_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+func TestInspect(t *testing.T) {
+	in := `digraph G {
+	node [shape=box]
+	A:p1:n [color=red]
+	A -> subgraph S { B } -> C
+	label=L
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	t.Run("AllNodes", func(t *testing.T) {
+		var got []string
+		ast.Inspect(g, func(n ast.Node) bool {
+			got = append(got, fmt.Sprintf("%T", n))
+			return true
+		})
+
+		want := []string{
+			"ast.Graph",
+			"ast.ID", // G
+			"*ast.AttrStmt",
+			"ast.ID", // node
+			"*ast.AttrList",
+			"*ast.AList",
+			"ast.Attribute",
+			"ast.ID", // shape
+			"ast.ID", // box
+			"*ast.NodeStmt",
+			"ast.NodeID",
+			"ast.ID", // A
+			"ast.Port",
+			"ast.ID", // p1
+			"*ast.AttrList",
+			"*ast.AList",
+			"ast.Attribute",
+			"ast.ID", // color
+			"ast.ID", // red
+			"*ast.EdgeStmt",
+			"ast.NodeID",
+			"ast.ID", // A
+			"ast.EdgeRHS",
+			"ast.Subgraph",
+			"ast.ID", // S
+			"*ast.NodeStmt",
+			"ast.NodeID",
+			"ast.ID", // B
+			"ast.EdgeRHS",
+			"ast.NodeID",
+			"ast.ID", // C
+			"ast.Attribute",
+			"ast.ID", // label
+			"ast.ID", // L
+		}
+		assert.EqualValuesf(t, got, want, "Inspect(%q)", in)
+	})
+
+	t.Run("SkipChildren", func(t *testing.T) {
+		var got []string
+		ast.Inspect(g, func(n ast.Node) bool {
+			if id, ok := n.(ast.ID); ok {
+				got = append(got, id.Literal)
+			}
+			_, isSubgraph := n.(ast.Subgraph)
+			_, isAttrList := n.(*ast.AttrList)
+			return !isSubgraph && !isAttrList
+		})
+
+		want := []string{"G", "node", "A", "p1", "A", "C", "label", "L"}
+		assert.EqualValuesf(t, got, want, "Inspect(%q)", in)
+	})
+}
@@ -0,0 +1,115 @@
+// Package attr provides typed access to the attributes of a graph applying the default values of
+// https://graphviz.org/doc/info/attrs.html.
+package attr
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	dotcolor "github.com/teleivo/dot/color"
+	"github.com/teleivo/dot/token"
+)
+
+// graphDefaults are the Graphviz default values of graph attributes. Attributes without a default
+// like label default to an empty string.
+var graphDefaults = map[string]string{
+	"center":      "false",
+	"charset":     "UTF-8",
+	"clusterrank": "local",
+	"compound":    "false",
+	"concentrate": "false",
+	"dpi":         "96",
+	"fontcolor":   "black",
+	"fontname":    "Times-Roman",
+	"fontsize":    "14",
+	"forcelabels": "true",
+	"labeljust":   "c",
+	"labelloc":    "b",
+	"landscape":   "false",
+	"mclimit":     "1",
+	"newrank":     "false",
+	"nodesep":     "0.25",
+	"outputorder": "breadthfirst",
+	"pad":         "0.0555",
+	"rankdir":     "TB",
+	"ranksep":     "0.5",
+	"remincross":  "true",
+	"rotate":      "0",
+	"searchsize":  "30",
+}
+
+// Value is the value of an attribute.
+type Value struct {
+	Name    string // Name is the name of the attribute.
+	Literal string // Literal is the unquoted value of the attribute or its default if it is not set.
+	IsSet   bool   // IsSet indicates that the attribute is set instead of having its default value.
+}
+
+// Graph returns the value of the graph attribute with given name. Graph attributes are set using
+// attribute statements like rankdir=LR or graph [rankdir=LR] in the root graph. The last one set
+// wins. The Graphviz default is returned if the attribute is not set.
+func Graph(g ast.Graph, name string) Value {
+	value := Value{Name: name, Literal: graphDefaults[name]}
+	for _, stmt := range g.Stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			if st.Name.Unquoted() == name {
+				value.Literal, value.IsSet = st.Value.Unquoted(), true
+			}
+		case *ast.AttrStmt:
+			if token.Lookup(st.ID.Literal) != token.Graph {
+				continue
+			}
+			for cur := &st.AttrList; cur != nil; cur = cur.Next {
+				for aList := cur.AList; aList != nil; aList = aList.Next {
+					if aList.Attribute.Name.Unquoted() == name {
+						value.Literal, value.IsSet = aList.Attribute.Value.Unquoted(), true
+					}
+				}
+			}
+		}
+	}
+	return value
+}
+
+// String returns the value as is.
+func (v Value) String() string {
+	return v.Literal
+}
+
+// Bool returns the value as boolean. Graphviz considers true and yes as well as any non-zero
+// integer to be true. An empty value is false.
+func (v Value) Bool() (bool, error) {
+	switch strings.ToLower(v.Literal) {
+	case "true", "yes":
+		return true, nil
+	case "false", "no", "":
+		return false, nil
+	}
+	n, err := strconv.Atoi(v.Literal)
+	if err != nil {
+		return false, fmt.Errorf("attribute %s: %q is not a bool", v.Name, v.Literal)
+	}
+	return n != 0, nil
+}
+
+// Float returns the value as floating-point number.
+func (v Value) Float() (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(v.Literal), 64)
+	if err != nil {
+		return 0, fmt.Errorf("attribute %s: %q is not a number", v.Name, v.Literal)
+	}
+	return f, nil
+}
+
+// Color returns the value as color as parsed by [dotcolor.Parse].
+func (v Value) Color() (color.RGBA, error) {
+	c, err := dotcolor.Parse(v.Literal)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("attribute %s: %v", v.Name, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,98 @@
+package attr_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/attr"
+	"github.com/teleivo/dot/internal/assertx"
+)
+
+func TestGraph(t *testing.T) {
+	in := `digraph {
+	rankdir=LR
+	graph [nodesep=1, bgcolor="#ff0000", rankdir=BT] [compound=yes]
+	subgraph cluster_a { fontsize=20 }
+	node [fontsize=10]
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	tests := map[string]attr.Value{
+		"rankdir":  {Name: "rankdir", Literal: "BT", IsSet: true},
+		"nodesep":  {Name: "nodesep", Literal: "1", IsSet: true},
+		"fontsize": {Name: "fontsize", Literal: "14"},
+		"label":    {Name: "label"},
+	}
+
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := attr.Graph(g, name)
+
+			assert.EqualValuesf(t, got, want, "Graph(%q)", name)
+		})
+	}
+
+	t.Run("Bool", func(t *testing.T) {
+		got, err := attr.Graph(g, "compound").Bool()
+
+		require.NoErrorf(t, err, "Graph(%q).Bool()", "compound")
+		assert.Truef(t, got, "Graph(%q).Bool()", "compound")
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		got, err := attr.Graph(g, "fontsize").Float()
+
+		require.NoErrorf(t, err, "Graph(%q).Float()", "fontsize")
+		assert.EqualValuesf(t, got, 14.0, "Graph(%q).Float()", "fontsize")
+	})
+
+	t.Run("Color", func(t *testing.T) {
+		got, err := attr.Graph(g, "bgcolor").Color()
+
+		require.NoErrorf(t, err, "Graph(%q).Color()", "bgcolor")
+		assert.EqualValuesf(t, got, color.RGBA{R: 255, A: 255}, "Graph(%q).Color()", "bgcolor")
+	})
+}
+
+func TestValue(t *testing.T) {
+	t.Run("Bool", func(t *testing.T) {
+		tests := map[string]bool{
+			"true":  true,
+			"Yes":   true,
+			"2":     true,
+			"false": false,
+			"NO":    false,
+			"0":     false,
+			"":      false,
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				got, err := attr.Value{Literal: in}.Bool()
+
+				require.NoErrorf(t, err, "Bool(%q)", in)
+				assert.EqualValuesf(t, got, want, "Bool(%q)", in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		v := attr.Value{Name: "center", Literal: "maybe"}
+
+		_, err := v.Bool()
+		require.NotNilf(t, err, "Bool(%q)", v.Literal)
+		assertx.Contains(t, err.Error(), `attribute center: "maybe" is not a bool`)
+
+		_, err = v.Float()
+		require.NotNilf(t, err, "Float(%q)", v.Literal)
+		assertx.Contains(t, err.Error(), `attribute center: "maybe" is not a number`)
+
+		_, err = v.Color()
+		require.NotNilf(t, err, "Color(%q)", v.Literal)
+		assertx.Contains(t, err.Error(), "unknown color name")
+	})
+}
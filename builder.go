@@ -0,0 +1,146 @@
+package dot
+
+import (
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// GraphKind defines whether a graph built by a [GraphBuilder] is directed.
+type GraphKind int
+
+const (
+	Undirected GraphKind = iota // Undirected builds a graph connecting nodes using '--'.
+	Directed                    // Directed builds a digraph connecting nodes using '->'.
+)
+
+// Attr creates an attribute with given name and value. The name and value are quoted if needed.
+func Attr(name, value string) ast.Attribute {
+	return ast.Attribute{Name: ast.NewID(name), Value: ast.NewID(value)}
+}
+
+// GraphBuilder builds graphs from Go code. Statements are added in the order of the calls. Build
+// the graph using [GraphBuilder.Build] and print it using the
+// [github.com/teleivo/dot/printer.Printer]. All identifiers are quoted if needed.
+//
+//	g := dot.NewGraphBuilder(dot.Directed).
+//		Node("a", dot.Attr("shape", "box")).
+//		Edge("a", "b").
+//		Build()
+type GraphBuilder struct {
+	graph ast.Graph
+	stmts *[]ast.Stmt     // stmts points to the statements of the graph or subgraph being built
+	ids   map[string]bool // ids holds the IDs of the nodes of the graph including its subgraphs
+}
+
+// NewGraphBuilder creates a builder for a graph of given kind.
+func NewGraphBuilder(kind GraphKind) *GraphBuilder {
+	b := &GraphBuilder{graph: ast.Graph{Directed: kind == Directed}, ids: make(map[string]bool)}
+	b.stmts = &b.graph.Stmts
+	return b
+}
+
+// ID sets the identifier of the graph.
+func (b *GraphBuilder) ID(id string) *GraphBuilder {
+	graphID := ast.NewID(id)
+	b.graph.ID = &graphID
+	return b
+}
+
+// Strict makes the graph strict.
+func (b *GraphBuilder) Strict() *GraphBuilder {
+	b.graph.StrictStart = &token.Position{}
+	return b
+}
+
+// Attr adds an attribute statement like rankdir=LR setting an attribute of the graph or subgraph.
+func (b *GraphBuilder) Attr(name, value string) *GraphBuilder {
+	*b.stmts = append(*b.stmts, Attr(name, value))
+	return b
+}
+
+// NodeAttrs adds a node attribute statement like node [shape=box] setting the default attributes
+// of the nodes that follow.
+func (b *GraphBuilder) NodeAttrs(attrs ...ast.Attribute) *GraphBuilder {
+	return b.attrStmt("node", attrs)
+}
+
+// EdgeAttrs adds an edge attribute statement like edge [color=red] setting the default attributes
+// of the edges that follow.
+func (b *GraphBuilder) EdgeAttrs(attrs ...ast.Attribute) *GraphBuilder {
+	return b.attrStmt("edge", attrs)
+}
+
+func (b *GraphBuilder) attrStmt(kind string, attrs []ast.Attribute) *GraphBuilder {
+	stmt := &ast.AttrStmt{ID: ast.ID{Literal: kind}}
+	if attrList := newAttrList(attrs); attrList != nil {
+		stmt.AttrList = *attrList
+	}
+	*b.stmts = append(*b.stmts, stmt)
+	return b
+}
+
+// UniqueID returns an ID that is not used by any node of the graph as described by
+// [ast.UniqueID]. The ID is reserved so the next call returns a different one. Use it to add
+// synthetic nodes like a legend to a graph.
+func (b *GraphBuilder) UniqueID(base string) string {
+	id := ast.UniqueID(base, func(id string) bool {
+		return b.ids[id]
+	})
+	b.ids[id] = true
+	return id
+}
+
+// Node adds a node statement.
+func (b *GraphBuilder) Node(id string, attrs ...ast.Attribute) *GraphBuilder {
+	b.ids[id] = true
+	*b.stmts = append(*b.stmts, &ast.NodeStmt{
+		NodeID:   ast.NodeID{ID: ast.NewID(id)},
+		AttrList: newAttrList(attrs),
+	})
+	return b
+}
+
+// Edge adds an edge statement connecting the tail to the head node.
+func (b *GraphBuilder) Edge(tail, head string, attrs ...ast.Attribute) *GraphBuilder {
+	b.ids[tail] = true
+	b.ids[head] = true
+	*b.stmts = append(*b.stmts, &ast.EdgeStmt{
+		Left: ast.NodeID{ID: ast.NewID(tail)},
+		Right: ast.EdgeRHS{
+			Directed: b.graph.Directed,
+			Right:    ast.NodeID{ID: ast.NewID(head)},
+		},
+		AttrList: newAttrList(attrs),
+	})
+	return b
+}
+
+// Subgraph adds a subgraph with given identifier. Its statements are added by f using the builder
+// passed to it. The identifier is optional. Prefix it with cluster to build a cluster.
+func (b *GraphBuilder) Subgraph(id string, f func(*GraphBuilder)) *GraphBuilder {
+	subgraph := ast.Subgraph{SubgraphStart: &token.Position{}}
+	if id != "" {
+		subgraphID := ast.NewID(id)
+		subgraph.ID = &subgraphID
+	}
+	f(&GraphBuilder{graph: ast.Graph{Directed: b.graph.Directed}, stmts: &subgraph.Stmts, ids: b.ids})
+	*b.stmts = append(*b.stmts, subgraph)
+	return b
+}
+
+// Build returns the graph.
+func (b *GraphBuilder) Build() ast.Graph {
+	return b.graph
+}
+
+func newAttrList(attrs []ast.Attribute) *ast.AttrList {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	result := &ast.AttrList{}
+	for i := len(attrs) - 1; i >= 0; i-- {
+		result.AList = &ast.AList{Attribute: attrs[i], Next: result.AList}
+	}
+	return result
+}
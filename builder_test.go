@@ -0,0 +1,68 @@
+package dot_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/printer"
+)
+
+func TestGraphBuilder(t *testing.T) {
+	g := dot.NewGraphBuilder(dot.Directed).
+		ID("build deps").
+		Strict().
+		Attr("rankdir", "LR").
+		NodeAttrs(dot.Attr("shape", "box")).
+		Node("a", dot.Attr("label", "module a"), dot.Attr("color", "red")).
+		Subgraph("cluster_std", func(b *dot.GraphBuilder) {
+			b.Attr("label", "std").
+				Node("io").
+				Edge("io", "errors")
+		}).
+		EdgeAttrs().
+		Edge("a", "io", dot.Attr("style", "dashed")).
+		Build()
+
+	var got strings.Builder
+	p := printer.NewPrinter(strings.NewReader(g.String()), &got)
+	err := p.Print()
+	require.NoErrorf(t, err, "Print(%q)", g.String())
+
+	want := `strict digraph "build deps" {
+	rankdir=LR
+	node [shape=box]
+	a [
+		label="module a"
+		color=red
+	]
+	subgraph cluster_std {
+		label=std
+		io
+		io -> errors
+	}
+	edge []
+	a -> io [style=dashed]
+}`
+	if got.String() != want {
+		t.Errorf("\n\ngot:\n%s\n\n\nwant:\n%s\n", got.String(), want)
+	}
+}
+
+func TestGraphBuilderUniqueID(t *testing.T) {
+	b := dot.NewGraphBuilder(dot.Undirected).
+		Node("legend").
+		Subgraph("", func(b *dot.GraphBuilder) {
+			b.Edge("legend_1", "a")
+		})
+
+	got := []string{b.UniqueID("legend"), b.UniqueID("legend"), b.UniqueID("a"), b.UniqueID("b")}
+
+	want := []string{"legend_2", "legend_3", "a_1", "b"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("UniqueID() call %d got %q want %q instead", i, got[i], want[i])
+		}
+	}
+}
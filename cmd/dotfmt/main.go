@@ -1,21 +1,253 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
+	"github.com/teleivo/dot/diagnostic"
+	"github.com/teleivo/dot/internal/diff"
+	"github.com/teleivo/dot/internal/version"
 	"github.com/teleivo/dot/printer"
 )
 
 func main() {
-	if err := run(os.Stdin, os.Stdout); err != nil {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run(r io.Reader, w io.Writer) error {
-	p := printer.NewPrinter(r, w)
-	return p.Print()
+func run(args []string, r io.Reader, w io.Writer, wErr io.Writer) (err error) {
+	flags := flag.NewFlagSet("dotfmt", flag.ContinueOnError)
+	flags.SetOutput(wErr)
+	flags.Usage = func() {
+		fmt.Fprintf(wErr, "usage: dotfmt [flags] [path ...]\n\nFormats dot source code read from stdin or the given files.\n\n")
+		flags.PrintDefaults()
+	}
+	write := flags.Bool("w", false, "write result to (source) file instead of stdout")
+	backup := flags.String("backup", "", "keep a copy of the original file with given suffix like .orig when writing files using -w")
+	list := flags.Bool("l", false, "list files whose formatting differs from dotfmt's")
+	showDiff := flags.Bool("d", false, "display diffs instead of rewriting files")
+	indent := flags.Int("indent", 0, "indent using given number of spaces instead of tabs")
+	maxColumn := flags.Int("maxcolumn", 100, "break up lines after given number of columns")
+	fitAttrs := flags.Bool("fitattrs", false, "keep attribute lists on a single line if they fit")
+	maxAttrs := flags.Int("maxattrs", 0, "break up attribute lists with more than given number of attributes when using -fitattrs")
+	alignAttrs := flags.Bool("alignattrs", false, "align the '=' of attributes on multiple lines")
+	semicolons := flags.Bool("semicolons", false, "terminate every statement by a ';'")
+	compact := flags.Bool("compact", false, "remove node statements without attributes of nodes used in edges")
+	eol := flags.String("eol", "lf", "end lines with lf, crlf or the line ending of the input using preserve")
+	debugLayout := flags.String("debug-layout", "", "write a JSON trace of why lines were broken up to given file")
+	printVersion := flags.Bool("version", false, "print version and build information and exit")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *printVersion {
+		info := version.Read(fmt.Sprintf("canonical=%d", printer.CanonicalVersion))
+		_, err := io.WriteString(w, info.Describe("dotfmt"))
+		return err
+	}
+
+	lineEnding, ok := lineEndings[*eol]
+	if !ok {
+		return fmt.Errorf("invalid -eol %q: must be one of lf, crlf or preserve", *eol)
+	}
+
+	// style flags deviate from the canonical form of the printer defaults
+	opts := []printer.Option{printer.WithIndent(*indent), printer.WithMaxColumn(*maxColumn), printer.WithLineEnding(lineEnding), printer.WithFinalNewline()}
+	if *fitAttrs {
+		opts = append(opts, printer.WithAttrLists(printer.AttrListsFit), printer.WithMaxAttrsPerLine(*maxAttrs))
+	}
+	if *alignAttrs {
+		opts = append(opts, printer.WithAlignedAttrs())
+	}
+	if *semicolons {
+		opts = append(opts, printer.WithSemicolons(printer.SemicolonsAlways))
+	}
+	if *compact {
+		opts = append(opts, printer.WithCompact())
+	}
+
+	var trace *[]layoutBreak
+	if *debugLayout != "" {
+		trace = &[]layoutBreak{}
+		defer func() {
+			if err == nil {
+				err = writeTrace(*debugLayout, *trace)
+			}
+		}()
+	}
+
+	if flags.NArg() == 0 {
+		if *write {
+			return errors.New("cannot use -w with standard input")
+		}
+		src, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if !*list && !*showDiff {
+			return format(src, "", w, wErr, opts, trace)
+		}
+		var out bytes.Buffer
+		if err := format(src, "", &out, wErr, opts, trace); err != nil {
+			return err
+		}
+		return report(src, out.Bytes(), "<standard input>", *list, *showDiff, w)
+	}
+
+	for _, path := range flags.Args() {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !*write && !*list && !*showDiff {
+			if err := format(src, path, w, wErr, opts, trace); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var out bytes.Buffer
+		if err := format(src, path, &out, wErr, opts, trace); err != nil {
+			return err
+		}
+		if bytes.Equal(src, out.Bytes()) {
+			continue
+		}
+		if err := report(src, out.Bytes(), path, *list, *showDiff, w); err != nil {
+			return err
+		}
+		if !*write {
+			continue
+		}
+		if *backup != "" {
+			if err := writeFile(path+*backup, src, path); err != nil {
+				return err
+			}
+		}
+		if err := writeFile(path, out.Bytes(), path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lineEndings maps the values of the -eol flag to the line ending of the printer.
+var lineEndings = map[string]printer.LineEnding{
+	"lf":       printer.LineEndingLF,
+	"crlf":     printer.LineEndingCRLF,
+	"preserve": printer.LineEndingPreserve,
+}
+
+// report writes the name of the file if list is set and the diff of the formatted source if
+// showDiff is set. Nothing is written if the source is already formatted.
+func report(src, formatted []byte, name string, list, showDiff bool, w io.Writer) error {
+	if bytes.Equal(src, formatted) {
+		return nil
+	}
+	if list {
+		if _, err := fmt.Fprintln(w, name); err != nil {
+			return err
+		}
+	}
+	if showDiff {
+		if _, err := w.Write(diff.Diff(name+".orig", src, name, formatted)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// format formats the source code to w. The line breaks are appended to the trace if it is not nil.
+func format(src []byte, filename string, w io.Writer, wErr io.Writer, opts []printer.Option, trace *[]layoutBreak) error {
+	var breaks []printer.Break
+	if trace != nil {
+		opts = append(opts[:len(opts):len(opts)], printer.WithBreaks(&breaks))
+	}
+	p := printer.NewPrinter(bytes.NewReader(src), w, opts...)
+	err := p.Print()
+	if trace != nil {
+		for _, b := range breaks {
+			*trace = append(*trace, layoutBreak{
+				Filename:     filename,
+				Line:         b.Output.Row,
+				Column:       b.Output.Column,
+				SourceLine:   b.Source.Row,
+				SourceColumn: b.Source.Column,
+				Construct:    b.Construct.String(),
+				Reason:       b.Reason.String(),
+			})
+		}
+	}
+
+	if d, ok := diagnostic.FromError(err); ok {
+		if werr := diagnostic.Write(wErr, []diagnostic.Diagnostic{d}, src, diagnostic.Options{Filename: filename}); werr != nil {
+			return err
+		}
+		return errors.New("failed to format due to syntax errors")
+	}
+	return err
+}
+
+// layoutBreak is a line break of the printer as written by -debug-layout. Line and column are the
+// position in the output of the last rune before the line break.
+type layoutBreak struct {
+	Filename     string `json:"filename,omitempty"`
+	Line         int    `json:"line"`
+	Column       int    `json:"column"`
+	SourceLine   int    `json:"sourceLine"`
+	SourceColumn int    `json:"sourceColumn"`
+	Construct    string `json:"construct"`
+	Reason       string `json:"reason"`
+}
+
+// writeTrace writes the line breaks as a JSON array to the file at path.
+func writeTrace(path string, trace []layoutBreak) error {
+	b, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), 0o644)
+}
+
+// writeFile atomically writes data to the file at path with the permissions of the file at
+// permPath. The data is written to a temporary file in the same directory which is then renamed to
+// path. An interruption thus leaves either the previous or the new file but never a truncated one.
+func writeFile(path string, data []byte, permPath string) (err error) {
+	info, err := os.Stat(permPath)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
 }
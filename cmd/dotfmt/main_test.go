@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/printer"
+)
+
+const (
+	unformatted = "graph{a--b}"
+	formatted   = "graph {\n\ta -- b\n}\n"
+)
+
+func TestRun(t *testing.T) {
+	tests := map[string]struct {
+		files     map[string]string // files are created in a temporary directory $DIR before the run
+		stdin     string
+		args      []string // args are passed to run with $DIR replaced by the temporary directory
+		want      string   // want is the output written to stdout with the temporary directory replaced by $DIR
+		wantErr   string   // wantErr is the error returned by run
+		wantFiles map[string]string
+	}{
+		"Stdin": {
+			stdin: unformatted,
+			want:  formatted,
+		},
+		"File": {
+			files:     map[string]string{"g.dot": unformatted},
+			args:      []string{"$DIR/g.dot"},
+			want:      formatted,
+			wantFiles: map[string]string{"g.dot": unformatted},
+		},
+		"Write": {
+			files:     map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+			args:      []string{"-w", "$DIR/g.dot", "$DIR/formatted.dot"},
+			wantFiles: map[string]string{"g.dot": formatted, "formatted.dot": formatted},
+		},
+		"WriteStdin": {
+			stdin:   unformatted,
+			args:    []string{"-w"},
+			wantErr: "cannot use -w with standard input",
+		},
+		"WriteWithBackup": {
+			files: map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+			args:  []string{"-w", "-backup", ".orig", "$DIR/g.dot", "$DIR/formatted.dot"},
+			wantFiles: map[string]string{
+				"g.dot":         formatted,
+				"g.dot.orig":    unformatted,
+				"formatted.dot": formatted,
+			},
+		},
+		"List": {
+			files:     map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+			args:      []string{"-l", "$DIR/g.dot", "$DIR/formatted.dot"},
+			want:      "$DIR/g.dot\n",
+			wantFiles: map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+		},
+		"ListStdin": {
+			stdin: unformatted,
+			args:  []string{"-l"},
+			want:  "<standard input>\n",
+		},
+		"ListAndWrite": {
+			files:     map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+			args:      []string{"-l", "-w", "$DIR/g.dot", "$DIR/formatted.dot"},
+			want:      "$DIR/g.dot\n",
+			wantFiles: map[string]string{"g.dot": formatted, "formatted.dot": formatted},
+		},
+		"Diff": {
+			files: map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+			args:  []string{"-d", "$DIR/g.dot", "$DIR/formatted.dot"},
+			want: `diff $DIR/g.dot.orig $DIR/g.dot
+--- $DIR/g.dot.orig
++++ $DIR/g.dot
+@@ -1,1 +1,3 @@
+-graph{a--b}
+\ No newline at end of file
++graph {
++	a -- b
++}
+`,
+			wantFiles: map[string]string{"g.dot": unformatted, "formatted.dot": formatted},
+		},
+		"LineEndingCRLF": {
+			stdin: unformatted,
+			args:  []string{"-eol", "crlf"},
+			want:  "graph {\r\n\ta -- b\r\n}\r\n",
+		},
+		"LineEndingPreserved": {
+			stdin: "graph {\r\na--b\r\n}",
+			args:  []string{"-eol", "preserve"},
+			want:  "graph {\r\n\ta -- b\r\n}\r\n",
+		},
+		"ListFormattedWithCRLF": {
+			files:     map[string]string{"g.dot": "graph {\r\n\ta -- b\r\n}\r\n"},
+			args:      []string{"-l", "-eol", "preserve", "$DIR/g.dot"},
+			wantFiles: map[string]string{"g.dot": "graph {\r\n\ta -- b\r\n}\r\n"},
+		},
+		"ListMissingFinalNewline": {
+			files:     map[string]string{"g.dot": "graph {\n\ta -- b\n}"},
+			args:      []string{"-l", "$DIR/g.dot"},
+			want:      "$DIR/g.dot\n",
+			wantFiles: map[string]string{"g.dot": "graph {\n\ta -- b\n}"},
+		},
+		"WriteWithCRLF": {
+			files:     map[string]string{"g.dot": "graph{\r\na--b\r\n}\r\n"},
+			args:      []string{"-w", "-eol", "preserve", "$DIR/g.dot"},
+			wantFiles: map[string]string{"g.dot": "graph {\r\n\ta -- b\r\n}\r\n"},
+		},
+		"InvalidLineEnding": {
+			stdin:   unformatted,
+			args:    []string{"-eol", "cr"},
+			wantErr: `invalid -eol "cr": must be one of lf, crlf or preserve`,
+		},
+		"SyntaxError": {
+			files:     map[string]string{"g.dot": "graph{a->b}"},
+			args:      []string{"-w", "$DIR/g.dot"},
+			wantErr:   "failed to format due to syntax errors",
+			wantFiles: map[string]string{"g.dot": "graph{a->b}"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range test.files {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)
+				require.NoErrorf(t, err, "WriteFile(%q)", name)
+			}
+			var args []string
+			for _, arg := range test.args {
+				args = append(args, strings.ReplaceAll(arg, "$DIR", dir))
+			}
+
+			var out, errOut strings.Builder
+			err := run(args, strings.NewReader(test.stdin), &out, &errOut)
+
+			if test.wantErr == "" {
+				require.NoErrorf(t, err, "run(%q) wrote to stderr %q", test.args, errOut.String())
+			} else {
+				require.NotNilf(t, err, "run(%q)", test.args)
+				assert.EqualValuesf(t, err.Error(), test.wantErr, "run(%q)", test.args)
+			}
+			got := strings.ReplaceAll(out.String(), dir, "$DIR")
+			assert.EqualValuesf(t, got, test.want, "run(%q)", test.args)
+			entries, err := os.ReadDir(dir)
+			require.NoErrorf(t, err, "ReadDir(%q)", dir)
+			gotFiles := make(map[string]string)
+			for _, entry := range entries {
+				content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				require.NoErrorf(t, err, "ReadFile(%q)", entry.Name())
+				gotFiles[entry.Name()] = string(content)
+			}
+			if len(test.files) == 0 {
+				assert.EqualValuesf(t, len(gotFiles), 0, "run(%q) should not create files", test.args)
+			} else {
+				assert.EqualValuesf(t, gotFiles, test.wantFiles, "run(%q) files", test.args)
+			}
+		})
+	}
+}
+
+func TestRunWriteKeepsMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "g.dot")
+	err := os.WriteFile(path, []byte(unformatted), 0o600)
+	require.NoErrorf(t, err, "WriteFile(%q)", path)
+	err = os.Chmod(path, 0o751)
+	require.NoErrorf(t, err, "Chmod(%q)", path)
+
+	var out, errOut strings.Builder
+	err = run([]string{"-w", "-backup", ".orig", path}, strings.NewReader(""), &out, &errOut)
+
+	require.NoErrorf(t, err, "run(-w %q)", path)
+	for _, path := range []string{path, path + ".orig"} {
+		info, err := os.Stat(path)
+		require.NoErrorf(t, err, "Stat(%q)", path)
+		assert.EqualValuesf(t, info.Mode().Perm(), os.FileMode(0o751), "run(-w %q) mode of %q", path, path)
+	}
+}
+
+func TestRunSyntaxError(t *testing.T) {
+	var out, errOut strings.Builder
+	err := run(nil, strings.NewReader("graph {\n\ta -> b\n}"), &out, &errOut)
+
+	require.NotNilf(t, err, "run()")
+	want := `2:4: error: undirected graph cannot contain directed edges (syntax)
+		a -> b
+		  ^
+`
+	assert.EqualValuesf(t, errOut.String(), want, "run() stderr")
+	assert.EqualValuesf(t, out.String(), "", "run() stdout")
+}
+
+func TestRunDebugLayout(t *testing.T) {
+	trace := filepath.Join(t.TempDir(), "trace.json")
+	in := "graph{a [label=xxxxxxxxxxxxxxxxx,color=red]}"
+
+	var out, errOut strings.Builder
+	err := run([]string{"-maxcolumn", "20", "-debug-layout", trace}, strings.NewReader(in), &out, &errOut)
+
+	require.NoErrorf(t, err, "run(-debug-layout)")
+	got, err := os.ReadFile(trace)
+	require.NoErrorf(t, err, "ReadFile(%q)", trace)
+	want := `[
+  {
+    "line": 2,
+    "column": 4,
+    "sourceLine": 1,
+    "sourceColumn": 9,
+    "construct": "attrlist",
+    "reason": "forced"
+  }
+]
+`
+	assert.EqualValuesf(t, string(got), want, "run(-debug-layout) trace")
+}
+
+func TestRunVersion(t *testing.T) {
+	var out, errOut strings.Builder
+	err := run([]string{"-version"}, strings.NewReader(""), &out, &errOut)
+
+	require.NoErrorf(t, err, "run(-version)")
+	assert.Truef(t, strings.HasPrefix(out.String(), "dotfmt "), "run(-version) should start with the command name instead got %q", out.String())
+	assert.Truef(t, strings.HasSuffix(out.String(), fmt.Sprintf("features canonical=%d\n", printer.CanonicalVersion)), "run(-version) should end with the canonical version instead got %q", out.String())
+}
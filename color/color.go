@@ -0,0 +1,190 @@
+// Package color parses colors as defined by https://graphviz.org/docs/attr-types/color/.
+package color
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is wrapped by the errors of colors that Graphviz accepts but that cannot be
+// converted to RGB by this package. These are the shades of X11 colors like red3 and colors of
+// schemes other than x11 and svg like /blues9/3.
+var ErrUnsupported = errors.New("unsupported color")
+
+// Parse parses a color given as "#rrggbb", "#rrggbbaa", as HSV triple like "0.5,0.5,1.0" or
+// "0.5 0.5 1.0" or by its name. Names are case-insensitive and are looked up in the default x11
+// color scheme unless prefixed by a scheme like /svg/gray. Colors without an alpha channel are
+// opaque except for the names transparent, none and invis which are fully transparent. The error
+// wraps [ErrUnsupported] if the color is valid but cannot be converted.
+func Parse(value string) (color.RGBA, error) {
+	in := strings.ToLower(strings.TrimSpace(value))
+	if strings.HasPrefix(in, "#") {
+		hex := in[1:]
+		if len(hex) != 6 && len(hex) != 8 {
+			return color.RGBA{}, fmt.Errorf("invalid color %q: expected #rrggbb or #rrggbbaa", value)
+		}
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+		n, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("invalid color %q: expected hexadecimal digits", value)
+		}
+		return color.RGBA{R: uint8(n >> 24), G: uint8(n >> 16), B: uint8(n >> 8), A: uint8(n)}, nil
+	}
+
+	if parts := strings.FieldsFunc(in, func(r rune) bool { return r == ',' || r == ' ' }); len(parts) == 3 {
+		var hsv [3]float64
+		for i, part := range parts {
+			f, err := strconv.ParseFloat(part, 64)
+			if err != nil || f < 0 || f > 1 {
+				return color.RGBA{}, fmt.Errorf("invalid color %q: expected HSV values between 0 and 1", value)
+			}
+			hsv[i] = f
+		}
+		return hsvToRGB(hsv[0], hsv[1], hsv[2]), nil
+	}
+
+	return parseName(value, in)
+}
+
+// parseName parses the lower case name in optionally prefixed by a color scheme like /svg/red.
+func parseName(value, in string) (color.RGBA, error) {
+	scheme := "x11"
+	if strings.HasPrefix(in, "/") {
+		i := strings.LastIndexByte(in, '/')
+		if i > 0 {
+			scheme = in[1:i]
+		}
+		in = in[i+1:]
+	}
+
+	switch scheme {
+	case "x11":
+		if c, ok := transparentNames[in]; ok {
+			return c, nil
+		}
+		if c, ok := x11Names[in]; ok {
+			return c, nil
+		}
+		if c, ok := svgNames[in]; ok {
+			return c, nil
+		}
+		if c, ok := x11Gray(in); ok {
+			return c, nil
+		}
+		if n := len(in); n > 1 && in[n-1] >= '1' && in[n-1] <= '4' && x11Variants[in[:n-1]] {
+			return color.RGBA{}, fmt.Errorf("%w %q: shades of X11 colors are not supported", ErrUnsupported, value)
+		}
+	case "svg":
+		if c, ok := transparentNames[in]; ok {
+			return c, nil
+		}
+		if c, ok := svgNames[in]; ok {
+			return c, nil
+		}
+	default:
+		return color.RGBA{}, fmt.Errorf("%w %q: color scheme %s is not supported", ErrUnsupported, value, scheme)
+	}
+	return color.RGBA{}, fmt.Errorf("invalid color %q: unknown color name", value)
+}
+
+// x11Gray parses the X11 shades of gray gray0 to gray100 and grey0 to grey100.
+func x11Gray(in string) (color.RGBA, bool) {
+	level, ok := strings.CutPrefix(in, "gray")
+	if !ok {
+		level, ok = strings.CutPrefix(in, "grey")
+	}
+	if !ok || level == "" || level[0] == '+' || level[0] == '-' {
+		return color.RGBA{}, false
+	}
+	n, err := strconv.Atoi(level)
+	if err != nil || n > 100 {
+		return color.RGBA{}, false
+	}
+	v := uint8(float64(n)*2.55 + 0.5)
+	return color.RGBA{v, v, v, 255}, true
+}
+
+// Weighted is a color of a color list together with the fraction of the area it fills.
+type Weighted struct {
+	Color  color.RGBA
+	Weight float64 // Weight is the fraction between 0 and 1 of the area filled by the color.
+}
+
+// ParseList parses a color list like "red;0.3:blue" as defined by
+// https://graphviz.org/docs/attr-types/colorList/. Colors are separated by ':' and each color is
+// parsed using [Parse]. A color is optionally followed by ';' and its weight between 0 and 1. The
+// weights must not add up to more than 1. Colors without a weight evenly share the remaining
+// fraction.
+func ParseList(value string) ([]Weighted, error) {
+	var result []Weighted
+	var total float64
+	var unweighted int
+	for _, part := range strings.Split(value, ":") {
+		name, weight, hasWeight := strings.Cut(part, ";")
+		c, err := Parse(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color list %q: %w", value, err)
+		}
+
+		w := Weighted{Color: c}
+		if hasWeight {
+			w.Weight, err = strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil || w.Weight < 0 || w.Weight > 1 {
+				return nil, fmt.Errorf("invalid color list %q: expected weight between 0 and 1 instead of %q", value, weight)
+			}
+			total += w.Weight
+		} else {
+			w.Weight = -1
+			unweighted++
+		}
+		result = append(result, w)
+	}
+	// allow for rounding errors of weights like 0.1 that cannot be represented exactly
+	if total > 1+1e-9 {
+		return nil, fmt.Errorf("invalid color list %q: weights add up to %g which is more than 1", value, total)
+	}
+
+	for i := range result {
+		if result[i].Weight < 0 {
+			result[i].Weight = max(1-total, 0) / float64(unweighted)
+		}
+	}
+	return result, nil
+}
+
+func hsvToRGB(h, s, v float64) color.RGBA {
+	rgb := func(r, g, b float64) color.RGBA {
+		return color.RGBA{
+			R: uint8(math.Round(r * 255)),
+			G: uint8(math.Round(g * 255)),
+			B: uint8(math.Round(b * 255)),
+			A: 255,
+		}
+	}
+	if s == 0 {
+		return rgb(v, v, v)
+	}
+	h = math.Mod(h*6, 6)
+	i := math.Floor(h)
+	f := h - i
+	p, q, t := v*(1-s), v*(1-s*f), v*(1-s*(1-f))
+	switch int(i) {
+	case 0:
+		return rgb(v, t, p)
+	case 1:
+		return rgb(q, v, p)
+	case 2:
+		return rgb(p, v, t)
+	case 3:
+		return rgb(p, q, v)
+	case 4:
+		return rgb(t, p, v)
+	}
+	return rgb(v, p, q)
+}
@@ -0,0 +1,134 @@
+package color_test
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	dotcolor "github.com/teleivo/dot/color"
+	"github.com/teleivo/dot/internal/assertx"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string]color.RGBA{
+			"#ff8000":       {255, 128, 0, 255},
+			"#FF800080":     {255, 128, 0, 128},
+			" Red ":         {255, 0, 0, 255},
+			"lightgrey":     {211, 211, 211, 255},
+			"0,1,1":         {255, 0, 0, 255},
+			"0.5 1.0 0.5":   {0, 128, 128, 255},
+			"0.000,0.0,1.0": {255, 255, 255, 255},
+			"Crimson":       {220, 20, 60, 255},
+			"green":         {0, 255, 0, 255},
+			"/x11/green":    {0, 255, 0, 255},
+			"/svg/green":    {0, 128, 0, 255},
+			"/green":        {0, 255, 0, 255},
+			"gray50":        {127, 127, 127, 255},
+			"grey100":       {255, 255, 255, 255},
+			"transparent":   {255, 255, 254, 0},
+			"None":          {255, 255, 255, 0},
+			"invis":         {255, 255, 255, 0},
+			"/svg/none":     {255, 255, 255, 0},
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				got, err := dotcolor.Parse(in)
+
+				require.NoErrorf(t, err, "Parse(%q)", in)
+				assert.EqualValuesf(t, got, want, "Parse(%q)", in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]string{
+			"#ff80":       "expected #rrggbb or #rrggbbaa",
+			"#gg8000":     "expected hexadecimal digits",
+			"0.5,1.5,0.5": "expected HSV values between 0 and 1",
+			"ultraviolet": "unknown color name",
+			"gray101":     "unknown color name",
+			"/svg/red3":   "unknown color name",
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				_, err := dotcolor.Parse(in)
+
+				require.NotNilf(t, err, "Parse(%q)", in)
+				assertx.Contains(t, err.Error(), want)
+			})
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		tests := map[string]string{
+			"red3":      "shades of X11 colors are not supported",
+			"/blues9/3": "color scheme blues9 is not supported",
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				_, err := dotcolor.Parse(in)
+
+				require.NotNilf(t, err, "Parse(%q)", in)
+				assert.Truef(t, errors.Is(err, dotcolor.ErrUnsupported), "Parse(%q) = %v, want ErrUnsupported", in, err)
+				assertx.Contains(t, err.Error(), want)
+			})
+		}
+	})
+}
+
+func TestParseList(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string][]dotcolor.Weighted{
+			"red": {
+				{Color: color.RGBA{255, 0, 0, 255}, Weight: 1},
+			},
+			"red:blue": {
+				{Color: color.RGBA{255, 0, 0, 255}, Weight: 0.5},
+				{Color: color.RGBA{0, 0, 255, 255}, Weight: 0.5},
+			},
+			"red;0.25:blue:#00ff00;0.25": {
+				{Color: color.RGBA{255, 0, 0, 255}, Weight: 0.25},
+				{Color: color.RGBA{0, 0, 255, 255}, Weight: 0.5},
+				{Color: color.RGBA{0, 255, 0, 255}, Weight: 0.25},
+			},
+			"red;1:blue": {
+				{Color: color.RGBA{255, 0, 0, 255}, Weight: 1},
+				{Color: color.RGBA{0, 0, 255, 255}, Weight: 0},
+			},
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				got, err := dotcolor.ParseList(in)
+
+				require.NoErrorf(t, err, "ParseList(%q)", in)
+				assert.EqualValuesf(t, got, want, "ParseList(%q)", in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]string{
+			"red:blu":          "unknown color name",
+			"red:":             "unknown color name",
+			"red;x":            "expected weight between 0 and 1",
+			"red;1.5":          "expected weight between 0 and 1",
+			"red;0.6:blue;0.6": "weights add up to 1.2 which is more than 1",
+		}
+
+		for in, want := range tests {
+			t.Run(in, func(t *testing.T) {
+				_, err := dotcolor.ParseList(in)
+
+				require.NotNilf(t, err, "ParseList(%q)", in)
+				assertx.Contains(t, err.Error(), want)
+			})
+		}
+	})
+}
@@ -0,0 +1,485 @@
+// Package diagnostic describes problems found in dot source code so all tools report them
+// consistently. Syntax errors of the parser and problems found by package lint are both
+// [Diagnostic] values that can be collected using a [Collector] and rendered as text, JSON or
+// SARIF using [Write].
+package diagnostic
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/internal/snippet"
+	"github.com/teleivo/dot/token"
+)
+
+// Severity is the severity of a [Diagnostic].
+type Severity int
+
+const (
+	Error   Severity = iota // Error is a problem that leads to a wrong rendering.
+	Warning                 // Warning is a problem that likely leads to an unintended rendering.
+	Info                    // Info is a hint on how to improve the graph.
+	Off                     // Off turns off reporting a problem.
+)
+
+var severityStrings = map[Severity]string{
+	Error:   "error",
+	Warning: "warning",
+	Info:    "info",
+	Off:     "off",
+}
+
+func (s Severity) String() string {
+	return severityStrings[s]
+}
+
+// ParseSeverity parses a severity given as error, warning, warn, info or off in any case.
+func ParseSeverity(in string) (Severity, error) {
+	switch strings.ToLower(in) {
+	case "error":
+		return Error, nil
+	case "warning", "warn":
+		return Warning, nil
+	case "info":
+		return Info, nil
+	case "off":
+		return Off, nil
+	}
+	return Off, fmt.Errorf("invalid severity %q: must be one of error, warning, info or off", in)
+}
+
+// SyntaxCode is the code of diagnostics created from syntax errors using [FromError].
+const SyntaxCode = "syntax"
+
+// Diagnostic is a problem found in dot source code.
+type Diagnostic struct {
+	Start    token.Position // Start is the position of the first rune of the offending code.
+	End      token.Position // End is the position of the last rune of the offending code.
+	Severity Severity       // Severity of the problem.
+	Code     string         // Code identifies the kind of problem like the name of a lint rule.
+	Message  string         // Message describes the problem.
+	Related  []Related      // Related are optional other locations involved in the problem.
+	Fixes    []Fix          // Fixes are optional suggestions on how to resolve the problem.
+}
+
+// String returns the diagnostic like 3:2: warning: message (code).
+func (d Diagnostic) String() string {
+	var out strings.Builder
+	out.WriteString(d.Start.String())
+	out.WriteString(": ")
+	out.WriteString(d.Severity.String())
+	out.WriteString(": ")
+	out.WriteString(d.Message)
+	if d.Code != "" {
+		out.WriteString(" (")
+		out.WriteString(d.Code)
+		out.WriteRune(')')
+	}
+	return out.String()
+}
+
+// Related is a location involved in a [Diagnostic] like the earlier definition of a duplicate.
+type Related struct {
+	Start   token.Position // Start is the position of the first rune of the related code.
+	End     token.Position // End is the position of the last rune of the related code.
+	Message string         // Message describes how the location relates to the problem.
+}
+
+// Fix is a suggested change resolving a [Diagnostic].
+type Fix struct {
+	Message string // Message describes the fix.
+	Edits   []Edit // Edits to apply to the source code.
+}
+
+// Edit replaces the source code between Start and End with NewText. Start is inclusive while End
+// is exclusive so an Edit with Start equal to End inserts NewText before Start.
+type Edit struct {
+	Start   token.Position
+	End     token.Position
+	NewText string
+}
+
+// FromError converts a syntax error of type [dot.Error] into a diagnostic of severity [Error] with
+// code [SyntaxCode]. Keywords used as identifiers come with a fix quoting them. It reports false if
+// err is not a syntax error.
+func FromError(err error) (Diagnostic, bool) {
+	var dotErr dot.Error
+	if !errors.As(err, &dotErr) {
+		return Diagnostic{}, false
+	}
+	pos := token.Position{Row: dotErr.LineNr, Column: dotErr.CharacterNr}
+	d := Diagnostic{
+		Start:    pos,
+		End:      pos,
+		Severity: Error,
+		Code:     SyntaxCode,
+		Message:  dotErr.Reason,
+	}
+	if dotErr.Category == dot.KeywordAsID {
+		d.End = token.Position{Row: dotErr.Resume.Row, Column: dotErr.Resume.Column - 1}
+		d.Fixes = []Fix{{
+			Message: "quote the keyword",
+			Edits: []Edit{
+				{Start: pos, End: pos, NewText: `"`},
+				{Start: dotErr.Resume, End: dotErr.Resume, NewText: `"`},
+			},
+		}}
+	}
+	return d, true
+}
+
+// Collector collects the diagnostics reported by multiple tools like the parser and the linter.
+// The zero value is ready to use.
+type Collector struct {
+	diagnostics []Diagnostic
+}
+
+// Add adds the diagnostics.
+func (c *Collector) Add(diagnostics ...Diagnostic) {
+	c.diagnostics = append(c.diagnostics, diagnostics...)
+}
+
+// AddError adds the syntax error as a diagnostic using [FromError]. Any other error is returned as
+// is so the caller can handle failures like I/O errors.
+func (c *Collector) AddError(err error) error {
+	d, ok := FromError(err)
+	if !ok {
+		return err
+	}
+	c.Add(d)
+	return nil
+}
+
+// Diagnostics returns the collected diagnostics sorted by their start position. Diagnostics
+// starting at the same position keep the order they were added in.
+func (c *Collector) Diagnostics() []Diagnostic {
+	result := slices.Clone(c.diagnostics)
+	Sort(result)
+	return result
+}
+
+// Sort sorts the diagnostics by their start position keeping the order of diagnostics starting at
+// the same position.
+func Sort(diagnostics []Diagnostic) {
+	slices.SortStableFunc(diagnostics, func(a, b Diagnostic) int {
+		if a.Start.Before(b.Start) {
+			return -1
+		} else if a.Start.After(b.Start) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// Count returns the number of diagnostics per severity.
+func Count(diagnostics []Diagnostic) map[Severity]int {
+	result := make(map[Severity]int)
+	for _, d := range diagnostics {
+		result[d.Severity]++
+	}
+	return result
+}
+
+// Fails reports whether any of the diagnostics is at least as severe as the threshold. This allows
+// failing on warnings as well as errors by passing [Warning]. No diagnostic fails the threshold
+// [Off].
+func Fails(diagnostics []Diagnostic, threshold Severity) bool {
+	for _, d := range diagnostics {
+		if d.Severity <= threshold && threshold != Off {
+			return true
+		}
+	}
+	return false
+}
+
+// Format defines the format in which diagnostics are rendered by [Write].
+type Format int
+
+const (
+	Text  Format = iota // Text renders diagnostics as text with the offending line and a caret.
+	JSON                // JSON renders diagnostics as a JSON array.
+	SARIF               // SARIF renders diagnostics as a SARIF 2.1.0 log understood by code scanning tools.
+)
+
+// Options configures how diagnostics are rendered by [Write].
+type Options struct {
+	Format   Format // Format in which diagnostics are rendered.
+	Filename string // Filename is the optional name of the file the diagnostics were found in.
+}
+
+// Write renders the diagnostics found in the dot source code src to w. The [Text] format renders
+// each diagnostic with the line of src it was found in and a caret pointing at its start like
+//
+//	graph.dot:3:2: warning: edge differs from 1 other edges only by its color red (color-only)
+//		a -> b [color=red]
+//		^
+//
+// followed by its related locations. The [JSON] format renders an array of objects holding the
+// filename, the range, severity, code, message, offending line, related locations and fixes. The
+// [SARIF] format renders a log with a single run as defined by
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html. Codes become the rule IDs of
+// its results and the severity [Info] becomes the level note.
+func Write(w io.Writer, diagnostics []Diagnostic, src []byte, opts Options) error {
+	line := snippet.Lines(src)
+
+	switch opts.Format {
+	case JSON:
+		return writeJSON(w, diagnostics, line, opts)
+	case SARIF:
+		return writeSARIF(w, diagnostics, line, opts)
+	}
+
+	var out strings.Builder
+	position := func(pos token.Position) {
+		if opts.Filename != "" {
+			out.WriteString(opts.Filename)
+			out.WriteRune(':')
+		}
+		out.WriteString(pos.String())
+		out.WriteString(": ")
+	}
+	for _, d := range diagnostics {
+		position(d.Start)
+		out.WriteString(d.Severity.String())
+		out.WriteString(": ")
+		out.WriteString(d.Message)
+		if d.Code != "" {
+			out.WriteString(" (")
+			out.WriteString(d.Code)
+			out.WriteRune(')')
+		}
+		out.WriteRune('\n')
+		snippet.Write(&out, line(d.Start.Row), d.Start.Column)
+
+		for _, r := range d.Related {
+			out.WriteRune('\t')
+			position(r.Start)
+			out.WriteString(r.Message)
+			out.WriteRune('\n')
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+type jsonRange struct {
+	Line      int `json:"line"`
+	Column    int `json:"column"`
+	EndLine   int `json:"endLine"`
+	EndColumn int `json:"endColumn"`
+}
+
+func newJSONRange(start, end token.Position) jsonRange {
+	return jsonRange{Line: start.Row, Column: start.Column, EndLine: end.Row, EndColumn: end.Column}
+}
+
+type jsonRelated struct {
+	jsonRange
+	Message string `json:"message"`
+}
+
+type jsonEdit struct {
+	jsonRange
+	NewText string `json:"newText"`
+}
+
+type jsonFix struct {
+	Message string     `json:"message"`
+	Edits   []jsonEdit `json:"edits"`
+}
+
+type jsonDiagnostic struct {
+	Filename string `json:"filename,omitempty"`
+	jsonRange
+	Severity string        `json:"severity"`
+	Code     string        `json:"code,omitempty"`
+	Message  string        `json:"message"`
+	Source   string        `json:"source"`
+	Related  []jsonRelated `json:"related,omitempty"`
+	Fixes    []jsonFix     `json:"fixes,omitempty"`
+}
+
+func writeJSON(w io.Writer, diagnostics []Diagnostic, line func(int) string, opts Options) error {
+	out := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, d := range diagnostics {
+		jd := jsonDiagnostic{
+			Filename:  opts.Filename,
+			jsonRange: newJSONRange(d.Start, d.End),
+			Severity:  d.Severity.String(),
+			Code:      d.Code,
+			Message:   d.Message,
+			Source:    line(d.Start.Row),
+		}
+		for _, r := range d.Related {
+			jd.Related = append(jd.Related, jsonRelated{jsonRange: newJSONRange(r.Start, r.End), Message: r.Message})
+		}
+		for _, f := range d.Fixes {
+			fix := jsonFix{Message: f.Message, Edits: []jsonEdit{}}
+			for _, e := range f.Edits {
+				fix.Edits = append(fix.Edits, jsonEdit{jsonRange: newJSONRange(e.Start, e.End), NewText: e.NewText})
+			}
+			jd.Fixes = append(jd.Fixes, fix)
+		}
+		out = append(out, jd)
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int           `json:"startLine"`
+	StartColumn int           `json:"startColumn"`
+	EndLine     int           `json:"endLine"`
+	EndColumn   int           `json:"endColumn"`
+	Snippet     *sarifMessage `json:"snippet,omitempty"`
+}
+
+// newSARIFRegion creates a region of the code from start up to but excluding end as SARIF regions
+// end before their end column.
+func newSARIFRegion(start, end token.Position) sarifRegion {
+	return sarifRegion{StartLine: start.Row, StartColumn: start.Column, EndLine: end.Row, EndColumn: end.Column}
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifLocation struct {
+	ID               *int                  `json:"id,omitempty"`
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	Message          *sarifMessage         `json:"message,omitempty"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion  `json:"deletedRegion"`
+	InsertedContent sarifMessage `json:"insertedContent"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []sarifReplacement    `json:"replacements"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifResult struct {
+	RuleID           string          `json:"ruleId,omitempty"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name           string      `json:"name"`
+			InformationURI string      `json:"informationUri"`
+			Rules          []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	ColumnKind string        `json:"columnKind"`
+	Results    []sarifResult `json:"results"`
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+var sarifLevels = map[Severity]string{
+	Error:   "error",
+	Warning: "warning",
+	Info:    "note",
+	Off:     "none",
+}
+
+func writeSARIF(w io.Writer, diagnostics []Diagnostic, line func(int) string, opts Options) error {
+	run := sarifRun{ColumnKind: "unicodeCodePoints", Results: []sarifResult{}}
+	run.Tool.Driver.Name = "dot"
+	run.Tool.Driver.InformationURI = "https://github.com/teleivo/dot"
+	run.Tool.Driver.Rules = []sarifRule{}
+	artifact := sarifArtifactLocation{URI: opts.Filename}
+
+	rules := make(map[string]bool)
+	for _, d := range diagnostics {
+		if d.Code != "" && !rules[d.Code] {
+			rules[d.Code] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: d.Code})
+		}
+
+		// the end of a diagnostic is the position of its last rune
+		region := newSARIFRegion(d.Start, token.Position{Row: d.End.Row, Column: d.End.Column + 1})
+		if src := line(d.Start.Row); src != "" {
+			region.Snippet = &sarifMessage{Text: src}
+		}
+		result := sarifResult{
+			RuleID:    d.Code,
+			Level:     sarifLevels[d.Severity],
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact, Region: region}}},
+		}
+		for i, r := range d.Related {
+			id := i + 1
+			result.RelatedLocations = append(result.RelatedLocations, sarifLocation{
+				ID: &id,
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: artifact,
+					Region:           newSARIFRegion(r.Start, token.Position{Row: r.End.Row, Column: r.End.Column + 1}),
+				},
+				Message: &sarifMessage{Text: r.Message},
+			})
+		}
+		for _, f := range d.Fixes {
+			change := sarifArtifactChange{ArtifactLocation: artifact, Replacements: []sarifReplacement{}}
+			for _, e := range f.Edits {
+				change.Replacements = append(change.Replacements, sarifReplacement{
+					DeletedRegion:   newSARIFRegion(e.Start, e.End),
+					InsertedContent: sarifMessage{Text: e.NewText},
+				})
+			}
+			result.Fixes = append(result.Fixes, sarifFix{Description: sarifMessage{Text: f.Message}, ArtifactChanges: []sarifArtifactChange{change}})
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	b, err := json.Marshal(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
@@ -0,0 +1,129 @@
+package diagnostic_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/diagnostic"
+	"github.com/teleivo/dot/token"
+)
+
+func TestCollector(t *testing.T) {
+	src := "graph {\n\tA -- B [color=red]\n\tC ! D\n}"
+	_, parseErr := dot.Parse([]byte(src))
+	require.NotNilf(t, parseErr, "Parse(%q)", src)
+
+	var c diagnostic.Collector
+	c.Add(diagnostic.Diagnostic{
+		Start:    token.Position{Row: 2, Column: 2},
+		End:      token.Position{Row: 2, Column: 7},
+		Severity: diagnostic.Warning,
+		Code:     "color-only",
+		Message:  "edge differs only by its color",
+		Related: []diagnostic.Related{
+			{Start: token.Position{Row: 1, Column: 1}, End: token.Position{Row: 1, Column: 5}, Message: "in this graph"},
+		},
+	})
+	err := c.AddError(parseErr)
+	require.NoErrorf(t, err, "AddError(%v)", parseErr)
+	c.Add(diagnostic.Diagnostic{
+		Start:    token.Position{Row: 1, Column: 1},
+		End:      token.Position{Row: 1, Column: 5},
+		Severity: diagnostic.Info,
+		Code:     "label",
+		Message:  "graph has no label",
+	})
+
+	ioErr := errors.New("read failed")
+	err = c.AddError(ioErr)
+	assert.Truef(t, errors.Is(err, ioErr), "AddError() should return errors that are no syntax errors")
+
+	got := c.Diagnostics()
+
+	require.EqualValuesf(t, len(got), 3, "Diagnostics()")
+	assert.EqualValuesf(t, got[0].Code, "label", "Diagnostics()[0]")
+	assert.EqualValuesf(t, got[1].Code, "color-only", "Diagnostics()[1]")
+	assert.EqualValuesf(t, got[2].Code, diagnostic.SyntaxCode, "Diagnostics()[2]")
+	assert.Truef(t, strings.HasPrefix(got[2].String(), "3:4: error: unquoted string"), "Diagnostics()[2] got %q", got[2].String())
+	assert.EqualValuesf(t, diagnostic.Count(got), map[diagnostic.Severity]int{diagnostic.Error: 1, diagnostic.Warning: 1, diagnostic.Info: 1}, "Count()")
+	assert.Truef(t, diagnostic.Fails(got, diagnostic.Error), "Fails(Error)")
+
+	t.Run("Text", func(t *testing.T) {
+		var out strings.Builder
+		err := diagnostic.Write(&out, got[:2], []byte(src), diagnostic.Options{Filename: "deps.dot"})
+
+		require.NoErrorf(t, err, "Write()")
+		want := `deps.dot:1:1: info: graph has no label (label)
+	graph {
+	^
+deps.dot:2:2: warning: edge differs only by its color (color-only)
+		A -- B [color=red]
+		^
+	deps.dot:1:1: in this graph
+`
+		if out.String() != want {
+			t.Errorf("\n\ngot:\n%s\n\n\nwant:\n%s\n", out.String(), want)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		d := got[1]
+		d.Fixes = []diagnostic.Fix{{
+			Message: "add a style",
+			Edits: []diagnostic.Edit{
+				{Start: token.Position{Row: 2, Column: 19}, End: token.Position{Row: 2, Column: 19}, NewText: ", style=dashed"},
+			},
+		}}
+		var out strings.Builder
+		err := diagnostic.Write(&out, []diagnostic.Diagnostic{d}, []byte(src), diagnostic.Options{Format: diagnostic.JSON})
+
+		require.NoErrorf(t, err, "Write()")
+		want := `[{"line":2,"column":2,"endLine":2,"endColumn":7,"severity":"warning","code":"color-only","message":"edge differs only by its color","source":"\tA -- B [color=red]","related":[{"line":1,"column":1,"endLine":1,"endColumn":5,"message":"in this graph"}],"fixes":[{"message":"add a style","edits":[{"line":2,"column":19,"endLine":2,"endColumn":19,"newText":", style=dashed"}]}]}]`
+		assert.EqualValuesf(t, out.String(), want, "Write()")
+	})
+
+	t.Run("SARIF", func(t *testing.T) {
+		d := got[1]
+		d.Fixes = []diagnostic.Fix{{
+			Message: "add a style",
+			Edits: []diagnostic.Edit{
+				{Start: token.Position{Row: 2, Column: 19}, End: token.Position{Row: 2, Column: 19}, NewText: ", style=dashed"},
+			},
+		}}
+		var out strings.Builder
+		err := diagnostic.Write(&out, []diagnostic.Diagnostic{d}, []byte(src), diagnostic.Options{Format: diagnostic.SARIF, Filename: "deps.dot"})
+
+		require.NoErrorf(t, err, "Write()")
+		want := `{"$schema":"https://json.schemastore.org/sarif-2.1.0.json","version":"2.1.0","runs":[{"tool":{"driver":{"name":"dot","informationUri":"https://github.com/teleivo/dot","rules":[{"id":"color-only"}]}},"columnKind":"unicodeCodePoints","results":[{"ruleId":"color-only","level":"warning","message":{"text":"edge differs only by its color"},"locations":[{"physicalLocation":{"artifactLocation":{"uri":"deps.dot"},"region":{"startLine":2,"startColumn":2,"endLine":2,"endColumn":8,"snippet":{"text":"\tA -- B [color=red]"}}}}],"relatedLocations":[{"id":1,"physicalLocation":{"artifactLocation":{"uri":"deps.dot"},"region":{"startLine":1,"startColumn":1,"endLine":1,"endColumn":6}},"message":{"text":"in this graph"}}],"fixes":[{"description":{"text":"add a style"},"artifactChanges":[{"artifactLocation":{"uri":"deps.dot"},"replacements":[{"deletedRegion":{"startLine":2,"startColumn":19,"endLine":2,"endColumn":19},"insertedContent":{"text":", style=dashed"}}]}]}]}]}]}`
+		assert.EqualValuesf(t, out.String(), want, "Write()")
+	})
+}
+
+func TestFromError(t *testing.T) {
+	src := "graph {\n\tA -- Edge\n}"
+	_, err := dot.Parse([]byte(src))
+	require.NotNilf(t, err, "Parse(%q)", src)
+
+	got, ok := diagnostic.FromError(err)
+
+	require.Truef(t, ok, "FromError(%v)", err)
+	want := diagnostic.Diagnostic{
+		Start:    token.Position{Row: 2, Column: 7},
+		End:      token.Position{Row: 2, Column: 10},
+		Severity: diagnostic.Error,
+		Code:     diagnostic.SyntaxCode,
+		Message:  `keyword Edge cannot be used as an identifier unless quoted like "Edge"`,
+		Fixes: []diagnostic.Fix{{
+			Message: "quote the keyword",
+			Edits: []diagnostic.Edit{
+				{Start: token.Position{Row: 2, Column: 7}, End: token.Position{Row: 2, Column: 7}, NewText: `"`},
+				{Start: token.Position{Row: 2, Column: 11}, End: token.Position{Row: 2, Column: 11}, NewText: `"`},
+			},
+		}},
+	}
+	assert.EqualValuesf(t, got, want, "FromError(%v)", err)
+}
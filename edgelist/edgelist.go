@@ -0,0 +1,213 @@
+// Package edgelist reads graphs from and writes graphs to edge lists in CSV or TSV format.
+package edgelist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+	"unicode"
+
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+)
+
+// Options configures how an edge list is read by [Read] or written by [Write].
+type Options struct {
+	Directed bool     // Directed creates a directed instead of an undirected graph when reading.
+	Comma    rune     // Comma separates the fields of a record. It defaults to ',' and is '\t' for TSV.
+	Header   bool     // Header indicates that the first record names the columns.
+	Attrs    []string // Attrs names the edge attributes written following source and target.
+}
+
+// Read reads an edge list from r and returns it as a graph. Every record creates an edge from the
+// node in its first field to the node in its second field. Records must all have the same number of
+// fields. Fields are quoted as defined by [encoding/csv]. Lines starting with '#' are skipped.
+//
+// The fields following source and target are attributes of the edge. Their names are taken from
+// the header. Without a header only a third field is read which is the label of the edge
+//
+//	source,target,label
+//	A,B,calls
+//	B,C,
+//
+// Attributes with an empty value are left out. A record with an empty target creates a node
+// without edges.
+func Read(r io.Reader, opts Options) (ast.Graph, error) {
+	kind := dot.Undirected
+	if opts.Directed {
+		kind = dot.Directed
+	}
+	b := dot.NewGraphBuilder(kind)
+
+	cr := csv.NewReader(r)
+	if opts.Comma != 0 {
+		cr.Comma = opts.Comma
+	}
+	cr.Comment = '#'
+	// trimming would swallow empty fields of TSV
+	cr.TrimLeadingSpace = !unicode.IsSpace(cr.Comma)
+
+	var names []string
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return ast.Graph{}, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		if first && opts.Header {
+			if len(record) < 2 {
+				return ast.Graph{}, fmt.Errorf("line %d: header has %d columns instead of at least source and target", line, len(record))
+			}
+			names = record[2:]
+			for i, name := range names {
+				if name == "" {
+					return ast.Graph{}, fmt.Errorf("line %d: missing attribute name of column %d", line, i+3)
+				}
+			}
+			continue
+		}
+		if len(record) < 2 {
+			return ast.Graph{}, fmt.Errorf("line %d: record has %d fields instead of at least source and target", line, len(record))
+		}
+		if !opts.Header {
+			if len(record) > 3 {
+				return ast.Graph{}, fmt.Errorf("line %d: record has %d fields but only source, target and label can be read without a header", line, len(record))
+			}
+			names = []string{"label"}
+		}
+
+		source, target := record[0], record[1]
+		if source == "" {
+			return ast.Graph{}, fmt.Errorf("line %d: missing source", line)
+		}
+		if target == "" {
+			b.Node(source)
+			continue
+		}
+		var attrs []ast.Attribute
+		for i, value := range record[2:] {
+			if value != "" {
+				attrs = append(attrs, dot.Attr(names[i], value))
+			}
+		}
+		b.Edge(source, target, attrs...)
+	}
+	return b.Build(), nil
+}
+
+// Write writes the edges of the graph read as dot source code from r as an edge list to w. Every
+// edge is written as a record of its source, its target and the values of the attributes named by
+// opts.Attrs. Attributes that are not set are written as empty fields. The header names the
+// columns like
+//
+//	source,target,color
+//	A,B,red
+//	B,C,
+//
+// The graph is parsed one statement at a time using [dot.StreamParser] so graphs that do not fit
+// into memory can be written. Edge statements declaring multiple edges like A -> {B C} are expanded
+// using [ast.ExpandEdges]. Edges get the attributes of the edge attribute statements in effect.
+// Nodes without edges and ports are left out. Multi-edges of strict graphs are written as declared
+// as merging them requires holding all edges in memory.
+func Write(w io.Writer, r io.Reader, opts Options) error {
+	sp, err := dot.NewStreamParser(r)
+	if err != nil {
+		return err
+	}
+
+	ew := writer{cw: csv.NewWriter(w), attrs: opts.Attrs}
+	if opts.Comma != 0 {
+		ew.cw.Comma = opts.Comma
+	}
+	if opts.Header {
+		if err := ew.cw.Write(append([]string{"source", "target"}, opts.Attrs...)); err != nil {
+			return err
+		}
+	}
+
+	defaults := make(map[string]string)
+	for {
+		stmt, err := sp.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := ew.stmt(stmt, defaults); err != nil {
+			return err
+		}
+	}
+	ew.cw.Flush()
+	return ew.cw.Error()
+}
+
+type writer struct {
+	cw    *csv.Writer
+	attrs []string
+}
+
+// stmt writes the edges declared by the statement. Edge attribute statements set the defaults
+// which are in effect for the remaining statements of their graph or subgraph.
+func (w writer) stmt(stmt ast.Stmt, defaults map[string]string) error {
+	switch st := stmt.(type) {
+	case *ast.AttrStmt:
+		if strings.EqualFold(st.ID.Literal, "edge") {
+			setAttrs(defaults, &st.AttrList)
+		}
+	case ast.Subgraph:
+		return w.subgraph(st, defaults)
+	case *ast.EdgeStmt:
+		operands := []ast.EdgeOperand{st.Left}
+		for cur := &st.Right; cur != nil; cur = cur.Next {
+			operands = append(operands, cur.Right)
+		}
+		for _, operand := range operands {
+			if subgraph, ok := operand.(ast.Subgraph); ok {
+				if err := w.subgraph(subgraph, defaults); err != nil {
+					return err
+				}
+			}
+		}
+
+		attrs := maps.Clone(defaults)
+		setAttrs(attrs, st.AttrList)
+		record := make([]string, 2+len(w.attrs))
+		for i, name := range w.attrs {
+			record[2+i] = attrs[name]
+		}
+		for _, edge := range ast.ExpandEdges(st) {
+			record[0] = edge.Left.(ast.NodeID).ID.Unquoted()
+			record[1] = edge.Right.Right.(ast.NodeID).ID.Unquoted()
+			if err := w.cw.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// subgraph writes the edges declared in the subgraph. Its edge defaults start out as the defaults
+// in effect and do not affect the statements following it.
+func (w writer) subgraph(subgraph ast.Subgraph, defaults map[string]string) error {
+	defaults = maps.Clone(defaults)
+	for _, stmt := range subgraph.Stmts {
+		if err := w.stmt(stmt, defaults); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAttrs sets the unquoted attributes of all attribute lists.
+func setAttrs(attrs map[string]string, attrList *ast.AttrList) {
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			attrs[aList.Attribute.Name.Unquoted()] = aList.Attribute.Value.Unquoted()
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package edgelist_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/edgelist"
+	"github.com/teleivo/dot/internal/assertx"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string]struct {
+			in   string
+			opts edgelist.Options
+			want string
+		}{
+			"Empty": {
+				in:   "",
+				want: `graph {}`,
+			},
+			"SourceAndTarget": {
+				in: `A,B
+# skipped
+B, C`,
+				opts: edgelist.Options{Directed: true},
+				want: `digraph {
+	A -> B
+	B -> C
+}`,
+			},
+			"LabelWithoutHeader": {
+				in: `A,B,calls
+B,"C D",
+E,,`,
+				want: `graph {
+	A -- B [label=calls]
+	B -- "C D"
+	E
+}`,
+			},
+			"AttributesNamedByHeader": {
+				in: "source\ttarget\tcolor\tpenwidth\nA\tB\tred\t2\nB\tC\t\t1",
+				opts: edgelist.Options{
+					Directed: true,
+					Comma:    '\t',
+					Header:   true,
+				},
+				want: `digraph {
+	A -> B [color=red,penwidth=2]
+	B -> C [penwidth=1]
+}`,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				g, err := edgelist.Read(strings.NewReader(test.in), test.opts)
+
+				require.NoErrorf(t, err, "Read(%q)", test.in)
+				assert.EqualValuesf(t, g.String(), test.want, "Read(%q)", test.in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			in     string
+			opts   edgelist.Options
+			errMsg string
+		}{
+			"MissingTarget": {
+				in:     "A\nB",
+				errMsg: "line 1: record has 1 fields instead of at least source and target",
+			},
+			"MissingSource": {
+				in:     ",B",
+				errMsg: "line 1: missing source",
+			},
+			"RecordsOfDifferentLength": {
+				in:     "A,B\nB,C,calls",
+				errMsg: "record on line 2: wrong number of fields",
+			},
+			"TooManyFieldsWithoutHeader": {
+				in:     "A,B,calls,red",
+				errMsg: "line 1: record has 4 fields but only source, target and label can be read without a header",
+			},
+			"MissingAttributeName": {
+				in:     "source,target,,color\nA,B,x,red",
+				opts:   edgelist.Options{Header: true},
+				errMsg: "line 1: missing attribute name of column 3",
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := edgelist.Read(strings.NewReader(test.in), test.opts)
+
+				require.NotNilf(t, err, "Read(%q)", test.in)
+				assertx.Contains(t, err.Error(), test.errMsg)
+			})
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		opts edgelist.Options
+		want string
+	}{
+		"Empty": {
+			in:   "",
+			want: "",
+		},
+		"EdgesWithDefaults": {
+			in: `digraph {
+	edge [color=red]
+	A -> B -> C [label=x]
+	subgraph {
+		edge [color=blue]
+		C -> D
+	}
+	D -> {E F}
+}`,
+			opts: edgelist.Options{Header: true, Attrs: []string{"color", "label"}},
+			want: `source,target,color,label
+A,B,red,x
+B,C,red,x
+C,D,blue,
+D,E,red,
+D,F,red,
+`,
+		},
+		"EdgesOfSubgraphOperands": {
+			in:   `graph { A -- { B -- C } }`,
+			want: "B,C\nA,B\nA,C\n",
+		},
+		"QuotedFieldsOfTSV": {
+			in:   "graph { \"A B\" -- \"C\\\"D\" [label=\"x\ty\"] }",
+			opts: edgelist.Options{Comma: '\t', Attrs: []string{"label"}},
+			want: "A B\t\"C\"\"D\"\t\"x\ty\"\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out strings.Builder
+			err := edgelist.Write(&out, strings.NewReader(test.in), test.opts)
+
+			require.NoErrorf(t, err, "Write(%q)", test.in)
+			assert.EqualValuesf(t, out.String(), test.want, "Write(%q)", test.in)
+		})
+	}
+
+	t.Run("SyntaxError", func(t *testing.T) {
+		in := `graph { A -> B }`
+		var out strings.Builder
+		err := edgelist.Write(&out, strings.NewReader(in), edgelist.Options{})
+
+		require.NotNilf(t, err, "Write(%q)", in)
+		assertx.Contains(t, err.Error(), "undirected graph cannot contain directed edges")
+	})
+}
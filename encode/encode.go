@@ -0,0 +1,86 @@
+// Package encode writes in-memory graphs of any Go type as formatted dot code. Implement [Graph] on
+// top of an existing graph type to dump it using [Write].
+package encode
+
+import (
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/printer"
+)
+
+// Seq is an iterator over values of type V. It has the same shape as iter.Seq so it can be ranged
+// over once the module requires a Go version supporting range over functions.
+type Seq[V any] func(yield func(V) bool)
+
+// Graph is implemented by graphs to be written by [Write].
+type Graph interface {
+	Directed() bool   // Directed reports whether the graph is directed.
+	Nodes() Seq[Node] // Nodes returns an iterator over the nodes of the graph.
+	Edges() Seq[Edge] // Edges returns an iterator over the edges of the graph.
+}
+
+// Node is a node of a [Graph].
+type Node struct {
+	ID    string            // ID is the identifier of the node. It is quoted if needed.
+	Attrs map[string]string // Attrs are the attributes of the node. They are written sorted by name.
+}
+
+// Edge is an edge of a [Graph] connecting the tail node to the head node.
+type Edge struct {
+	Tail  string            // Tail is the identifier of the node the edge starts at.
+	Head  string            // Head is the identifier of the node the edge ends at.
+	Attrs map[string]string // Attrs are the attributes of the edge. They are written sorted by name.
+}
+
+// Write writes the graph g as dot code formatted by the [printer.Printer] using given options.
+// Nodes are written in the order of the iterator followed by the edges. Nodes without attributes
+// are written as well so nodes without edges are part of the graph.
+func Write(w io.Writer, g Graph, opts ...printer.Option) error {
+	graph := ast.Graph{Directed: g.Directed()}
+	g.Nodes()(func(n Node) bool {
+		graph.Stmts = append(graph.Stmts, &ast.NodeStmt{
+			NodeID:   ast.NodeID{ID: ast.NewID(n.ID)},
+			AttrList: attrList(n.Attrs),
+		})
+		return true
+	})
+	g.Edges()(func(e Edge) bool {
+		graph.Stmts = append(graph.Stmts, &ast.EdgeStmt{
+			Left: ast.NodeID{ID: ast.NewID(e.Tail)},
+			Right: ast.EdgeRHS{
+				Directed: graph.Directed,
+				Right:    ast.NodeID{ID: ast.NewID(e.Head)},
+			},
+			AttrList: attrList(e.Attrs),
+		})
+		return true
+	})
+
+	p := printer.NewPrinter(strings.NewReader(graph.String()), w, opts...)
+	return p.Print()
+}
+
+// attrList returns the attributes sorted by name. Nil is returned if there are none.
+func attrList(attrs map[string]string) *ast.AttrList {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	result := &ast.AttrList{}
+	for i := len(names) - 1; i >= 0; i-- {
+		result.AList = &ast.AList{
+			Attribute: ast.Attribute{Name: ast.NewID(names[i]), Value: ast.NewID(attrs[names[i]])},
+			Next:      result.AList,
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package encode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/encode"
+	"github.com/teleivo/dot/printer"
+)
+
+// deps is a graph of package dependencies as it could be held by a program.
+type deps struct {
+	packages []string
+	imports  map[string][]string
+	std      map[string]bool
+}
+
+func (d deps) Directed() bool {
+	return true
+}
+
+func (d deps) Nodes() encode.Seq[encode.Node] {
+	return func(yield func(encode.Node) bool) {
+		for _, pkg := range d.packages {
+			n := encode.Node{ID: pkg}
+			if d.std[pkg] {
+				n.Attrs = map[string]string{"shape": "box", "style": "filled"}
+			}
+			if !yield(n) {
+				return
+			}
+		}
+	}
+}
+
+func (d deps) Edges() encode.Seq[encode.Edge] {
+	return func(yield func(encode.Edge) bool) {
+		for _, pkg := range d.packages {
+			for _, imp := range d.imports[pkg] {
+				if !yield(encode.Edge{Tail: pkg, Head: imp}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g := deps{
+		packages: []string{"main", "github.com/teleivo/dot", "io", "unused"},
+		imports: map[string][]string{
+			"main":                   {"github.com/teleivo/dot", "io"},
+			"github.com/teleivo/dot": {"io"},
+		},
+		std: map[string]bool{"io": true},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		var got strings.Builder
+		err := encode.Write(&got, g)
+
+		require.NoErrorf(t, err, "Write()")
+		want := `digraph {
+	main
+	"github.com/teleivo/dot"
+	io [
+		shape=box
+		style=filled
+	]
+	unused
+	main -> "github.com/teleivo/dot"
+	main -> io
+	"github.com/teleivo/dot" -> io
+}`
+		if got.String() != want {
+			t.Errorf("\n\ngot:\n%s\n\n\nwant:\n%s\n", got.String(), want)
+		}
+	})
+
+	t.Run("WithPrinterOptions", func(t *testing.T) {
+		var got strings.Builder
+		err := encode.Write(&got, g, printer.WithQuoting(printer.QuoteAlways))
+
+		require.NoErrorf(t, err, "Write()")
+		want := `digraph {
+	"main"
+	"github.com/teleivo/dot"
+	"io" [
+		"shape"="box"
+		"style"="filled"
+	]
+	"unused"
+	"main" -> "github.com/teleivo/dot"
+	"main" -> "io"
+	"github.com/teleivo/dot" -> "io"
+}`
+		if got.String() != want {
+			t.Errorf("\n\ngot:\n%s\n\n\nwant:\n%s\n", got.String(), want)
+		}
+	})
+}
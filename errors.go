@@ -0,0 +1,79 @@
+package dot
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/internal/snippet"
+)
+
+// ErrorFormat defines the format in which errors are rendered by [FormatErrors].
+type ErrorFormat int
+
+const (
+	ErrorText ErrorFormat = iota // ErrorText renders errors as text with the offending line and a caret.
+	ErrorJSON                    // ErrorJSON renders errors as a JSON array.
+)
+
+// ErrorOptions configures how errors are rendered by [FormatErrors].
+type ErrorOptions struct {
+	Format   ErrorFormat // Format in which errors are rendered.
+	Filename string      // Filename is the optional name of the file the errors were found in.
+}
+
+// FormatErrors renders the errors found in the dot source code src. Each error is rendered with the
+// line of src it was found in and a caret pointing at the offending character like
+//
+//	graph.dot:1:11: unquoted string identifiers can contain alphabetic ...
+//		graph { A ! }
+//		          ^
+//
+// The [ErrorJSON] format renders an array of objects holding the filename, line, column, reason and
+// the offending line.
+func FormatErrors(errs []Error, src []byte, opts ErrorOptions) (string, error) {
+	line := snippet.Lines(src)
+
+	if opts.Format == ErrorJSON {
+		type jsonError struct {
+			Filename string `json:"filename,omitempty"`
+			Line     int    `json:"line"`
+			Column   int    `json:"column"`
+			Reason   string `json:"reason"`
+			Source   string `json:"source"`
+		}
+		out := make([]jsonError, 0, len(errs))
+		for _, err := range errs {
+			out = append(out, jsonError{
+				Filename: opts.Filename,
+				Line:     err.LineNr,
+				Column:   err.CharacterNr,
+				Reason:   err.Reason,
+				Source:   line(err.LineNr),
+			})
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+
+	var out strings.Builder
+	for _, err := range errs {
+		if opts.Filename != "" {
+			out.WriteString(opts.Filename)
+			out.WriteRune(':')
+		}
+		out.WriteString(strconv.Itoa(err.LineNr))
+		out.WriteRune(':')
+		out.WriteString(strconv.Itoa(err.CharacterNr))
+		out.WriteString(": ")
+		out.WriteString(err.Reason)
+		out.WriteRune('\n')
+
+		snippet.Write(&out, line(err.LineNr), err.CharacterNr)
+	}
+
+	return out.String(), nil
+}
@@ -0,0 +1,58 @@
+package dot_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+)
+
+func TestFormatErrors(t *testing.T) {
+	src := "graph {\n\tA ! B\n\tC -- \"D\r\n}"
+	errs := []dot.Error{
+		{LineNr: 2, CharacterNr: 4, Character: '!', Reason: "invalid character"},
+		{LineNr: 3, CharacterNr: 7, Character: '"', Reason: "missing closing quote"},
+		{LineNr: 9, CharacterNr: 1, Reason: "past the end"},
+	}
+
+	tests := map[string]struct {
+		opts dot.ErrorOptions
+		want string
+	}{
+		"Text": {
+			want: "2:4: invalid character\n" +
+				"\t\tA ! B\n" +
+				"\t\t  ^\n" +
+				"3:7: missing closing quote\n" +
+				"\t\tC -- \"D\n" +
+				"\t\t     ^\n" +
+				"9:1: past the end\n",
+		},
+		"TextWithFilename": {
+			opts: dot.ErrorOptions{Filename: "graph.dot"},
+			want: "graph.dot:2:4: invalid character\n" +
+				"\t\tA ! B\n" +
+				"\t\t  ^\n" +
+				"graph.dot:3:7: missing closing quote\n" +
+				"\t\tC -- \"D\n" +
+				"\t\t     ^\n" +
+				"graph.dot:9:1: past the end\n",
+		},
+		"JSON": {
+			opts: dot.ErrorOptions{Format: dot.ErrorJSON, Filename: "graph.dot"},
+			want: `[{"filename":"graph.dot","line":2,"column":4,"reason":"invalid character","source":"\tA ! B"},` +
+				`{"filename":"graph.dot","line":3,"column":7,"reason":"missing closing quote","source":"\tC -- \"D"},` +
+				`{"filename":"graph.dot","line":9,"column":1,"reason":"past the end","source":""}]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := dot.FormatErrors(errs, []byte(src), test.opts)
+
+			require.NoErrorf(t, err, "FormatErrors(%v)", test.opts)
+			assert.EqualValuesf(t, got, test.want, "FormatErrors(%v)", test.opts)
+		})
+	}
+}
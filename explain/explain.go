@@ -0,0 +1,225 @@
+// Package explain describes dot graphs in prose. The description helps reviewing generated graphs
+// without having to render them.
+package explain
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// maxHubs is the maximum number of hubs listed.
+const maxHubs = 5
+
+// minHubDegree is the minimum degree of a node to be considered a hub.
+const minHubDegree = 3
+
+// Write writes a markdown description of the graph g to w. It describes the kind of graph, its
+// clusters, its hubs which are the nodes with the highest degree, the sources and sinks of directed
+// graphs and nodes without any edges. Edges connecting subgraphs are counted as the edges between
+// each pair of their nodes as Graphviz does.
+func Write(w io.Writer, g ast.Graph) error {
+	s := newSummary(g)
+	bw := bufio.NewWriter(w)
+
+	kind := "graph"
+	if g.Directed {
+		kind = "digraph"
+	}
+	if g.IsStrict() {
+		kind = "strict " + kind
+	}
+	fmt.Fprint(bw, "# ", kind)
+	if g.ID != nil {
+		fmt.Fprint(bw, " ", g.ID.Unquoted())
+	}
+	fmt.Fprint(bw, "\n\n")
+
+	kind = "Undirected"
+	if g.Directed {
+		kind = "Directed"
+	}
+	fmt.Fprintf(bw, "%s graph with %s, %s and %s.\n", kind,
+		plural(len(s.nodes), "node"), plural(len(s.edges), "edge"), plural(len(s.clusters), "cluster"))
+
+	if len(s.clusters) > 0 {
+		fmt.Fprint(bw, "\n## Clusters\n\n")
+		for _, c := range s.clusters {
+			fmt.Fprintf(bw, "- %s", c.name)
+			if c.label != "" {
+				fmt.Fprintf(bw, " %q", c.label)
+			}
+			fmt.Fprintf(bw, " with %s\n", plural(c.nodes, "node"))
+		}
+	}
+
+	if hubs := s.hubs(); len(hubs) > 0 {
+		fmt.Fprint(bw, "\n## Hubs\n\n")
+		for _, n := range hubs {
+			fmt.Fprintf(bw, "- %s with %s", n, plural(s.in[n]+s.out[n], "edge"))
+			if g.Directed {
+				fmt.Fprintf(bw, " (%d in, %d out)", s.in[n], s.out[n])
+			}
+			fmt.Fprintln(bw)
+		}
+	}
+
+	if g.Directed {
+		writeList(bw, "Sources", s.filter(func(n string) bool { return s.in[n] == 0 && s.out[n] > 0 }))
+		writeList(bw, "Sinks", s.filter(func(n string) bool { return s.out[n] == 0 && s.in[n] > 0 }))
+	}
+	writeList(bw, "Isolated nodes", s.filter(func(n string) bool { return s.in[n]+s.out[n] == 0 }))
+
+	return bw.Flush()
+}
+
+func writeList(bw *bufio.Writer, title string, nodes []string) {
+	if len(nodes) == 0 {
+		return
+	}
+	fmt.Fprintf(bw, "\n## %s\n\n", title)
+	for _, n := range nodes {
+		fmt.Fprintf(bw, "- %s\n", n)
+	}
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+type cluster struct {
+	name, label string
+	nodes       int
+}
+
+type edge struct {
+	tail, head string
+}
+
+type summary struct {
+	nodes    []string // nodes in the order they first appear
+	seen     map[string]bool
+	edges    []edge
+	in, out  map[string]int
+	clusters []cluster
+}
+
+func newSummary(g ast.Graph) *summary {
+	s := &summary{
+		seen: make(map[string]bool),
+		in:   make(map[string]int),
+		out:  make(map[string]int),
+	}
+
+	ast.Inspect(g, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case ast.NodeID:
+			if id := n.ID.Unquoted(); !s.seen[id] {
+				s.seen[id] = true
+				s.nodes = append(s.nodes, id)
+			}
+			return false
+		case *ast.EdgeStmt:
+			tails := operandNodes(n.Left)
+			for cur := &n.Right; cur != nil; cur = cur.Next {
+				heads := operandNodes(cur.Right)
+				for _, tail := range tails {
+					for _, head := range heads {
+						s.edges = append(s.edges, edge{tail: tail, head: head})
+						s.out[tail]++
+						s.in[head]++
+					}
+				}
+				tails = heads
+			}
+		case ast.Subgraph:
+			if n.ID != nil && strings.HasPrefix(n.ID.Unquoted(), "cluster") {
+				s.clusters = append(s.clusters, cluster{
+					name:  n.ID.Unquoted(),
+					label: label(n.Stmts),
+					nodes: len(operandNodes(n)),
+				})
+			}
+		case *ast.AttrList, ast.Attribute:
+			return false
+		}
+		return true
+	})
+
+	return s
+}
+
+// hubs returns the nodes with the highest degree.
+func (s *summary) hubs() []string {
+	hubs := s.filter(func(n string) bool { return s.in[n]+s.out[n] >= minHubDegree })
+	slices.SortStableFunc(hubs, func(a, b string) int {
+		return cmp.Compare(s.in[b]+s.out[b], s.in[a]+s.out[a])
+	})
+	return hubs[:min(len(hubs), maxHubs)]
+}
+
+// filter returns the nodes matching the predicate in the order they first appear.
+func (s *summary) filter(predicate func(string) bool) []string {
+	var result []string
+	for _, n := range s.nodes {
+		if predicate(n) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// operandNodes returns the IDs of the nodes of an edge operand. These are all the nodes within a
+// subgraph.
+func operandNodes(operand ast.EdgeOperand) []string {
+	var result []string
+	seen := make(map[string]bool)
+	ast.Inspect(operand, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case ast.NodeID:
+			if id := n.ID.Unquoted(); !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+			}
+			return false
+		case *ast.AttrList, ast.Attribute:
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// label returns the label set by the statements of a subgraph.
+func label(stmts []ast.Stmt) string {
+	var result string
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			if st.Name.Unquoted() == "label" {
+				result = st.Value.Unquoted()
+			}
+		case *ast.AttrStmt:
+			if token.Lookup(st.ID.Literal) != token.Graph {
+				continue
+			}
+			for cur := &st.AttrList; cur != nil; cur = cur.Next {
+				for aList := cur.AList; aList != nil; aList = aList.Next {
+					if aList.Attribute.Name.Unquoted() == "label" {
+						result = aList.Attribute.Value.Unquoted()
+					}
+				}
+			}
+		}
+	}
+	return result
+}
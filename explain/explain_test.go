@@ -0,0 +1,90 @@
+package explain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/explain"
+)
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"Empty": {
+			in: `graph {}`,
+			want: `# graph
+
+Undirected graph with 0 nodes, 0 edges and 0 clusters.
+`,
+		},
+		"Directed": {
+			in: `strict digraph "build pipeline" {
+	subgraph cluster_ci {
+		label="CI"
+		lint; test
+	}
+	checkout -> {lint test build}
+	{lint test} -> build -> publish
+	docs
+}`,
+			want: `# strict digraph build pipeline
+
+Directed graph with 6 nodes, 6 edges and 1 cluster.
+
+## Clusters
+
+- cluster_ci "CI" with 2 nodes
+
+## Hubs
+
+- build with 4 edges (3 in, 1 out)
+- checkout with 3 edges (0 in, 3 out)
+
+## Sources
+
+- checkout
+
+## Sinks
+
+- publish
+
+## Isolated nodes
+
+- docs
+`,
+		},
+		"Undirected": {
+			in: `graph {
+	A -- B -- C
+	A -- C
+	B -- D
+}`,
+			want: `# graph
+
+Undirected graph with 4 nodes, 4 edges and 0 clusters.
+
+## Hubs
+
+- B with 3 edges
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			var out strings.Builder
+			err = explain.Write(&out, g)
+
+			require.NoErrorf(t, err, "Write(%q)", test.in)
+			assert.EqualValuesf(t, out.String(), test.want, "Write(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// Package geometry estimates the size of rendered dot nodes without calling Graphviz. Sizes are
+// computed from the label, fontsize, shape and margin using the defaults of Graphviz. The estimates
+// are approximations as the actual size depends on the fonts available to the renderer.
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// Defaults used by Graphviz as documented in https://graphviz.org/doc/info/attrs.html.
+const (
+	DefaultFontSize = 14.0  // DefaultFontSize is the default fontsize in points.
+	DefaultWidth    = 0.75  // DefaultWidth is the default and minimum width of a node in inches.
+	DefaultHeight   = 0.5   // DefaultHeight is the default and minimum height of a node in inches.
+	DefaultMarginX  = 0.11  // DefaultMarginX is the default horizontal margin around a label in inches.
+	DefaultMarginY  = 0.055 // DefaultMarginY is the default vertical margin around a label in inches.
+	PointsPerInch   = 72.0  // PointsPerInch converts between points used by fontsize and inches.
+	lineSpacing     = 1.2   // lineSpacing is the factor of the fontsize taken up by a line of text.
+)
+
+// Size is the width and height of a box in inches.
+type Size struct {
+	Width, Height float64
+}
+
+// NodeSize estimates the size of the node with given name and attributes as rendered by Graphviz.
+// The label defaults to \N which is the name of the node. The attributes label, fontsize, shape,
+// margin, width, height and fixedsize are considered. Invalid attribute values are ignored in favor
+// of the defaults like Graphviz does. The last attribute wins if an attribute is given multiple
+// times.
+func NodeSize(name string, attrs []ast.Attribute) Size {
+	values := make(map[string]string, len(attrs))
+	for _, attr := range attrs {
+		values[attr.Name.Unquoted()] = attr.Value.Unquoted()
+	}
+
+	label := `\N`
+	if v, ok := values["label"]; ok {
+		label = v
+	}
+	label = strings.ReplaceAll(label, `\N`, name)
+	fontSize := parseFloat(values["fontsize"], DefaultFontSize)
+	shape := strings.ToLower(values["shape"])
+	if shape == "" {
+		shape = "ellipse"
+	}
+	minWidth := parseFloat(values["width"], DefaultWidth)
+	minHeight := parseFloat(values["height"], DefaultHeight)
+	if isTrue(values["fixedsize"]) || values["fixedsize"] == "shape" {
+		return Size{Width: minWidth, Height: minHeight}
+	}
+
+	text := TextSize(label, fontSize)
+	marginX, marginY := parseMargin(values["margin"])
+	if shape == "plain" {
+		marginX, marginY = 0, 0
+	}
+	size := Size{Width: text.Width + 2*marginX, Height: text.Height + 2*marginY}
+
+	switch shape {
+	case "plain":
+		// plain nodes are sized to fit their label only
+		return size
+	case "ellipse", "oval":
+		// an ellipse enclosing a rectangle with the same aspect ratio is larger by a factor of √2
+		size.Width *= math.Sqrt2
+		size.Height *= math.Sqrt2
+	case "circle", "doublecircle":
+		diameter := math.Max(size.Width, size.Height) * math.Sqrt2
+		size = Size{Width: diameter, Height: diameter}
+		minWidth = math.Max(minWidth, minHeight)
+		minHeight = minWidth
+	case "diamond", "mdiamond":
+		// a diamond enclosing a rectangle at its center is twice its width and height
+		size.Width *= 2
+		size.Height *= 2
+	}
+
+	return Size{
+		Width:  math.Max(size.Width, minWidth),
+		Height: math.Max(size.Height, minHeight),
+	}
+}
+
+// TextSize estimates the size in inches of the text set in given fontsize using the average
+// character widths of the default Times-Roman font. Lines are separated by newlines or one of the
+// escape sequences \n, \l and \r.
+func TextSize(text string, fontSize float64) Size {
+	text = strings.NewReplacer(`\n`, "\n", `\l`, "\n", `\r`, "\n").Replace(text)
+	lines := strings.Split(text, "\n")
+	// a trailing line break like in "a\l" does not add an empty line
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var width float64
+	for _, line := range lines {
+		var w float64
+		for _, r := range line {
+			w += charWidth(r)
+		}
+		width = math.Max(width, w)
+	}
+
+	return Size{
+		Width:  width * fontSize / PointsPerInch,
+		Height: float64(len(lines)) * fontSize * lineSpacing / PointsPerInch,
+	}
+}
+
+// charWidth returns the approximate width of the rune in Times-Roman as a fraction of the
+// fontsize.
+func charWidth(r rune) float64 {
+	switch {
+	case r == ' ':
+		return 0.25
+	case r == 'i' || r == 'j' || r == 'l' || r == 't' || r == 'f' || r == 'r' || r == '.' || r == ',' || r == ':' || r == ';' || r == '\'':
+		return 0.3
+	case r == 'm' || r == 'w':
+		return 0.72
+	case r == 'M' || r == 'W':
+		return 0.92
+	case r >= 'A' && r <= 'Z':
+		return 0.68
+	case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		return 0.5
+	}
+	return 0.56
+}
+
+// parseMargin parses the margin attribute given as a single value for both or as x,y.
+func parseMargin(value string) (float64, float64) {
+	x, y, ok := strings.Cut(value, ",")
+	if !ok {
+		margin := parseFloat(value, -1)
+		if margin < 0 {
+			return DefaultMarginX, DefaultMarginY
+		}
+		return margin, margin
+	}
+	return parseFloat(x, DefaultMarginX), parseFloat(y, DefaultMarginY)
+}
+
+// parseFloat parses a non-negative float returning the default if the value is invalid.
+func parseFloat(value string, defaultValue float64) float64 {
+	f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || f < 0 {
+		return defaultValue
+	}
+	return f
+}
+
+// isTrue determines if the value of a boolean attribute is true as interpreted by Graphviz. True
+// and yes in any case as well as non-zero integers are true.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes":
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n != 0
+}
+
+// Point is a position in points as used by the pos attribute of Graphviz. The origin is in the
+// lower-left corner.
+type Point struct {
+	X, Y float64
+}
+
+// ParsePoint parses a point like 27,18 as used by the pos attribute of nodes. A trailing ! marking
+// the position as fixed is ignored. A third coordinate is ignored as well.
+func ParsePoint(in string) (Point, error) {
+	in = strings.TrimSuffix(strings.TrimSpace(in), "!")
+	coords := strings.Split(in, ",")
+	if len(coords) != 2 && len(coords) != 3 {
+		return Point{}, fmt.Errorf("invalid point %q: must be of the form x,y", in)
+	}
+	x, err := strconv.ParseFloat(coords[0], 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid point %q: %w", in, err)
+	}
+	y, err := strconv.ParseFloat(coords[1], 64)
+	if err != nil {
+		return Point{}, fmt.Errorf("invalid point %q: %w", in, err)
+	}
+	return Point{X: x, Y: y}, nil
+}
+
+// Midpoint returns the point halfway between a and b.
+func Midpoint(a, b Point) Point {
+	return Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// Distance returns the euclidean distance between a and b.
+func Distance(a, b Point) float64 {
+	return math.Hypot(b.X-a.X, b.Y-a.Y)
+}
+
+// Spline is the B-spline of an edge as given by its pos attribute after layout. Points holds 3n+1
+// control points describing n cubic Bézier curves.
+type Spline struct {
+	Start  *Point  // Start is the optional tip of the arrowhead at the tail of the edge.
+	End    *Point  // End is the optional tip of the arrowhead at the head of the edge.
+	Points []Point // Points are the control points.
+}
+
+// ParseSpline parses the pos attribute of an edge like e,27,36.1 27,71.7 27,63.9 27,54.8 27,46.2.
+// Only the first spline is parsed if the edge has multiple ones separated by a ';'.
+func ParseSpline(in string) (Spline, error) {
+	in, _, _ = strings.Cut(in, ";")
+	var s Spline
+	for _, field := range strings.Fields(in) {
+		switch {
+		case strings.HasPrefix(field, "s,"):
+			p, err := ParsePoint(field[2:])
+			if err != nil {
+				return Spline{}, err
+			}
+			s.Start = &p
+		case strings.HasPrefix(field, "e,"):
+			p, err := ParsePoint(field[2:])
+			if err != nil {
+				return Spline{}, err
+			}
+			s.End = &p
+		default:
+			p, err := ParsePoint(field)
+			if err != nil {
+				return Spline{}, err
+			}
+			s.Points = append(s.Points, p)
+		}
+	}
+	if len(s.Points) < 4 || (len(s.Points)-1)%3 != 0 {
+		return Spline{}, fmt.Errorf("invalid spline %q: must have 3n+1 control points with n >= 1", in)
+	}
+	return s, nil
+}
+
+// Midpoint returns the point in the middle of the spline. This is the middle of the Bézier curve
+// in the middle or the point joining the two curves in the middle.
+func (s Spline) Midpoint() Point {
+	curves := (len(s.Points) - 1) / 3
+	if curves%2 == 0 {
+		return s.Points[3*curves/2]
+	}
+	p := s.Points[3*(curves/2):]
+	// cubic Bézier curve at t=0.5
+	return Point{
+		X: (p[0].X + 3*p[1].X + 3*p[2].X + p[3].X) / 8,
+		Y: (p[0].Y + 3*p[1].Y + 3*p[2].Y + p[3].Y) / 8,
+	}
+}
@@ -0,0 +1,196 @@
+package geometry_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/geometry"
+)
+
+func TestNodeSize(t *testing.T) {
+	tests := map[string]struct {
+		name  string
+		attrs map[string]string
+		want  geometry.Size
+	}{
+		"DefaultsToMinimumSize": {
+			name: "A",
+			want: geometry.Size{Width: 0.75, Height: 0.5},
+		},
+		"LongLabelInEllipse": {
+			name:  "A",
+			attrs: map[string]string{"label": "a long label text"},
+			// 17 runes of 0.25, 0.3 and 0.5 em totalling 6.75 em
+			want: geometry.Size{Width: (6.75*14/72 + 0.22) * math.Sqrt2, Height: 0.5},
+		},
+		"LabelDefaultsToName": {
+			name:  "a long label text",
+			attrs: map[string]string{"shape": "box"},
+			want:  geometry.Size{Width: 6.75*14/72 + 0.22, Height: 0.5},
+		},
+		"FontSizeAndMargin": {
+			name:  "A",
+			attrs: map[string]string{"shape": "box", "label": "Hello\\nWorld", "fontsize": "36", "margin": "0.5,0.25"},
+			// World is the widest line at 2.52 em
+			want: geometry.Size{Width: 2.52*36/72 + 1, Height: 2*36*1.2/72 + 0.5},
+		},
+		"SingleMargin": {
+			name:  "Hello",
+			attrs: map[string]string{"shape": "box", "margin": "0.5"},
+			want:  geometry.Size{Width: 2.28*14/72 + 1, Height: 14*1.2/72 + 1},
+		},
+		"PlainHasNoMarginOrMinimumSize": {
+			name:  "Hello",
+			attrs: map[string]string{"shape": "plain", "margin": "1"},
+			want:  geometry.Size{Width: 2.28 * 14 / 72, Height: 14 * 1.2 / 72},
+		},
+		"Circle": {
+			name:  "a long label text",
+			attrs: map[string]string{"shape": "circle"},
+			want: geometry.Size{
+				Width:  (6.75*14/72 + 0.22) * math.Sqrt2,
+				Height: (6.75*14/72 + 0.22) * math.Sqrt2,
+			},
+		},
+		"Diamond": {
+			name:  "Hello",
+			attrs: map[string]string{"shape": "diamond"},
+			want:  geometry.Size{Width: (2.28*14/72 + 0.22) * 2, Height: (14*1.2/72 + 0.11) * 2},
+		},
+		"FixedSize": {
+			name:  "a long label text",
+			attrs: map[string]string{"fixedsize": "true", "width": "0.3", "height": "0.2"},
+			want:  geometry.Size{Width: 0.3, Height: 0.2},
+		},
+		"InvalidValuesFallBackToDefaults": {
+			name:  "A",
+			attrs: map[string]string{"fontsize": "big", "width": "-1", "margin": "wide"},
+			want:  geometry.Size{Width: 0.75, Height: 0.5},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attrs []ast.Attribute
+			for name, value := range test.attrs {
+				attrs = append(attrs, ast.Attribute{Name: ast.ID{Literal: name}, Value: ast.ID{Literal: `"` + value + `"`}})
+			}
+
+			got := geometry.NodeSize(test.name, attrs)
+
+			assert.Truef(t, approxEqual(got, test.want), "NodeSize(%q, %v) = %v, want %v", test.name, test.attrs, got, test.want)
+		})
+	}
+}
+
+func TestTextSize(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want geometry.Size
+	}{
+		"Empty": {
+			in:   "",
+			want: geometry.Size{Width: 0, Height: 14 * 1.2 / 72},
+		},
+		"LeftJustifiedLines": {
+			in:   `a\lbb\l`,
+			want: geometry.Size{Width: 1 * 14 / 72.0, Height: 2 * 14 * 1.2 / 72},
+		},
+		"Newlines": {
+			in:   "W\nW\nW",
+			want: geometry.Size{Width: 0.92 * 14 / 72, Height: 3 * 14 * 1.2 / 72},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := geometry.TextSize(test.in, 14)
+
+			assert.Truef(t, approxEqual(got, test.want), "TextSize(%q, 14) = %v, want %v", test.in, got, test.want)
+		})
+	}
+}
+
+func approxEqual(a, b geometry.Size) bool {
+	return math.Abs(a.Width-b.Width) < 1e-9 && math.Abs(a.Height-b.Height) < 1e-9
+}
+
+func TestParsePoint(t *testing.T) {
+	tests := map[string]geometry.Point{
+		"27,18":     {X: 27, Y: 18},
+		"27.5,-18!": {X: 27.5, Y: -18},
+		"1,2,3":     {X: 1, Y: 2},
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			got, err := geometry.ParsePoint(in)
+
+			require.NoErrorf(t, err, "ParsePoint(%q)", in)
+			assert.EqualValuesf(t, got, want, "ParsePoint(%q)", in)
+		})
+	}
+
+	for _, in := range []string{"", "27", "a,b", "1,2,3,4"} {
+		_, err := geometry.ParsePoint(in)
+		require.NotNilf(t, err, "ParsePoint(%q)", in)
+	}
+}
+
+func TestSpline(t *testing.T) {
+	t.Run("SingleCurve", func(t *testing.T) {
+		in := "e,27,36.104 27,71.697 27,63.983 27,54.712 27,46.112"
+
+		got, err := geometry.ParseSpline(in)
+
+		require.NoErrorf(t, err, "ParseSpline(%q)", in)
+		assert.EqualValuesf(t, got, geometry.Spline{
+			End: &geometry.Point{X: 27, Y: 36.104},
+			Points: []geometry.Point{
+				{X: 27, Y: 71.697},
+				{X: 27, Y: 63.983},
+				{X: 27, Y: 54.712},
+				{X: 27, Y: 46.112},
+			},
+		}, "ParseSpline(%q)", in)
+		assert.Truef(t, approxEqualPoint(got.Midpoint(), geometry.Point{X: 27, Y: 59.23675}), "Midpoint() = %v", got.Midpoint())
+	})
+
+	t.Run("TwoCurvesMeetInTheMiddle", func(t *testing.T) {
+		in := "s,0,0 0,0 1,1 2,2 3,3 4,4 5,5 6,6;7,7 8,8 9,9 10,10"
+
+		got, err := geometry.ParseSpline(in)
+
+		require.NoErrorf(t, err, "ParseSpline(%q)", in)
+		assert.EqualValuesf(t, got.Start, &geometry.Point{}, "ParseSpline(%q)", in)
+		assert.EqualValuesf(t, got.Midpoint(), geometry.Point{X: 3, Y: 3}, "Midpoint()")
+	})
+
+	t.Run("ThreeCurves", func(t *testing.T) {
+		in := "0,0 1,0 2,0 3,0 4,0 5,0 6,0 7,0 8,0 9,0"
+
+		got, err := geometry.ParseSpline(in)
+
+		require.NoErrorf(t, err, "ParseSpline(%q)", in)
+		assert.Truef(t, approxEqualPoint(got.Midpoint(), geometry.Point{X: 4.5}), "Midpoint() = %v", got.Midpoint())
+	})
+
+	for _, in := range []string{"", "e,1,1 0,0 1,1", "0,0 1,1 2,2 3,3 4,4", "0,0 1,1 x 3,3"} {
+		_, err := geometry.ParseSpline(in)
+		require.NotNilf(t, err, "ParseSpline(%q)", in)
+	}
+}
+
+func TestMidpointAndDistance(t *testing.T) {
+	a, b := geometry.Point{X: 0, Y: 0}, geometry.Point{X: 6, Y: 8}
+
+	assert.EqualValuesf(t, geometry.Midpoint(a, b), geometry.Point{X: 3, Y: 4}, "Midpoint(%v, %v)", a, b)
+	assert.EqualValuesf(t, geometry.Distance(a, b), 10.0, "Distance(%v, %v)", a, b)
+}
+
+func approxEqualPoint(a, b geometry.Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
@@ -2,6 +2,9 @@ module github.com/teleivo/dot
 
 go 1.22.5
 
-require github.com/teleivo/assertive v0.0.0-20240807044559-4d52e9d98f38
+require (
+	github.com/teleivo/assertive v0.0.0-20240807044559-4d52e9d98f38
+	gonum.org/v1/gonum v0.15.1
+)
 
 require github.com/google/go-cmp v0.6.0 // indirect
@@ -0,0 +1,176 @@
+// Package gonum converts graphs between the semantic model of package graph and the graphs of
+// https://www.gonum.org so gonum algorithms can run on dot graphs.
+package gonum
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/graph"
+	gograph "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/encoding"
+	"gonum.org/v1/gonum/graph/multi"
+)
+
+// Node is a gonum node of a [graph.Node]. It implements the node interfaces of the gonum dot
+// encoding.
+type Node struct {
+	UID  int64       // UID is the gonum ID of the node.
+	Node *graph.Node // Node is the node of the semantic model.
+}
+
+// ID returns the gonum ID of the node.
+func (n Node) ID() int64 {
+	return n.UID
+}
+
+// DOTID returns the dot ID of the node.
+func (n Node) DOTID() string {
+	return n.Node.ID
+}
+
+// Attributes returns the attributes of the node.
+func (n Node) Attributes() []encoding.Attribute {
+	return attributes(n.Node.Attrs)
+}
+
+// Line is a gonum line of a [graph.Edge]. Lines allow parallel edges and self-loops like dot.
+type Line struct {
+	F, T Node        // F and T are the nodes the line is from and to.
+	UID  int64       // UID is the gonum ID of the line.
+	Edge *graph.Edge // Edge is the edge of the semantic model.
+}
+
+// From returns the node the line is from.
+func (l Line) From() gograph.Node {
+	return l.F
+}
+
+// To returns the node the line is to.
+func (l Line) To() gograph.Node {
+	return l.T
+}
+
+// ReversedLine returns the line with its nodes swapped.
+func (l Line) ReversedLine() gograph.Line {
+	return Line{F: l.T, T: l.F, UID: l.UID, Edge: l.Edge}
+}
+
+// ID returns the gonum ID of the line.
+func (l Line) ID() int64 {
+	return l.UID
+}
+
+// Attributes returns the attributes of the edge.
+func (l Line) Attributes() []encoding.Attribute {
+	return attributes(l.Edge.Attrs)
+}
+
+// Multigraph is a gonum multigraph that can be used wherever a gonum graph is expected.
+type Multigraph interface {
+	gograph.Graph
+	gograph.Multigraph
+}
+
+// FromGraph converts the graph into a gonum multigraph. The result is a [multi.DirectedGraph] if g
+// is directed and a [multi.UndirectedGraph] otherwise. Its nodes are of type [Node] with IDs in the
+// order of [graph.Graph.Nodes]. Its lines are of type [Line] with IDs in the order of
+// [graph.Graph.Edges].
+func FromGraph(g *graph.Graph) Multigraph {
+	var result interface {
+		Multigraph
+		AddNode(gograph.Node)
+		SetLine(gograph.Line)
+	}
+	if g.Directed {
+		result = multi.NewDirectedGraph()
+	} else {
+		result = multi.NewUndirectedGraph()
+	}
+
+	nodes := make(map[*graph.Node]Node, len(g.Nodes))
+	for i, n := range g.Nodes {
+		nodes[n] = Node{UID: int64(i), Node: n}
+		result.AddNode(nodes[n])
+	}
+	for i, e := range g.Edges {
+		result.SetLine(Line{F: nodes[e.Tail], T: nodes[e.Head], UID: int64(i), Edge: e})
+	}
+	return result
+}
+
+// ToGraph converts the gonum graph into a dot graph. The graph is directed if g implements
+// [gograph.Directed]. Nodes are identified by their DOTID if they implement one and by their gonum
+// ID otherwise. Attributes of nodes and edges implementing [encoding.Attributer] are kept. Nodes
+// are added in the order of their gonum ID followed by the edges in the order of the IDs of their
+// nodes and lines.
+func ToGraph(g gograph.Graph) ast.Graph {
+	_, directed := g.(gograph.Directed)
+	kind := dot.Undirected
+	if directed {
+		kind = dot.Directed
+	}
+	b := dot.NewGraphBuilder(kind)
+
+	nodes := sortedNodes(g.Nodes())
+	for _, n := range nodes {
+		b.Node(dotID(n), attrs(n)...)
+	}
+	for _, from := range nodes {
+		for _, to := range sortedNodes(g.From(from.ID())) {
+			if !directed && to.ID() < from.ID() { // undirected edges are reachable from both nodes
+				continue
+			}
+			if mg, ok := g.(gograph.Multigraph); ok {
+				lines := gograph.LinesOf(mg.Lines(from.ID(), to.ID()))
+				slices.SortFunc(lines, func(a, b gograph.Line) int {
+					return cmp.Compare(a.ID(), b.ID())
+				})
+				for _, line := range lines {
+					b.Edge(dotID(from), dotID(to), attrs(line)...)
+				}
+				continue
+			}
+			b.Edge(dotID(from), dotID(to), attrs(g.Edge(from.ID(), to.ID()))...)
+		}
+	}
+	return b.Build()
+}
+
+func sortedNodes(nodes gograph.Nodes) []gograph.Node {
+	result := gograph.NodesOf(nodes)
+	slices.SortFunc(result, func(a, b gograph.Node) int {
+		return cmp.Compare(a.ID(), b.ID())
+	})
+	return result
+}
+
+func dotID(n gograph.Node) string {
+	if n, ok := n.(interface{ DOTID() string }); ok {
+		return n.DOTID()
+	}
+	return strconv.FormatInt(n.ID(), 10)
+}
+
+func attrs(v any) []ast.Attribute {
+	attributer, ok := v.(encoding.Attributer)
+	if !ok {
+		return nil
+	}
+	var result []ast.Attribute
+	for _, a := range attributer.Attributes() {
+		result = append(result, dot.Attr(a.Key, a.Value))
+	}
+	return result
+}
+
+func attributes(attrs []graph.Attribute) []encoding.Attribute {
+	result := make([]encoding.Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		result = append(result, encoding.Attribute{Key: a.Name, Value: a.Value})
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package gonum_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/gonum"
+	"github.com/teleivo/dot/graph"
+	gograph "gonum.org/v1/gonum/graph"
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+func TestFromGraph(t *testing.T) {
+	in := `digraph {
+	node [shape=box]
+	main -> cli -> core
+	main -> core [style=dashed]
+	main -> core
+	core -> core
+}`
+	ast, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	g := gonum.FromGraph(graph.Build(ast))
+
+	assert.EqualValuesf(t, g.Nodes().Len(), 3, "FromGraph(%q).Nodes()", in)
+	var styles []string
+	for lines := g.Lines(0, 2); lines.Next(); {
+		line := lines.Line().(gonum.Line)
+		assert.EqualValuesf(t, line.F.DOTID(), "main", "FromGraph(%q).Lines(main, core)", in)
+		assert.EqualValuesf(t, line.T.DOTID(), "core", "FromGraph(%q).Lines(main, core)", in)
+		styles = append(styles, line.Edge.Attr("style").String())
+	}
+	slices.Sort(styles)
+	assert.EqualValuesf(t, styles, []string{"", "dashed"}, "FromGraph(%q).Lines(main, core)", in)
+	assert.EqualValuesf(t, g.Node(1).(gonum.Node).Attributes()[0].Value, "box", "FromGraph(%q).Node(cli)", in)
+
+	sorted, err := topo.Sort(g.(gograph.Directed))
+	require.NoErrorf(t, err, "topo.Sort(FromGraph(%q))", in)
+	var order []string
+	for _, n := range sorted {
+		order = append(order, n.(gonum.Node).DOTID())
+	}
+	assert.EqualValuesf(t, order, []string{"main", "cli", "core"}, "topo.Sort(FromGraph(%q))", in)
+}
+
+func TestToGraph(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		in := `graph {
+	a [color=red]
+	b
+	c
+	a -- b [label=ab]
+	a -- b
+	c -- b
+}`
+		ast, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+
+		got := gonum.ToGraph(gonum.FromGraph(graph.Build(ast)))
+
+		assert.EqualValuesf(t, got.String(), `graph {
+	a [color=red]
+	b
+	c
+	a -- b [label=ab]
+	a -- b
+	b -- c
+}`, "ToGraph(FromGraph(%q))", in)
+	})
+
+	t.Run("SimpleGraph", func(t *testing.T) {
+		g := simple.NewDirectedGraph()
+		g.SetEdge(g.NewEdge(simple.Node(2), simple.Node(1)))
+		g.SetEdge(g.NewEdge(simple.Node(1), simple.Node(3)))
+
+		got := gonum.ToGraph(g)
+
+		assert.EqualValuesf(t, got.String(), `digraph {
+	1
+	2
+	3
+	1 -> 3
+	2 -> 1
+}`, "ToGraph()")
+	})
+}
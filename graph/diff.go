@@ -0,0 +1,324 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// ChangeKind is the kind of a [Change] between two graphs.
+type ChangeKind int
+
+const (
+	GraphAttrChanged ChangeKind = iota // GraphAttrChanged is a graph attribute that is set, unset or set to a different value.
+	NodeAdded                          // NodeAdded is a node only in the new graph.
+	NodeRemoved                        // NodeRemoved is a node only in the old graph.
+	NodeRenamed                        // NodeRenamed is a node with a different ID but the same attributes and edges.
+	NodeAttrChanged                    // NodeAttrChanged is a node attribute that is set, unset or set to a different value.
+	EdgeAdded                          // EdgeAdded is an edge only in the new graph.
+	EdgeRemoved                        // EdgeRemoved is an edge only in the old graph.
+	EdgeAttrChanged                    // EdgeAttrChanged is an edge attribute that is set, unset or set to a different value.
+)
+
+var changeKindStrings = map[ChangeKind]string{
+	GraphAttrChanged: "graph-attr-changed",
+	NodeAdded:        "node-added",
+	NodeRemoved:      "node-removed",
+	NodeRenamed:      "node-renamed",
+	NodeAttrChanged:  "node-attr-changed",
+	EdgeAdded:        "edge-added",
+	EdgeRemoved:      "edge-removed",
+	EdgeAttrChanged:  "edge-attr-changed",
+}
+
+func (k ChangeKind) String() string {
+	return changeKindStrings[k]
+}
+
+// MarshalText encodes the kind as its string so changes encode to readable JSON.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is a semantic difference between two graphs.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Element  string     `json:"element"`            // Element is the changed element. It is empty for the graph, the ID of a node like a or the endpoints of an edge like a -> b.
+	Renamed  string     `json:"renamed,omitempty"`  // Renamed is the ID of a renamed node in the new graph.
+	Attr     string     `json:"attr,omitempty"`     // Attr is the name of a changed attribute.
+	OldValue string     `json:"oldValue,omitempty"` // OldValue is the value of a changed attribute in the old graph. It is empty if it was not set.
+	NewValue string     `json:"newValue,omitempty"` // NewValue is the value of a changed attribute in the new graph. It is empty if it is not set.
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case NodeAdded:
+		return "+ node " + c.Element
+	case NodeRemoved:
+		return "- node " + c.Element
+	case NodeRenamed:
+		return "~ node " + c.Element + " renamed to " + c.Renamed
+	case EdgeAdded:
+		return "+ edge " + c.Element
+	case EdgeRemoved:
+		return "- edge " + c.Element
+	case NodeAttrChanged:
+		return fmt.Sprintf("~ node %s %s: %q -> %q", c.Element, c.Attr, c.OldValue, c.NewValue)
+	case EdgeAttrChanged:
+		return fmt.Sprintf("~ edge %s %s: %q -> %q", c.Element, c.Attr, c.OldValue, c.NewValue)
+	}
+	return fmt.Sprintf("~ graph %s: %q -> %q", c.Attr, c.OldValue, c.NewValue)
+}
+
+// Diff compares the graphs semantically. Formatting, the order of statements and how nodes, edges
+// and attributes are declared do not matter. Nodes are compared by their ID and edges by their
+// endpoints including ports. Attributes are compared by the value in effect so a node that gets
+// its color from a node attribute statement in one graph and from its attribute list in the other
+// is unchanged. Graph attributes only include the ones of the root graph.
+//
+// A removed node and an added node are reported as a rename if they have the same non-empty
+// attributes and the same edges to the same nodes and no other removed or added node does. Edges of
+// renamed nodes are not reported as removed and added.
+//
+// The kind of graph is not compared. Edges are described using the edge operator of the new graph.
+//
+// Changes are returned in the order: graph attributes, removed, renamed and added nodes, node
+// attributes, removed and added edges and edge attributes. Removed elements are ordered as they
+// appear in the old graph, all others as in the new graph.
+func Diff(old, new *Graph) []Change {
+	var result []Change
+	result = appendAttrChanges(result, GraphAttrChanged, "", old.Attrs, new.Attrs)
+
+	var removed, added []*Node
+	for _, n := range old.Nodes {
+		if _, ok := new.Node(n.ID); !ok {
+			removed = append(removed, n)
+		}
+	}
+	for _, n := range new.Nodes {
+		if _, ok := old.Node(n.ID); !ok {
+			added = append(added, n)
+		}
+	}
+	renamed := renames(old, new, removed, added)
+	isRenamed := make(map[string]bool) // isRenamed holds the new IDs of renamed nodes
+	for _, newID := range renamed {
+		isRenamed[newID] = true
+	}
+
+	for _, n := range removed {
+		if _, ok := renamed[n.ID]; !ok {
+			result = append(result, Change{Kind: NodeRemoved, Element: n.ID})
+		}
+	}
+	for _, n := range removed {
+		if newID, ok := renamed[n.ID]; ok {
+			result = append(result, Change{Kind: NodeRenamed, Element: n.ID, Renamed: newID})
+		}
+	}
+	for _, n := range added {
+		if !isRenamed[n.ID] {
+			result = append(result, Change{Kind: NodeAdded, Element: n.ID})
+		}
+	}
+	for _, n := range new.Nodes {
+		if o, ok := old.Node(n.ID); ok {
+			result = appendAttrChanges(result, NodeAttrChanged, n.ID, o.Attrs, n.Attrs)
+		}
+	}
+
+	// edges are matched by their key in the new graph. Multi-edges are matched in order.
+	same := func(id string) string { return id }
+	rename := func(id string) string {
+		if newID, ok := renamed[id]; ok {
+			return newID
+		}
+		return id
+	}
+	oldEdges := make(map[string][]*Edge)
+	for _, e := range old.Edges {
+		key := edgeKey(e, new.Directed, rename)
+		oldEdges[key] = append(oldEdges[key], e)
+	}
+	newEdges := make(map[string][]*Edge)
+	for _, e := range new.Edges {
+		key := edgeKey(e, new.Directed, same)
+		newEdges[key] = append(newEdges[key], e)
+	}
+
+	seen := make(map[string]int)
+	for _, e := range old.Edges {
+		key := edgeKey(e, new.Directed, rename)
+		if seen[key] >= len(newEdges[key]) {
+			result = append(result, Change{Kind: EdgeRemoved, Element: edgeKey(e, old.Directed, same)})
+		}
+		seen[key]++
+	}
+	clear(seen)
+	var changed []Change
+	for _, e := range new.Edges {
+		key := edgeKey(e, new.Directed, same)
+		i := seen[key]
+		seen[key]++
+		if i >= len(oldEdges[key]) {
+			result = append(result, Change{Kind: EdgeAdded, Element: key})
+			continue
+		}
+		changed = appendAttrChanges(changed, EdgeAttrChanged, key, oldEdges[key][i].Attrs, e.Attrs)
+	}
+	return append(result, changed...)
+}
+
+// renames pairs removed with added nodes that have the same attributes and edges. It returns the
+// new ID by the old ID of each renamed node.
+func renames(old, new *Graph, removed, added []*Node) map[string]string {
+	result := make(map[string]string)
+	if len(removed) == 0 || len(added) == 0 {
+		return result
+	}
+
+	isRemoved := make(map[*Node]bool, len(removed))
+	for _, n := range removed {
+		isRemoved[n] = true
+	}
+	isAdded := make(map[*Node]bool, len(added))
+	for _, n := range added {
+		isAdded[n] = true
+	}
+	oldSignatures := signatures(old, isRemoved)
+	newSignatures := signatures(new, isAdded)
+
+	matches := make(map[string][]*Node)
+	for _, a := range added {
+		matches[newSignatures[a]] = append(matches[newSignatures[a]], a)
+	}
+	count := make(map[string]int)
+	for _, r := range removed {
+		count[oldSignatures[r]]++
+	}
+	for _, r := range removed {
+		sig := oldSignatures[r]
+		if sig != "" && count[sig] == 1 && len(matches[sig]) == 1 {
+			result[r.ID] = matches[sig][0].ID
+		}
+	}
+	return result
+}
+
+// signatures describes the given nodes of the graph by their attributes and edges. Nodes without
+// attributes and edges have an empty signature as they cannot be told apart.
+func signatures(g *Graph, nodes map[*Node]bool) map[*Node]string {
+	edges := make(map[*Node][]string)
+	for _, e := range g.Edges {
+		for _, end := range []struct {
+			node, other *Node
+			dir         string
+		}{{e.Tail, e.Head, "out"}, {e.Head, e.Tail, "in"}} {
+			if !nodes[end.node] {
+				continue
+			}
+			other := end.other.ID
+			if end.other == end.node {
+				other = "" // self-loops do not depend on the ID
+			} else if nodes[end.other] {
+				other = "?" // the other end might be renamed as well
+			}
+			if !g.Directed {
+				end.dir = ""
+			}
+			edges[end.node] = append(edges[end.node], end.dir+" "+strconv.Quote(other)+" "+attrsKey(e.Attrs))
+			if e.Tail == e.Head {
+				break
+			}
+		}
+	}
+
+	result := make(map[*Node]string, len(nodes))
+	for n := range nodes {
+		if len(n.Attrs) == 0 && len(edges[n]) == 0 {
+			continue
+		}
+		slices.Sort(edges[n])
+		result[n] = attrsKey(n.Attrs) + "\n" + strings.Join(edges[n], "\n")
+	}
+	return result
+}
+
+// attrsKey describes the attributes in effect independent of the order they are set in.
+func attrsKey(attrs []Attribute) string {
+	values := effective(attrs)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var sb strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&sb, "%q=%q;", name, values[name])
+	}
+	return sb.String()
+}
+
+// edgeKey describes the edge by its endpoints and ports like a:p -> b. The endpoints of undirected
+// edges are sorted so a -- b and b -- a are the same edge.
+func edgeKey(e *Edge, directed bool, id func(string) string) string {
+	tail, head := withPort(id(e.Tail.ID), e.TailPort), withPort(id(e.Head.ID), e.HeadPort)
+	if directed {
+		return tail + " -> " + head
+	}
+	if head < tail {
+		tail, head = head, tail
+	}
+	return tail + " -- " + head
+}
+
+func withPort(id string, p *ast.Port) string {
+	if p == nil {
+		return id
+	}
+	result := id
+	if p.Name != nil {
+		result += ":" + p.Name.Unquoted()
+	}
+	if p.CompassPoint != nil {
+		result += ":" + p.CompassPoint.String()
+	}
+	return result
+}
+
+// effective returns the value in effect by attribute name. The last one set wins.
+func effective(attrs []Attribute) map[string]string {
+	result := make(map[string]string, len(attrs))
+	for _, a := range attrs {
+		result[a.Name] = a.Value
+	}
+	return result
+}
+
+// appendAttrChanges appends a change for every attribute whose value in effect differs between the
+// old and new attributes sorted by attribute name.
+func appendAttrChanges(changes []Change, kind ChangeKind, element string, old, new []Attribute) []Change {
+	oldValues, newValues := effective(old), effective(new)
+	var names []string
+	for name := range oldValues {
+		names = append(names, name)
+	}
+	for name := range newValues {
+		if _, ok := oldValues[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		oldValue, inOld := oldValues[name]
+		newValue, inNew := newValues[name]
+		if inOld != inNew || oldValue != newValue {
+			changes = append(changes, Change{Kind: kind, Element: element, Attr: name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,112 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+)
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		old  string
+		new  string
+		want []string
+	}{
+		"FormattingAndOrderDoNotMatter": {
+			old: `graph {
+	node [color=red]
+	a -- b
+	c
+}`,
+			new: `graph { c [color=red]; b [color=red]; a [color=red]; b -- a }`,
+		},
+		"Changes": {
+			old: `digraph {
+	rankdir=LR
+	node [shape=box]
+	a -> b [color=red]
+	b -> c
+	c -> d
+}`,
+			new: `digraph {
+	rankdir=TB
+	a [shape=box]
+	b [shape=box]
+	c [shape=circle]
+	b -> c
+	a -> b [color=blue]
+	a -> b
+	e
+}`,
+			want: []string{
+				`~ graph rankdir: "LR" -> "TB"`,
+				`- node d`,
+				`+ node e`,
+				`~ node c shape: "box" -> "circle"`,
+				`- edge c -> d`,
+				`+ edge a -> b`,
+				`~ edge a -> b color: "red" -> "blue"`,
+			},
+		},
+		"Ports": {
+			old: `digraph { a:p -> b }`,
+			new: `digraph { a -> b:n }`,
+			want: []string{
+				`- edge a:p -> b`,
+				`+ edge a -> b:n`,
+			},
+		},
+		"Rename": {
+			old: `digraph {
+	x [label=db]
+	x -> a
+	y -> a
+}`,
+			new: `digraph {
+	db [label=db]
+	db -> a
+	z -> a [color=red]
+}`,
+			want: []string{
+				`- node y`,
+				`~ node x renamed to db`,
+				`+ node z`,
+				`- edge y -> a`,
+				`+ edge z -> a`,
+			},
+		},
+		"AmbiguousRename": {
+			old: `digraph { x -> a; y -> a }`,
+			new: `digraph { v -> a; w -> a }`,
+			want: []string{
+				`- node x`,
+				`- node y`,
+				`+ node v`,
+				`+ node w`,
+				`- edge x -> a`,
+				`- edge y -> a`,
+				`+ edge v -> a`,
+				`+ edge w -> a`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			old, err := dot.Parse([]byte(test.old))
+			require.NoErrorf(t, err, "Parse(%q)", test.old)
+			new, err := dot.Parse([]byte(test.new))
+			require.NoErrorf(t, err, "Parse(%q)", test.new)
+
+			var got []string
+			for _, c := range graph.Diff(graph.Build(old), graph.Build(new)) {
+				got = append(got, c.String())
+			}
+
+			assert.EqualValuesf(t, got, test.want, "Diff(%q, %q)", test.old, test.new)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package graph
+
+import "github.com/teleivo/dot/ast"
+
+// Filter returns a copy of the AST of the graph with the nodes and edges that are kept. A nil func
+// keeps all nodes or edges. Edges of nodes that are not kept are dropped as well. The graph is left
+// unchanged.
+//
+// Statements that are kept entirely are kept as is so the filtered graph can be printed close to
+// its source. An edge statement of which only some edges are kept is replaced by a statement per
+// kept edge as described by [ast.ExpandEdges]. A kept node that first appeared in a statement that
+// is dropped is declared by a node statement in place of it. This keeps the node in the subgraphs
+// it was in and gives it the same node defaults. Subgraphs that are left without any node, edge or
+// subgraph are dropped.
+func (g *Graph) Filter(keepNode func(*Node) bool, keepEdge func(*Edge) bool) ast.Graph {
+	f := filter{
+		g:        g,
+		nodes:    make(map[*Node]bool, len(g.Nodes)),
+		edges:    make(map[*ast.EdgeStmt][]*Edge),
+		declared: make(map[*Node]bool),
+	}
+	for _, n := range g.Nodes {
+		f.nodes[n] = keepNode == nil || keepNode(n)
+	}
+	for _, e := range g.Edges {
+		f.edges[e.Stmt] = append(f.edges[e.Stmt], e)
+	}
+	f.keepEdge = func(e *Edge) bool {
+		return f.nodes[e.Tail] && f.nodes[e.Head] && (keepEdge == nil || keepEdge(e))
+	}
+
+	result := g.AST
+	result.Stmts, _ = f.stmts(g.AST.Stmts)
+	return result
+}
+
+type filter struct {
+	g        *Graph
+	nodes    map[*Node]bool            // nodes indicates which nodes are kept
+	keepEdge func(*Edge) bool          // keepEdge reports whether an edge is kept
+	edges    map[*ast.EdgeStmt][]*Edge // edges holds the edges declared by an edge statement
+	declared map[*Node]bool            // declared holds the nodes that appeared in the filtered statements
+}
+
+// stmts filters the statements. It reports whether any node, edge or subgraph is left.
+func (f *filter) stmts(stmts []ast.Stmt) ([]ast.Stmt, bool) {
+	var result []ast.Stmt
+	var hasElements bool
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *ast.NodeStmt:
+			n := f.node(st.NodeID)
+			if !f.nodes[n] {
+				continue
+			}
+			f.declared[n] = true
+		case *ast.EdgeStmt:
+			kept := f.edgeStmt(st)
+			hasElements = hasElements || len(kept) > 0
+			result = append(result, kept...)
+			continue
+		case ast.Subgraph:
+			subgraph, ok := f.subgraph(st)
+			if !ok {
+				continue
+			}
+			stmt = subgraph
+		default:
+			result = append(result, stmt)
+			continue
+		}
+		hasElements = true
+		result = append(result, stmt)
+	}
+	return result, hasElements
+}
+
+func (f *filter) subgraph(subgraph ast.Subgraph) (ast.Subgraph, bool) {
+	var ok bool
+	subgraph.Stmts, ok = f.stmts(subgraph.Stmts)
+	return subgraph, ok
+}
+
+// edgeStmt filters the edge statement. The statement is returned as is if all its nodes and edges
+// are kept.
+func (f *filter) edgeStmt(stmt *ast.EdgeStmt) []ast.Stmt {
+	var nodes []*Node
+	var ids []ast.ID
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case ast.NodeID:
+			nodes = append(nodes, f.node(n))
+			ids = append(ids, n.ID)
+			return false
+		case *ast.AttrList, ast.Attribute:
+			return false
+		}
+		return true
+	})
+
+	expanded := ast.ExpandEdges(stmt)
+	edges := make([]*Edge, len(expanded))
+	all := true
+	for i, es := range expanded {
+		edges[i] = f.edge(stmt, i, len(expanded), es)
+		all = all && f.keepEdge(edges[i])
+	}
+	for _, n := range nodes {
+		all = all && f.nodes[n]
+	}
+	if all {
+		for _, n := range nodes {
+			f.declared[n] = true
+		}
+		return []ast.Stmt{stmt}
+	}
+
+	var result []ast.Stmt
+	kept := make(map[*Node]bool)
+	for _, e := range edges {
+		if f.keepEdge(e) {
+			kept[e.Tail], kept[e.Head] = true, true
+		}
+	}
+	// declare the kept nodes that would otherwise lose their first appearance
+	for i, n := range nodes {
+		if f.nodes[n] && !kept[n] && !f.declared[n] {
+			f.declared[n] = true
+			result = append(result, &ast.NodeStmt{NodeID: ast.NodeID{ID: ids[i]}})
+		}
+	}
+	for i, es := range expanded {
+		if f.keepEdge(edges[i]) {
+			f.declared[edges[i].Tail], f.declared[edges[i].Head] = true, true
+			result = append(result, es)
+		}
+	}
+	return result
+}
+
+// node returns the node of the node ID.
+func (f *filter) node(nid ast.NodeID) *Node {
+	n, _ := f.g.Node(nid.ID.Unquoted())
+	return n
+}
+
+// edge returns the i-th of the count edges declared by the edge statement. Edges of strict graphs
+// might have been merged into an earlier edge in which case it is looked up by its nodes.
+func (f *filter) edge(stmt *ast.EdgeStmt, i, count int, expanded *ast.EdgeStmt) *Edge {
+	if edges := f.edges[stmt]; len(edges) == count {
+		return edges[i]
+	}
+	tail := f.node(expanded.Left.(ast.NodeID))
+	head := f.node(expanded.Right.Right.(ast.NodeID))
+	key := [2]*Node{tail, head}
+	if !f.g.Directed && tail.ID > head.ID {
+		key = [2]*Node{head, tail}
+	}
+	return f.g.edges[key]
+}
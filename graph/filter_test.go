@@ -0,0 +1,101 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/graph"
+	"github.com/teleivo/dot/printer"
+)
+
+func TestFilter(t *testing.T) {
+	tests := map[string]struct {
+		in       string
+		keepNode func(*graph.Node) bool
+		keepEdge func(*graph.Edge) bool
+		want     string
+	}{
+		"KeepAll": {
+			in: `digraph {
+	a -> b -> c [color=red]
+}`,
+			want: `digraph {
+	a -> b -> c [color=red]
+}`,
+		},
+		"PartiallyKeptEdgeStmt": {
+			in: `digraph {
+	a -> b -> c [color=red]
+}`,
+			keepEdge: func(e *graph.Edge) bool { return e.Tail.ID == "b" },
+			want: `digraph {
+	a
+	b -> c [color=red]
+}`,
+		},
+		"ByAttributes": {
+			in: `digraph {
+	node [shape=box]
+	subgraph cluster_api {
+		label=API
+		gateway -> {auth users} [color=red]
+		logs [shape=note]
+	}
+	auth -> db
+	users -> db [style=dotted]
+	logs -> archive
+	subgraph cluster_ops { archive }
+}`,
+			keepNode: func(n *graph.Node) bool { return n.Attr("shape").Literal == "box" && n.ID != "auth" },
+			keepEdge: func(e *graph.Edge) bool { return e.Attr("style").Literal != "dotted" },
+			want: `digraph {
+	node [shape=box]
+	subgraph cluster_api {
+		label=API
+		gateway -> users [color=red]
+	}
+	db
+	archive
+	subgraph cluster_ops {
+		archive
+	}
+}`,
+		},
+		"EmptySubgraphsAreDropped": {
+			in: `graph {
+	subgraph cluster_a { label=a; x -- y }
+	z
+}`,
+			keepNode: func(n *graph.Node) bool { return n.ID == "z" },
+			want: `graph {
+	z
+}`,
+		},
+		"StrictMergedEdges": {
+			in: `strict graph {
+	a -- b
+	b -- a [color=red]
+	c -- a
+}`,
+			keepEdge: func(e *graph.Edge) bool { return e.Attr("color").Literal == "red" },
+			want: `strict graph {
+	a -- b
+	b -- a [color=red]
+	c
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := build(t, test.in).Filter(test.keepNode, test.keepEdge)
+
+			var got strings.Builder
+			err := printer.NewPrinter(strings.NewReader(g.String()), &got).Print()
+			require.NoErrorf(t, err, "Print(%q)", g.String())
+			assert.EqualValuesf(t, got.String(), test.want, "Filter(%q)", test.in)
+		})
+	}
+}
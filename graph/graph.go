@@ -0,0 +1,348 @@
+// Package graph provides a semantic model of dot graphs. The model resolves what an [ast.Graph]
+// means as interpreted by Graphviz. Nodes are unique by their ID, edges between subgraphs are
+// expanded into edges between their nodes and default attributes set by attribute statements like
+// node [shape=box] are applied to the nodes and edges in their scope. IDs and attribute values are
+// unquoted. Every element references the AST it is built from so its position is available.
+package graph
+
+import (
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/attr"
+	"github.com/teleivo/dot/token"
+)
+
+// Graph is a directed or undirected graph.
+type Graph struct {
+	ID        string      // ID is the unquoted identifier of the graph which is empty if the graph has none.
+	Strict    bool        // Strict indicates that the graph has no multi-edges.
+	Directed  bool        // Directed indicates that the graph is a directed graph.
+	Attrs     []Attribute // Attrs lists the attributes of the graph in the order they are set.
+	Nodes     []*Node     // Nodes lists the nodes in the order they first appear.
+	Edges     []*Edge     // Edges lists the edges in the order they appear.
+	Subgraphs []*Subgraph // Subgraphs lists the subgraphs of the root graph in the order they appear.
+	Defaults  []*Default  // Defaults lists the attribute statements of the graph and its subgraphs in the order they appear.
+	AST       ast.Graph   // AST is the graph the model is built from.
+
+	nodes map[string]*Node
+	ids   map[string]bool // ids holds the IDs reserved by UniqueID
+	edges map[[2]*Node]*Edge
+}
+
+// Node is a node of a graph.
+type Node struct {
+	ID     string      // ID is the unquoted identifier of the node.
+	Attrs  []Attribute // Attrs lists the attributes in effect for the node in the order they are set.
+	NodeID ast.NodeID  // NodeID is the node identifier that first declared the node.
+}
+
+// Edge is an edge of a graph connecting the tail node to the head node. The order of tail and head
+// has no meaning in undirected graphs.
+type Edge struct {
+	Tail     *Node         // Tail is the node the edge starts at.
+	TailPort *ast.Port     // TailPort is the optional port the edge attaches to at its tail.
+	Head     *Node         // Head is the node the edge ends at.
+	HeadPort *ast.Port     // HeadPort is the optional port the edge attaches to at its head.
+	Attrs    []Attribute   // Attrs lists the attributes in effect for the edge in the order they are set.
+	Stmt     *ast.EdgeStmt // Stmt is the edge statement that declared the edge.
+}
+
+// Subgraph is a subgraph or cluster of a graph.
+type Subgraph struct {
+	ID        string       // ID is the unquoted identifier of the subgraph which is empty if the subgraph has none.
+	Attrs     []Attribute  // Attrs lists the attributes of the subgraph in the order they are set.
+	Nodes     []*Node      // Nodes lists the nodes in the subgraph and its subgraphs in the order they first appear.
+	Subgraphs []*Subgraph  // Subgraphs lists the subgraphs of the subgraph in the order they appear.
+	Subgraph  ast.Subgraph // Subgraph is the subgraph the model is built from.
+
+	nodes map[*Node]bool
+}
+
+// Default is an attribute statement like node [shape=box] setting default attributes. Graphviz
+// applies defaults sequentially. They apply to the elements created after the statement up to the
+// end of the graph or subgraph it is in including nested subgraphs. Node defaults thus do not
+// apply to nodes that first appeared before the statement even if they are used after it.
+type Default struct {
+	Stmt      *ast.AttrStmt   // Stmt is the attribute statement.
+	Kind      token.TokenType // Kind is the keyword of the statement which is one of graph, node or edge.
+	End       token.Position  // End is the position of the closing brace of the graph or subgraph ending its scope.
+	Nodes     []*Node         // Nodes lists the nodes of a node default that first appeared in its scope.
+	Skipped   []*Node         // Skipped lists the nodes used in the scope of a node default that do not get its attributes as they first appeared before it.
+	Edges     []*Edge         // Edges lists the edges of an edge default declared in its scope.
+	Subgraphs []*Subgraph     // Subgraphs lists the subgraphs of a graph default that inherit its attributes as they are declared in its scope.
+
+	nodes map[*Node]bool
+	edges map[*Edge]bool
+}
+
+// Attribute is a name-value pair.
+type Attribute struct {
+	Name      string        // Name is the unquoted name of the attribute.
+	Value     string        // Value is the unquoted value of the attribute.
+	Attribute ast.Attribute // Attribute is the attribute the model is built from.
+}
+
+// Build builds the semantic model of given graph. Nodes get the attributes of the node attribute
+// statements in effect at the point they first appear. Edges get the attributes of the edge
+// attribute statements in effect at their edge statement. Edges of strict graphs that connect the
+// same nodes are merged into a single edge.
+func Build(g ast.Graph) *Graph {
+	result := &Graph{
+		Strict:   g.IsStrict(),
+		Directed: g.Directed,
+		AST:      g,
+		nodes:    make(map[string]*Node),
+		edges:    make(map[[2]*Node]*Edge),
+	}
+	if g.ID != nil {
+		result.ID = g.ID.Unquoted()
+	}
+
+	b := builder{g: result}
+	result.Attrs, result.Subgraphs = b.stmts(g.Stmts, scope{end: g.RightBrace})
+	return result
+}
+
+// Node returns the node with given unquoted ID.
+func (g *Graph) Node(id string) (*Node, bool) {
+	n, ok := g.nodes[id]
+	return n, ok
+}
+
+// UniqueID returns an ID that is not used by any node of the graph as described by
+// [ast.UniqueID]. The ID is reserved so the next call returns a different one. Use it in
+// transformations introducing synthetic nodes like a legend or the node a cluster is contracted
+// into.
+func (g *Graph) UniqueID(base string) string {
+	if g.ids == nil {
+		g.ids = make(map[string]bool)
+	}
+	id := ast.UniqueID(base, func(id string) bool {
+		_, ok := g.nodes[id]
+		return ok || g.ids[id]
+	})
+	g.ids[id] = true
+	return id
+}
+
+// Attr returns the value of the graph attribute with given name. The Graphviz default is returned
+// if the attribute is not set.
+func (g *Graph) Attr(name string) attr.Value {
+	return attr.Graph(g.AST, name)
+}
+
+// Attr returns the value of the node attribute with given name. The last one set wins.
+func (n *Node) Attr(name string) attr.Value {
+	return lookup(n.Attrs, name)
+}
+
+// Attr returns the value of the edge attribute with given name. The last one set wins.
+func (e *Edge) Attr(name string) attr.Value {
+	return lookup(e.Attrs, name)
+}
+
+// Attr returns the value of the subgraph attribute with given name. The last one set wins.
+func (s *Subgraph) Attr(name string) attr.Value {
+	return lookup(s.Attrs, name)
+}
+
+func lookup(attrs []Attribute, name string) attr.Value {
+	value := attr.Value{Name: name}
+	for _, a := range attrs {
+		if a.Name == name {
+			value.Literal, value.IsSet = a.Value, true
+		}
+	}
+	return value
+}
+
+// scope holds the default attributes and the subgraphs enclosing the statements being built.
+type scope struct {
+	nodeDefaults []Attribute
+	edgeDefaults []Attribute
+	subgraphs    []*Subgraph
+	defaults     []*Default     // defaults lists the attribute statements in effect
+	end          token.Position // end is the position of the closing brace of the innermost graph or subgraph
+}
+
+type builder struct {
+	g *Graph
+}
+
+// stmts builds the statements in given scope. It returns the attributes set by the statements and
+// the subgraphs declared by them.
+func (b *builder) stmts(stmts []ast.Stmt, sc scope) ([]Attribute, []*Subgraph) {
+	// clip the capacity so appending in a subgraph does not leak into its parent
+	sc.nodeDefaults = sc.nodeDefaults[:len(sc.nodeDefaults):len(sc.nodeDefaults)]
+	sc.edgeDefaults = sc.edgeDefaults[:len(sc.edgeDefaults):len(sc.edgeDefaults)]
+	sc.defaults = sc.defaults[:len(sc.defaults):len(sc.defaults)]
+
+	var attrs []Attribute
+	var subgraphs []*Subgraph
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			attrs = append(attrs, newAttribute(st))
+		case *ast.AttrStmt:
+			d := &Default{Stmt: st, Kind: token.Lookup(st.ID.Literal), End: sc.end, nodes: make(map[*Node]bool), edges: make(map[*Edge]bool)}
+			b.g.Defaults = append(b.g.Defaults, d)
+			sc.defaults = append(sc.defaults, d)
+			switch d.Kind {
+			case token.Graph:
+				attrs = append(attrs, attributes(&st.AttrList)...)
+			case token.Node:
+				sc.nodeDefaults = append(sc.nodeDefaults, attributes(&st.AttrList)...)
+			case token.Edge:
+				sc.edgeDefaults = append(sc.edgeDefaults, attributes(&st.AttrList)...)
+			}
+		case *ast.NodeStmt:
+			n := b.node(st.NodeID, sc)
+			n.Attrs = append(n.Attrs, attributes(st.AttrList)...)
+		case *ast.EdgeStmt:
+			subgraphs = append(subgraphs, b.edge(st, sc)...)
+		case ast.Subgraph:
+			subgraphs = append(subgraphs, b.subgraph(st, sc))
+		}
+	}
+	return attrs, subgraphs
+}
+
+func (b *builder) subgraph(subgraph ast.Subgraph, sc scope) *Subgraph {
+	result := &Subgraph{Subgraph: subgraph, nodes: make(map[*Node]bool)}
+	if subgraph.ID != nil {
+		result.ID = subgraph.ID.Unquoted()
+	}
+	for _, d := range sc.defaults {
+		if d.Kind == token.Graph {
+			d.Subgraphs = append(d.Subgraphs, result)
+		}
+	}
+	sc.subgraphs = append(sc.subgraphs[:len(sc.subgraphs):len(sc.subgraphs)], result)
+	sc.end = subgraph.RightBrace
+	result.Attrs, result.Subgraphs = b.stmts(subgraph.Stmts, sc)
+	return result
+}
+
+// node returns the node of given ID creating it with the node defaults of the scope if it does not
+// exist yet. The node is added to all subgraphs of the scope.
+func (b *builder) node(nid ast.NodeID, sc scope) *Node {
+	id := nid.ID.Unquoted()
+	n, ok := b.g.nodes[id]
+	if !ok {
+		n = &Node{
+			ID:     id,
+			Attrs:  append([]Attribute(nil), sc.nodeDefaults...),
+			NodeID: nid,
+		}
+		b.g.nodes[id] = n
+		b.g.Nodes = append(b.g.Nodes, n)
+	}
+	for _, d := range sc.defaults {
+		if d.Kind != token.Node || d.nodes[n] {
+			continue
+		}
+		d.nodes[n] = true
+		if ok {
+			d.Skipped = append(d.Skipped, n)
+		} else {
+			d.Nodes = append(d.Nodes, n)
+		}
+	}
+	for _, subgraph := range sc.subgraphs {
+		if !subgraph.nodes[n] {
+			subgraph.nodes[n] = true
+			subgraph.Nodes = append(subgraph.Nodes, n)
+		}
+	}
+	return n
+}
+
+// endpoint is a node an edge attaches to.
+type endpoint struct {
+	node *Node
+	port *ast.Port
+}
+
+// edge builds the edges of the edge statement connecting every node of an operand to every node
+// of the operand following it. It returns the subgraphs used as operands.
+func (b *builder) edge(es *ast.EdgeStmt, sc scope) []*Subgraph {
+	var subgraphs []*Subgraph
+	operand := func(operand ast.EdgeOperand) []endpoint {
+		switch op := operand.(type) {
+		case ast.NodeID:
+			return []endpoint{{node: b.node(op, sc), port: op.Port}}
+		case ast.Subgraph:
+			subgraph := b.subgraph(op, sc)
+			subgraphs = append(subgraphs, subgraph)
+			result := make([]endpoint, 0, len(subgraph.Nodes))
+			for _, n := range subgraph.Nodes {
+				result = append(result, endpoint{node: n})
+			}
+			return result
+		}
+		return nil
+	}
+
+	attrs := append(sc.edgeDefaults[:len(sc.edgeDefaults):len(sc.edgeDefaults)], attributes(es.AttrList)...)
+	attrs = attrs[:len(attrs):len(attrs)] // the edges share their attributes
+	tails := operand(es.Left)
+	for cur := &es.Right; cur != nil; cur = cur.Next {
+		heads := operand(cur.Right)
+		for _, tail := range tails {
+			for _, head := range heads {
+				e := b.addEdge(&Edge{
+					Tail:     tail.node,
+					TailPort: tail.port,
+					Head:     head.node,
+					HeadPort: head.port,
+					Attrs:    attrs,
+					Stmt:     es,
+				})
+				for _, d := range sc.defaults {
+					if d.Kind == token.Edge && !d.edges[e] {
+						d.edges[e] = true
+						d.Edges = append(d.Edges, e)
+					}
+				}
+			}
+		}
+		tails = heads
+	}
+	return subgraphs
+}
+
+// addEdge adds the edge to the graph. Edges of strict graphs connecting the same nodes are merged
+// with the attributes of the later edge taking precedence. The edge of the graph is returned which
+// is the existing one if the edge is merged into it.
+func (b *builder) addEdge(e *Edge) *Edge {
+	if !b.g.Strict {
+		b.g.Edges = append(b.g.Edges, e)
+		return e
+	}
+
+	key := [2]*Node{e.Tail, e.Head}
+	if !b.g.Directed && e.Tail.ID > e.Head.ID {
+		key = [2]*Node{e.Head, e.Tail}
+	}
+	if existing, ok := b.g.edges[key]; ok {
+		existing.Attrs = append(existing.Attrs[:len(existing.Attrs):len(existing.Attrs)], e.Attrs...)
+		return existing
+	}
+	b.g.edges[key] = e
+	b.g.Edges = append(b.g.Edges, e)
+	return e
+}
+
+func newAttribute(a ast.Attribute) Attribute {
+	return Attribute{Name: a.Name.Unquoted(), Value: a.Value.Unquoted(), Attribute: a}
+}
+
+// attributes returns the attributes of all the attribute lists chained together.
+func attributes(attrList *ast.AttrList) []Attribute {
+	var result []Attribute
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			result = append(result, newAttribute(aList.Attribute))
+		}
+	}
+	return result
+}
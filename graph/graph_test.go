@@ -0,0 +1,274 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+	"github.com/teleivo/dot/token"
+)
+
+func TestBuild(t *testing.T) {
+	tests := map[string]struct {
+		in            string
+		wantNodes     []string
+		wantEdges     []string
+		wantSubgraphs []string
+	}{
+		"NodesAreUnique": {
+			in: `graph {
+	A [color=red]
+	"A" [shape=box]
+	B -- A
+}`,
+			wantNodes: []string{"A [color=red shape=box]", "B []"},
+			wantEdges: []string{"B -- A []"},
+		},
+		"NodeDefaultsApplyWhenNodesFirstAppear": {
+			in: `digraph {
+	A
+	node [shape=box]
+	B -> A
+	subgraph {
+		node [color=red]
+		C
+		B
+	}
+	D
+}`,
+			wantNodes:     []string{"A []", "B [shape=box]", "C [shape=box color=red]", "D [shape=box]"},
+			wantEdges:     []string{"B -> A []"},
+			wantSubgraphs: []string{"{C B}"},
+		},
+		"EdgeDefaultsApplyToEdgesInScope": {
+			in: `digraph {
+	edge [color=red]
+	A -> B [label=x]
+	subgraph s {
+		edge [style=dashed]
+		C -> D
+	}
+	E -> F
+}`,
+			wantNodes: []string{"A []", "B []", "C []", "D []", "E []", "F []"},
+			wantEdges: []string{
+				"A -> B [color=red label=x]",
+				"C -> D [color=red style=dashed]",
+				"E -> F [color=red]",
+			},
+			wantSubgraphs: []string{"s [] {C D}"},
+		},
+		"EdgesBetweenSubgraphsAreExpanded": {
+			in: `digraph {
+	A -> {B C} -> subgraph cluster_d {label="D" D}
+}`,
+			wantNodes: []string{"A []", "B []", "C []", "D []"},
+			wantEdges: []string{
+				"A -> B []",
+				"A -> C []",
+				"B -> D []",
+				"C -> D []",
+			},
+			wantSubgraphs: []string{"{B C}", "cluster_d [label=D] {D}"},
+		},
+		"NestedSubgraphsContainTheNodesOfTheirSubgraphs": {
+			in: `graph {
+	subgraph cluster_a {
+		A
+		subgraph cluster_b { B }
+	}
+}`,
+			wantNodes:     []string{"A []", "B []"},
+			wantSubgraphs: []string{"cluster_a [] {A B} [cluster_b [] {B}]"},
+		},
+		"StrictGraphsMergeEdges": {
+			in: `strict graph {
+	A -- B [color=red]
+	B -- A [style=dashed]
+	A -- A
+}`,
+			wantNodes: []string{"A []", "B []"},
+			wantEdges: []string{"A -- B [color=red style=dashed]", "A -- A []"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := graph.Build(g)
+
+			var nodes, edges, subgraphs []string
+			for _, n := range got.Nodes {
+				nodes = append(nodes, n.ID+" "+attrs(n.Attrs))
+			}
+			op := " -- "
+			if got.Directed {
+				op = " -> "
+			}
+			for _, e := range got.Edges {
+				edges = append(edges, e.Tail.ID+op+e.Head.ID+" "+attrs(e.Attrs))
+			}
+			for _, s := range got.Subgraphs {
+				subgraphs = append(subgraphs, subgraph(s))
+			}
+			assert.EqualValuesf(t, nodes, test.wantNodes, "Build(%q).Nodes", test.in)
+			assert.EqualValuesf(t, edges, test.wantEdges, "Build(%q).Edges", test.in)
+			assert.EqualValuesf(t, subgraphs, test.wantSubgraphs, "Build(%q).Subgraphs", test.in)
+		})
+	}
+}
+
+func TestGraph(t *testing.T) {
+	in := `strict digraph "deps" {
+	rankdir=LR
+	"core" [label="Core"]
+	web:s -> core:n [weight=2]
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	got := graph.Build(g)
+
+	assert.EqualValuesf(t, got.ID, "deps", "Build(%q).ID", in)
+	assert.Truef(t, got.Strict, "Build(%q).Strict", in)
+	assert.EqualValuesf(t, got.Attr("rankdir").String(), "LR", "Build(%q).Attr(%q)", in, "rankdir")
+	assert.EqualValuesf(t, got.Attr("ranksep").String(), "0.5", "Build(%q).Attr(%q)", in, "ranksep")
+
+	core, ok := got.Node("core")
+	require.Truef(t, ok, "Build(%q).Node(%q)", in, "core")
+	assert.EqualValuesf(t, core.Attr("label").String(), "Core", "Node(%q).Attr(%q)", "core", "label")
+	assert.EqualValuesf(t, core.NodeID.Start(), token.Position{Row: 3, Column: 2}, "Node(%q).NodeID.Start()", "core")
+	assert.Falsef(t, core.Attr("shape").IsSet, "Node(%q).Attr(%q).IsSet", "core", "shape")
+
+	require.EqualValuesf(t, len(got.Edges), 1, "Build(%q).Edges", in)
+	edge := got.Edges[0]
+	weight, err := edge.Attr("weight").Float()
+	require.NoErrorf(t, err, "Edge.Attr(%q).Float()", "weight")
+	assert.EqualValuesf(t, weight, 2.0, "Edge.Attr(%q).Float()", "weight")
+	assert.EqualValuesf(t, edge.TailPort.String(), ":s", "Edge.TailPort")
+	assert.EqualValuesf(t, edge.HeadPort.String(), ":n", "Edge.HeadPort")
+	assert.EqualValuesf(t, edge.Stmt.Start(), token.Position{Row: 4, Column: 2}, "Edge.Stmt.Start()")
+}
+
+func TestGraphUniqueID(t *testing.T) {
+	in := `graph { legend "legend_1" -- "legend 2" }`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	got := graph.Build(g)
+
+	assert.EqualValuesf(t, got.UniqueID("legend"), "legend_2", "UniqueID(%q)", "legend")
+	assert.EqualValuesf(t, got.UniqueID("legend"), "legend_3", "UniqueID(%q) once reserved", "legend")
+	assert.EqualValuesf(t, got.UniqueID("legend 2"), "legend 2_1", "UniqueID(%q)", "legend 2")
+	assert.EqualValuesf(t, got.UniqueID("core"), "core", "UniqueID(%q)", "core")
+}
+
+func TestGraphDefaults(t *testing.T) {
+	in := `digraph {
+	A
+	node [shape=box]
+	edge [color=red]
+	A -> B
+	subgraph s {
+		graph [rank=same]
+		node [color=blue]
+		subgraph t { C }
+		B -> D
+	}
+	E -> D
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	got := graph.Build(g)
+
+	type scope struct {
+		Kind      token.TokenType
+		Start     token.Position
+		End       token.Position
+		Nodes     []string
+		Skipped   []string
+		Edges     []string
+		Subgraphs []string
+	}
+	var scopes []scope
+	for _, d := range got.Defaults {
+		sc := scope{Kind: d.Kind, Start: d.Stmt.Start(), End: d.End}
+		for _, n := range d.Nodes {
+			sc.Nodes = append(sc.Nodes, n.ID)
+		}
+		for _, n := range d.Skipped {
+			sc.Skipped = append(sc.Skipped, n.ID)
+		}
+		for _, e := range d.Edges {
+			sc.Edges = append(sc.Edges, e.Tail.ID+" -> "+e.Head.ID)
+		}
+		for _, s := range d.Subgraphs {
+			sc.Subgraphs = append(sc.Subgraphs, s.ID)
+		}
+		scopes = append(scopes, sc)
+	}
+
+	want := []scope{
+		{
+			Kind:    token.Node,
+			Start:   token.Position{Row: 3, Column: 2},
+			End:     token.Position{Row: 13, Column: 1},
+			Nodes:   []string{"B", "C", "D", "E"},
+			Skipped: []string{"A"},
+		},
+		{
+			Kind:  token.Edge,
+			Start: token.Position{Row: 4, Column: 2},
+			End:   token.Position{Row: 13, Column: 1},
+			Edges: []string{"A -> B", "B -> D", "E -> D"},
+		},
+		{
+			Kind:      token.Graph,
+			Start:     token.Position{Row: 7, Column: 3},
+			End:       token.Position{Row: 11, Column: 2},
+			Subgraphs: []string{"t"},
+		},
+		{
+			Kind:    token.Node,
+			Start:   token.Position{Row: 8, Column: 3},
+			End:     token.Position{Row: 11, Column: 2},
+			Nodes:   []string{"C", "D"},
+			Skipped: []string{"B"},
+		},
+	}
+	assert.EqualValuesf(t, scopes, want, "Build(%q).Defaults", in)
+}
+
+func attrs(attrs []graph.Attribute) string {
+	var result []string
+	for _, a := range attrs {
+		result = append(result, a.Name+"="+a.Value)
+	}
+	return "[" + strings.Join(result, " ") + "]"
+}
+
+func subgraph(s *graph.Subgraph) string {
+	var out strings.Builder
+	if s.ID != "" {
+		out.WriteString(s.ID + " " + attrs(s.Attrs) + " ")
+	}
+	var nodes []string
+	for _, n := range s.Nodes {
+		nodes = append(nodes, n.ID)
+	}
+	out.WriteString("{" + strings.Join(nodes, " ") + "}")
+	if len(s.Subgraphs) > 0 {
+		var subgraphs []string
+		for _, sub := range s.Subgraphs {
+			subgraphs = append(subgraphs, subgraph(sub))
+		}
+		out.WriteString(" [" + strings.Join(subgraphs, " ") + "]")
+	}
+	return out.String()
+}
@@ -0,0 +1,327 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// Resolution defines how [Merge] resolves an attribute that is set to different values in the
+// graphs it merges.
+type Resolution int
+
+const (
+	Ours   Resolution = iota // Ours keeps the value of the first graph.
+	Theirs                   // Theirs keeps the value of the second graph.
+	Fail                     // Fail fails the merge with an error listing all conflicts.
+)
+
+// Merge merges the graph theirs into the graph ours. The merged graph has the union of the nodes,
+// edges and attributes of both graphs. It is useful to combine graphs generated for parts of a
+// system into a single diagram.
+//
+//   - Nodes are merged by their ID.
+//   - Edges are merged by their endpoints including ports. Multi-edges are merged in order so the
+//     merged graph has as many edges between two nodes as the graph with more of them.
+//   - Subgraphs with the same ID at the same level of nesting are merged so a cluster of both graphs
+//     ends up as a single cluster. Anonymous subgraphs are kept as is if they have attributes like
+//     rank=same and are dropped otherwise as they only group nodes and edges that are part of the
+//     merged graph anyway.
+//   - Attributes are merged by the value in effect which includes the defaults of node and edge
+//     attribute statements. An attribute set to different values in both graphs is a conflict that
+//     is resolved according to the resolution.
+//
+// The merged graph sets all attributes explicitly. Graph attributes come first followed by the
+// nodes that are not in a subgraph, the subgraphs with their nodes and lastly the edges. A node is
+// declared with its attributes in the first subgraph it is in. The merged graph is strict if ours
+// is. An error is returned if one graph is directed and the other is not.
+func Merge(ours, theirs *Graph, resolution Resolution) (ast.Graph, error) {
+	if ours.Directed != theirs.Directed {
+		return ast.Graph{}, errors.New("cannot merge a directed with an undirected graph")
+	}
+
+	m := merger{resolution: resolution, declared: make(map[string]bool)}
+	result := ast.Graph{Directed: ours.Directed}
+	if ours.Strict {
+		result.StrictStart = &token.Position{}
+	}
+	if id := ours.AST.ID; id != nil {
+		result.ID = &ast.ID{Literal: id.Literal}
+	} else if id := theirs.AST.ID; id != nil {
+		result.ID = &ast.ID{Literal: id.Literal}
+	}
+	for _, a := range m.attrs("graph", ours.Attrs, theirs.Attrs) {
+		result.Stmts = append(result.Stmts, a)
+	}
+
+	// merge the nodes first so they can be declared with their merged attributes in subgraphs
+	m.nodes = make(map[string][]ast.Attribute)
+	var nodes []*Node
+	for _, n := range ours.Nodes {
+		nodes = append(nodes, n)
+		var attrs []Attribute
+		if other, ok := theirs.Node(n.ID); ok {
+			attrs = other.Attrs
+		}
+		m.nodes[n.ID] = m.attrs("node "+n.ID, n.Attrs, attrs)
+	}
+	for _, n := range theirs.Nodes {
+		if _, ok := ours.Node(n.ID); !ok {
+			nodes = append(nodes, n)
+			m.nodes[n.ID] = m.attrs("node "+n.ID, nil, n.Attrs)
+		}
+	}
+
+	subgraphs := m.subgraphs(ours.Subgraphs, theirs.Subgraphs)
+	for _, n := range directNodes(nodes, subgraphs) {
+		result.Stmts = append(result.Stmts, m.node(n))
+	}
+	for _, s := range subgraphs {
+		result.Stmts = append(result.Stmts, m.subgraph(s))
+	}
+
+	for _, e := range m.edges(ours, theirs) {
+		result.Stmts = append(result.Stmts, e)
+	}
+
+	if len(m.conflicts) > 0 {
+		return ast.Graph{}, errors.Join(m.conflicts...)
+	}
+	return result, nil
+}
+
+type merger struct {
+	resolution Resolution
+	conflicts  []error
+	nodes      map[string][]ast.Attribute // nodes holds the merged attributes by node ID
+	declared   map[string]bool            // declared holds the IDs of the nodes declared with their attributes
+}
+
+// mergedSubgraph is a subgraph of both graphs or one of them.
+type mergedSubgraph struct {
+	id        *ast.ID
+	attrs     []ast.Attribute
+	nodes     []*Node // nodes lists the nodes directly in the subgraph
+	subgraphs []*mergedSubgraph
+}
+
+// subgraphs merges the subgraphs of both graphs that are nested in the same parent.
+func (m *merger) subgraphs(ours, theirs []*Subgraph) []*mergedSubgraph {
+	byID := make(map[string]*Subgraph)
+	for _, s := range theirs {
+		if s.ID != "" {
+			byID[s.ID] = s
+		}
+	}
+	merged := make(map[string]bool)
+
+	var result []*mergedSubgraph
+	add := func(s, other *Subgraph) {
+		if s.ID == "" && len(s.Attrs) == 0 {
+			return
+		}
+		ms := &mergedSubgraph{}
+		if s.ID != "" {
+			ms.id = &ast.ID{Literal: s.Subgraph.ID.Literal}
+		}
+		var otherAttrs []Attribute
+		var otherSubgraphs []*Subgraph
+		nodes := s.Nodes
+		if other != nil {
+			otherAttrs, otherSubgraphs = other.Attrs, other.Subgraphs
+			nodes = append(nodes[:len(nodes):len(nodes)], other.Nodes...)
+		}
+		ms.attrs = m.attrs("subgraph "+s.ID, s.Attrs, otherAttrs)
+		ms.subgraphs = m.subgraphs(s.Subgraphs, otherSubgraphs)
+		ms.nodes = directNodes(nodes, ms.subgraphs)
+		result = append(result, ms)
+	}
+	for _, s := range ours {
+		var other *Subgraph
+		if s.ID != "" && !merged[s.ID] {
+			other = byID[s.ID]
+			merged[s.ID] = true
+		}
+		add(s, other)
+	}
+	for _, s := range theirs {
+		if s.ID == "" || !merged[s.ID] {
+			add(s, nil)
+		}
+	}
+	return result
+}
+
+// directNodes returns the nodes that are not in one of the subgraphs. Nodes are unique by their ID
+// as they can be of both graphs.
+func directNodes(nodes []*Node, subgraphs []*mergedSubgraph) []*Node {
+	nested := make(map[string]bool)
+	var visit func(subgraphs []*mergedSubgraph)
+	visit = func(subgraphs []*mergedSubgraph) {
+		for _, s := range subgraphs {
+			for _, n := range s.nodes {
+				nested[n.ID] = true
+			}
+			visit(s.subgraphs)
+		}
+	}
+	visit(subgraphs)
+
+	var result []*Node
+	for _, n := range nodes {
+		if !nested[n.ID] {
+			nested[n.ID] = true
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func (m *merger) subgraph(s *mergedSubgraph) ast.Subgraph {
+	result := ast.Subgraph{ID: s.id}
+	if s.id != nil {
+		result.SubgraphStart = &token.Position{}
+	}
+	for _, a := range s.attrs {
+		result.Stmts = append(result.Stmts, a)
+	}
+	for _, n := range s.nodes {
+		result.Stmts = append(result.Stmts, m.node(n))
+	}
+	for _, nested := range s.subgraphs {
+		result.Stmts = append(result.Stmts, m.subgraph(nested))
+	}
+	return result
+}
+
+// node returns the node statement declaring the node. Only the first declaration sets the
+// attributes of the node.
+func (m *merger) node(n *Node) *ast.NodeStmt {
+	result := &ast.NodeStmt{NodeID: ast.NodeID{ID: ast.ID{Literal: n.NodeID.ID.Literal}}}
+	if !m.declared[n.ID] {
+		m.declared[n.ID] = true
+		result.AttrList = newAttrList(m.nodes[n.ID])
+	}
+	return result
+}
+
+// edges merges the edges of both graphs. Edges are matched by their endpoints and ports.
+func (m *merger) edges(ours, theirs *Graph) []*ast.EdgeStmt {
+	same := func(id string) string { return id }
+	theirEdges := make(map[string][]*Edge)
+	for _, e := range theirs.Edges {
+		key := edgeKey(e, theirs.Directed, same)
+		theirEdges[key] = append(theirEdges[key], e)
+	}
+
+	var result []*ast.EdgeStmt
+	seen := make(map[string]int)
+	for _, e := range ours.Edges {
+		key := edgeKey(e, ours.Directed, same)
+		i := seen[key]
+		seen[key]++
+		var attrs []Attribute
+		if i < len(theirEdges[key]) {
+			attrs = theirEdges[key][i].Attrs
+		}
+		result = append(result, newEdgeStmt(e, ours.Directed, m.attrs("edge "+key, e.Attrs, attrs)))
+	}
+	for _, e := range theirs.Edges {
+		key := edgeKey(e, theirs.Directed, same)
+		seen[key]--
+		if seen[key] < 0 {
+			result = append(result, newEdgeStmt(e, theirs.Directed, m.attrs("edge "+key, nil, e.Attrs)))
+		}
+	}
+	return result
+}
+
+func newEdgeStmt(e *Edge, directed bool, attrs []ast.Attribute) *ast.EdgeStmt {
+	return &ast.EdgeStmt{
+		Left: newNodeID(e.Tail, e.TailPort),
+		Right: ast.EdgeRHS{
+			Directed: directed,
+			Right:    newNodeID(e.Head, e.HeadPort),
+		},
+		AttrList: newAttrList(attrs),
+	}
+}
+
+func newNodeID(n *Node, port *ast.Port) ast.NodeID {
+	result := ast.NodeID{ID: ast.ID{Literal: n.NodeID.ID.Literal}}
+	if port != nil {
+		result.Port = &ast.Port{}
+		if port.Name != nil {
+			result.Port.Name = &ast.ID{Literal: port.Name.Literal}
+		}
+		if port.CompassPoint != nil {
+			result.Port.CompassPoint = &ast.CompassPoint{Type: port.CompassPoint.Type}
+		}
+	}
+	return result
+}
+
+// attrs merges the attributes in effect. Attributes are ordered as they are first set in ours
+// followed by the ones only set in theirs. The element describes whose attributes are merged in
+// errors.
+func (m *merger) attrs(element string, ours, theirs []Attribute) []ast.Attribute {
+	var names []string
+	values := make(map[string]Attribute)
+	for _, a := range ours {
+		if _, ok := values[a.Name]; !ok {
+			names = append(names, a.Name)
+		}
+		values[a.Name] = a
+	}
+	theirValues := make(map[string]Attribute)
+	for _, a := range theirs {
+		if _, ok := values[a.Name]; !ok {
+			if _, ok := theirValues[a.Name]; !ok {
+				names = append(names, a.Name)
+			}
+		}
+		theirValues[a.Name] = a
+	}
+
+	result := make([]ast.Attribute, 0, len(names))
+	for _, name := range names {
+		a, inOurs := values[name]
+		if their, ok := theirValues[name]; ok && (!inOurs || m.resolve(element, a, their)) {
+			a = their
+		}
+		result = append(result, ast.Attribute{
+			Name:  ast.ID{Literal: a.Attribute.Name.Literal},
+			Value: ast.ID{Literal: a.Attribute.Value.Literal},
+		})
+	}
+	return result
+}
+
+// resolve reports whether their attribute wins over ours. Conflicts are recorded if the merge
+// fails on them.
+func (m *merger) resolve(element string, ours, theirs Attribute) bool {
+	if ours.Value == theirs.Value {
+		return false
+	}
+	switch m.resolution {
+	case Theirs:
+		return true
+	case Fail:
+		m.conflicts = append(m.conflicts, fmt.Errorf("conflicting values of attribute %s of %s: %q and %q", ours.Name, element, ours.Value, theirs.Value))
+	}
+	return false
+}
+
+func newAttrList(attrs []ast.Attribute) *ast.AttrList {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	result := &ast.AttrList{}
+	for i := len(attrs) - 1; i >= 0; i-- {
+		result.AList = &ast.AList{Attribute: attrs[i], Next: result.AList}
+	}
+	return result
+}
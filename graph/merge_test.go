@@ -0,0 +1,126 @@
+package graph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+	"github.com/teleivo/dot/printer"
+)
+
+func TestMerge(t *testing.T) {
+	ours := `digraph arch {
+	rankdir=LR
+	node [shape=box]
+	subgraph cluster_api {
+		label=API
+		gateway
+	}
+	gateway -> auth [color=red]
+	gateway:out -> db
+	{rank=same; auth db}
+}`
+	theirs := `digraph {
+	subgraph cluster_api {
+		label="API layer"
+		users [shape=box]
+	}
+	gateway -> auth [color=blue]
+	gateway -> auth
+	users -> db
+	x -> {y z}
+}`
+
+	t.Run("Resolution", func(t *testing.T) {
+		tests := map[string]struct {
+			resolution graph.Resolution
+			want       string
+		}{
+			"Ours": {
+				resolution: graph.Ours,
+				want: `digraph arch {
+	rankdir=LR
+	x
+	y
+	z
+	subgraph cluster_api {
+		label=API
+		gateway [shape=box]
+		users [shape=box]
+	}
+	subgraph {
+		rank=same
+		auth [shape=box]
+		db [shape=box]
+	}
+	gateway -> auth [color=red]
+	gateway:out -> db
+	gateway -> auth
+	users -> db
+	x -> y
+	x -> z
+}`,
+			},
+			"Theirs": {
+				resolution: graph.Theirs,
+				want: `digraph arch {
+	rankdir=LR
+	x
+	y
+	z
+	subgraph cluster_api {
+		label="API layer"
+		gateway [shape=box]
+		users [shape=box]
+	}
+	subgraph {
+		rank=same
+		auth [shape=box]
+		db [shape=box]
+	}
+	gateway -> auth [color=blue]
+	gateway:out -> db
+	gateway -> auth
+	users -> db
+	x -> y
+	x -> z
+}`,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				g, err := graph.Merge(build(t, ours), build(t, theirs), test.resolution)
+				require.NoErrorf(t, err, "Merge(%q, %q)", ours, theirs)
+
+				var got strings.Builder
+				err = printer.NewPrinter(strings.NewReader(g.String()), &got).Print()
+				require.NoErrorf(t, err, "Print(%q)", g.String())
+				assert.EqualValuesf(t, got.String(), test.want, "Merge(%q, %q)", ours, theirs)
+			})
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		_, err := graph.Merge(build(t, ours), build(t, theirs), graph.Fail)
+
+		require.NotNilf(t, err, "Merge(%q, %q)", ours, theirs)
+		assert.EqualValuesf(t, err.Error(), `conflicting values of attribute label of subgraph cluster_api: "API" and "API layer"
+conflicting values of attribute color of edge gateway -> auth: "red" and "blue"`, "Merge(%q, %q)", ours, theirs)
+	})
+
+	t.Run("DifferentKinds", func(t *testing.T) {
+		_, err := graph.Merge(build(t, "graph {}"), build(t, "digraph {}"), graph.Ours)
+
+		require.NotNilf(t, err, "Merge(%q, %q)", "graph {}", "digraph {}")
+	})
+}
+
+func build(t *testing.T, src string) *graph.Graph {
+	g, err := dot.Parse([]byte(src))
+	require.NoErrorf(t, err, "Parse(%q)", src)
+	return graph.Build(g)
+}
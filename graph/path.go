@@ -0,0 +1,442 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// Path is a path through a graph.
+type Path struct {
+	Nodes  []*Node // Nodes lists the nodes of the path from its start to its end.
+	Edges  []*Edge // Edges lists the edges of the path. Edge i connects node i to node i+1. An undirected edge might connect them from its head to its tail.
+	Length float64 // Length is the sum of the weights of the edges.
+}
+
+// LongestPath returns the longest path of a directed acyclic graph which is also known as its
+// critical path. It is useful to find the chain of steps that determines how long a build or
+// pipeline takes.
+//
+// Edges are weighted by the numeric value of the attribute with given name. Edges that do not set
+// the attribute and all edges if the name is empty have a weight of 1 so the path with the most
+// edges is returned. Of several longest paths the one found first in topological order is returned
+// so the same graph always yields the same path. An error is returned if the graph is undirected,
+// has a cycle or if an edge has a weight that is not a number.
+func (g *Graph) LongestPath(weight string) (Path, error) {
+	if !g.Directed {
+		return Path{}, errors.New("longest path requires a directed graph")
+	}
+	if len(g.Nodes) == 0 {
+		return Path{}, nil
+	}
+
+	weights, err := edgeWeights(g, weight)
+	if err != nil {
+		return Path{}, err
+	}
+	out := make(map[*Node][]*Edge)
+	inDegree := make(map[*Node]int)
+	for _, e := range g.Edges {
+		out[e.Tail] = append(out[e.Tail], e)
+		inDegree[e.Head]++
+	}
+
+	// visit the nodes in topological order using Kahn's algorithm
+	var order []*Node
+	for _, n := range g.Nodes {
+		if inDegree[n] == 0 {
+			order = append(order, n)
+		}
+	}
+	dist := make(map[*Node]float64, len(g.Nodes))
+	pred := make(map[*Node]*Edge, len(g.Nodes))
+	for i := 0; i < len(order); i++ {
+		n := order[i]
+		for _, e := range out[n] {
+			if d := dist[n] + weights[e]; d > dist[e.Head] {
+				dist[e.Head], pred[e.Head] = d, e
+			}
+			inDegree[e.Head]--
+			if inDegree[e.Head] == 0 {
+				order = append(order, e.Head)
+			}
+		}
+	}
+	if len(order) < len(g.Nodes) {
+		n := cycle(g, inDegree)
+		return Path{}, fmt.Errorf("%s: graph has a cycle through node %s", n.NodeID.Start(), n.ID)
+	}
+
+	end := order[0]
+	for _, n := range order[1:] {
+		if dist[n] > dist[end] {
+			end = n
+		}
+	}
+
+	result := Path{Nodes: []*Node{end}, Length: dist[end]}
+	for e := pred[end]; e != nil; e = pred[e.Tail] {
+		result.Nodes = append(result.Nodes, e.Tail)
+		result.Edges = append(result.Edges, e)
+	}
+	slices.Reverse(result.Nodes)
+	slices.Reverse(result.Edges)
+	return result, nil
+}
+
+// ShortestPath returns the shortest path from one node to another. It is useful to find out how a
+// change to one component reaches another. Edges of undirected graphs are followed in both
+// directions.
+//
+// Edges are weighted by the numeric value of the attribute with given name like in
+// [Graph.LongestPath] so the path with the fewest edges is returned if the name is empty. Of several
+// shortest paths the same graph always yields the same one. The path is empty if there is no path
+// from one node to the other. It consists of the node only if both nodes are the same. An error is
+// returned if an edge has a weight that is not a number or that is negative.
+func (g *Graph) ShortestPath(from, to *Node, weight string) (Path, error) {
+	weights, err := edgeWeights(g, weight)
+	if err != nil {
+		return Path{}, err
+	}
+	for _, e := range g.Edges {
+		if weights[e] < 0 {
+			return Path{}, fmt.Errorf("%s: edge %s -> %s has %s %q that is negative", e.Stmt.Start(), e.Tail.ID, e.Head.ID, weight, e.Attr(weight).Literal)
+		}
+	}
+
+	index := make(map[*Node]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n] = i
+	}
+	out := make(map[*Node][]*Edge)
+	for _, e := range g.Edges {
+		out[e.Tail] = append(out[e.Tail], e)
+		if !g.Directed && e.Tail != e.Head {
+			out[e.Head] = append(out[e.Head], e)
+		}
+	}
+
+	// visit the nodes in order of their distance using Dijkstra's algorithm. Nodes at the same
+	// distance are visited in the order they appear so the path does not depend on the heap.
+	dist := map[*Node]float64{from: 0}
+	pred := make(map[*Node]*Edge)
+	visited := make(map[*Node]bool)
+	queue := &nodeQueue{dist: dist, index: index, nodes: []*Node{from}}
+	for queue.Len() > 0 {
+		n := heap.Pop(queue).(*Node)
+		if visited[n] {
+			continue
+		}
+		visited[n] = true
+		if n == to {
+			break
+		}
+		for _, e := range out[n] {
+			next := e.Head
+			if next == n {
+				next = e.Tail
+			}
+			if d, ok := dist[next]; visited[next] || ok && d <= dist[n]+weights[e] {
+				continue
+			}
+			dist[next], pred[next] = dist[n]+weights[e], e
+			heap.Push(queue, next)
+		}
+	}
+	if !visited[to] {
+		return Path{}, nil
+	}
+
+	result := Path{Nodes: []*Node{to}, Length: dist[to]}
+	for n := to; n != from; n = result.Nodes[len(result.Nodes)-1] {
+		e := pred[n]
+		prev := e.Tail
+		if prev == n {
+			prev = e.Head
+		}
+		result.Nodes = append(result.Nodes, prev)
+		result.Edges = append(result.Edges, e)
+	}
+	slices.Reverse(result.Nodes)
+	slices.Reverse(result.Edges)
+	return result, nil
+}
+
+// nodeQueue is a priority queue of nodes ordered by their distance and the order they appear.
+// A node is pushed again if its distance shrinks. Nodes that were already popped must be skipped.
+type nodeQueue struct {
+	dist  map[*Node]float64
+	index map[*Node]int
+	nodes []*Node
+}
+
+func (q *nodeQueue) Len() int { return len(q.nodes) }
+
+func (q *nodeQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	if q.dist[a] != q.dist[b] {
+		return q.dist[a] < q.dist[b]
+	}
+	return q.index[a] < q.index[b]
+}
+
+func (q *nodeQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *nodeQueue) Push(x any) { q.nodes = append(q.nodes, x.(*Node)) }
+
+func (q *nodeQueue) Pop() any {
+	n := q.nodes[len(q.nodes)-1]
+	q.nodes = q.nodes[:len(q.nodes)-1]
+	return n
+}
+
+// edgeWeights returns the weight of every edge which is the numeric value of the attribute with
+// given name. Edges that do not set the attribute and all edges if the name is empty have a weight
+// of 1.
+func edgeWeights(g *Graph, weight string) (map[*Edge]float64, error) {
+	result := make(map[*Edge]float64, len(g.Edges))
+	for _, e := range g.Edges {
+		w := 1.0
+		if v := e.Attr(weight); weight != "" && v.IsSet {
+			var err error
+			w, err = strconv.ParseFloat(v.Literal, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: edge %s -> %s has %s %q that is not a number", e.Stmt.Start(), e.Tail.ID, e.Head.ID, weight, v.Literal)
+			}
+		}
+		result[e] = w
+	}
+	return result, nil
+}
+
+// cycle returns a node on a cycle given the in-degrees left after a topological sort. Every node
+// left with a positive in-degree has a predecessor that is left as well. Following predecessors
+// from any such node thus ends up going around a cycle.
+func cycle(g *Graph, inDegree map[*Node]int) *Node {
+	pred := make(map[*Node]*Node)
+	var start *Node
+	for _, e := range g.Edges {
+		if inDegree[e.Tail] > 0 && inDegree[e.Head] > 0 && pred[e.Head] == nil {
+			pred[e.Head] = e.Tail
+		}
+		if start == nil && inDegree[e.Head] > 0 {
+			start = e.Head
+		}
+	}
+
+	visited := make(map[*Node]bool)
+	n := start
+	for !visited[n] {
+		visited[n] = true
+		n = pred[n]
+	}
+	return n
+}
+
+// Highlight highlights the path p through the graph g by adding the attributes to the nodes and
+// edges of the path. The path must have been found in the model built from g. The graph is modified
+// in place. The path is highlighted using color=red and penwidth=2 if no attributes are given.
+//
+// Nodes are highlighted by node statements added to the end of the graph. Edges are highlighted by
+// adding the attributes to the edge statements declaring them. Edge statements declaring multiple
+// edges like a -> b -> c or a -> {b c} are split into statements declaring a single edge each so
+// only the edges of the path are highlighted. The nodes and subgraphs of a split statement are
+// declared before its edges so they keep their order and attributes.
+//
+// An error is returned and the graph is left unchanged if an edge of the path is not declared in
+// g as the path was found in the model built from another graph.
+func Highlight(g *ast.Graph, p Path, attrs ...ast.Attribute) error {
+	if err := declares(*g, p); err != nil {
+		return err
+	}
+	if len(attrs) == 0 {
+		attrs = []ast.Attribute{
+			{Name: ast.NewID("color"), Value: ast.NewID("red")},
+			{Name: ast.NewID("penwidth"), Value: ast.NewID("2")},
+		}
+	}
+
+	split := make(map[*ast.EdgeStmt][]*ast.EdgeStmt)
+	for _, e := range p.Edges {
+		if !isSingleEdge(e.Stmt) {
+			split[e.Stmt] = nil
+		}
+	}
+	if len(split) > 0 {
+		g.Stmts = splitStmts(g.Stmts, split)
+	}
+
+	for _, e := range p.Edges {
+		stmt := e.Stmt
+		if edges, ok := split[stmt]; ok {
+			stmt = findEdge(edges, e)
+		}
+		stmt.AttrList = appendAttrs(stmt.AttrList, attrs)
+	}
+	for _, n := range p.Nodes {
+		g.Stmts = append(g.Stmts, &ast.NodeStmt{
+			NodeID:   ast.NodeID{ID: ast.NewID(n.ID)},
+			AttrList: appendAttrs(nil, attrs),
+		})
+	}
+	return nil
+}
+
+// declares returns an error if an edge of the path is not declared by an edge statement of the
+// graph. Highlighting such an edge would change a statement of another graph.
+func declares(g ast.Graph, p Path) error {
+	stmts := make(map[*ast.EdgeStmt]bool)
+	ast.Inspect(g, func(n ast.Node) bool {
+		if es, ok := n.(*ast.EdgeStmt); ok {
+			stmts[es] = true
+		}
+		return true
+	})
+	for _, e := range p.Edges {
+		if !stmts[e.Stmt] || findEdge(ast.ExpandEdges(e.Stmt), e) == nil {
+			return fmt.Errorf("edge %s -> %s of the path is not declared in the graph", e.Tail.ID, e.Head.ID)
+		}
+	}
+	return nil
+}
+
+// isSingleEdge reports whether the edge statement declares a single edge between two nodes.
+func isSingleEdge(es *ast.EdgeStmt) bool {
+	_, leftIsNode := es.Left.(ast.NodeID)
+	_, rightIsNode := es.Right.Right.(ast.NodeID)
+	return leftIsNode && rightIsNode && es.Right.Next == nil
+}
+
+// splitStmts replaces the edge statements that are keys of split with the statements they are split
+// into. The edge statements declaring a single edge each are recorded in split.
+func splitStmts(stmts []ast.Stmt, split map[*ast.EdgeStmt][]*ast.EdgeStmt) []ast.Stmt {
+	result := make([]ast.Stmt, 0, len(stmts))
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *ast.EdgeStmt:
+			st.Left = splitOperand(st.Left, split)
+			for cur := &st.Right; cur != nil; cur = cur.Next {
+				cur.Right = splitOperand(cur.Right, split)
+			}
+			if _, ok := split[st]; !ok {
+				result = append(result, st)
+				continue
+			}
+			decls, edges := splitEdges(st)
+			split[st] = edges
+			result = append(result, decls...)
+			for _, edge := range edges {
+				result = append(result, edge)
+			}
+		case ast.Subgraph:
+			st.Stmts = splitStmts(st.Stmts, split)
+			result = append(result, st)
+		default:
+			result = append(result, stmt)
+		}
+	}
+	return result
+}
+
+func splitOperand(operand ast.EdgeOperand, split map[*ast.EdgeStmt][]*ast.EdgeStmt) ast.EdgeOperand {
+	subgraph, ok := operand.(ast.Subgraph)
+	if !ok {
+		return operand
+	}
+	subgraph.Stmts = splitStmts(subgraph.Stmts, split)
+	return subgraph
+}
+
+// splitEdges splits the edge statement into statements declaring a single edge each using
+// [ast.ExpandEdges]. The declarations of the operands in their original order are returned if any
+// of the operands is a subgraph.
+func splitEdges(es *ast.EdgeStmt) ([]ast.Stmt, []*ast.EdgeStmt) {
+	edges := ast.ExpandEdges(es)
+	for _, edge := range edges {
+		edge.AttrList = appendAttrs(edge.AttrList, nil)
+	}
+
+	operands := []ast.EdgeOperand{es.Left}
+	for cur := &es.Right; cur != nil; cur = cur.Next {
+		operands = append(operands, cur.Right)
+	}
+	for _, operand := range operands {
+		if _, ok := operand.(ast.Subgraph); ok {
+			return operandDecls(operands), edges
+		}
+	}
+	return nil, edges
+}
+
+// operandDecls returns the statements declaring the operands.
+func operandDecls(operands []ast.EdgeOperand) []ast.Stmt {
+	result := make([]ast.Stmt, 0, len(operands))
+	for _, operand := range operands {
+		switch op := operand.(type) {
+		case ast.NodeID:
+			result = append(result, &ast.NodeStmt{NodeID: ast.NodeID{ID: op.ID}})
+		case ast.Subgraph:
+			result = append(result, op)
+		}
+	}
+	return result
+}
+
+// findEdge returns the edge statement declaring an edge from the tail to the head of the edge.
+func findEdge(edges []*ast.EdgeStmt, e *Edge) *ast.EdgeStmt {
+	for _, edge := range edges {
+		if edge.Left.(ast.NodeID).ID.Unquoted() == e.Tail.ID && edge.Right.Right.(ast.NodeID).ID.Unquoted() == e.Head.ID {
+			return edge
+		}
+	}
+	return nil
+}
+
+// appendAttrs returns a copy of the attribute list with the attributes appended to its last list.
+// The copy does not share any attribute list with the original so it can be changed independently.
+func appendAttrs(attrList *ast.AttrList, attrs []ast.Attribute) *ast.AttrList {
+	var first, last *ast.AttrList
+	for cur := attrList; cur != nil; cur = cur.Next {
+		list := &ast.AttrList{LeftBracket: cur.LeftBracket, AList: appendAList(cur.AList, nil), RightBracket: cur.RightBracket}
+		if first == nil {
+			first = list
+		} else {
+			last.Next = list
+		}
+		last = list
+	}
+	if len(attrs) == 0 {
+		return first
+	}
+	if first == nil {
+		first = &ast.AttrList{}
+		last = first
+	}
+	last.AList = appendAList(last.AList, attrs)
+	return first
+}
+
+// appendAList returns a copy of the attributes with given attributes appended.
+func appendAList(aList *ast.AList, attrs []ast.Attribute) *ast.AList {
+	var all []ast.Attribute
+	for cur := aList; cur != nil; cur = cur.Next {
+		all = append(all, cur.Attribute)
+	}
+	all = append(all, attrs...)
+
+	var first, prev *ast.AList
+	for _, attr := range all {
+		cur := &ast.AList{Attribute: attr}
+		if first == nil {
+			first = cur
+		} else {
+			prev.Next = cur
+		}
+		prev = cur
+	}
+	return first
+}
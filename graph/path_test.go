@@ -0,0 +1,358 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/graph"
+)
+
+func TestLongestPath(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		weight     string
+		wantNodes  []string
+		wantLength float64
+	}{
+		"Empty": {
+			in: `digraph {}`,
+		},
+		"SingleNode": {
+			in:        `digraph { a }`,
+			wantNodes: []string{"a"},
+		},
+		"MostEdges": {
+			in: `digraph {
+	checkout -> build -> test -> deploy
+	checkout -> lint -> deploy
+}`,
+			wantNodes:  []string{"checkout", "build", "test", "deploy"},
+			wantLength: 3,
+		},
+		"Weighted": {
+			in: `digraph {
+	checkout -> build [minutes=5]
+	build -> test [minutes=10]
+	test -> deploy [minutes=1]
+	checkout -> lint [minutes=20]
+	lint -> deploy
+}`,
+			weight:     "minutes",
+			wantNodes:  []string{"checkout", "lint", "deploy"},
+			wantLength: 21,
+		},
+		"WeightedViaEdgeDefaults": {
+			in: `digraph {
+	edge [minutes=2]
+	a -> b
+	a -> {c d} [minutes=0.5]
+}`,
+			weight:     "minutes",
+			wantNodes:  []string{"a", "b"},
+			wantLength: 2,
+		},
+		"TiesAreBrokenInTopologicalOrder": {
+			in: `digraph {
+	a -> c
+	b -> c
+	c -> d
+	c -> e
+}`,
+			wantNodes:  []string{"a", "c", "d"},
+			wantLength: 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got, err := graph.Build(g).LongestPath(test.weight)
+			require.NoErrorf(t, err, "LongestPath(%q)", test.weight)
+
+			var nodes []string
+			for _, n := range got.Nodes {
+				nodes = append(nodes, n.ID)
+			}
+			assert.EqualValuesf(t, nodes, test.wantNodes, "LongestPath(%q).Nodes", test.weight)
+			assert.EqualValuesf(t, len(got.Edges), max(len(got.Nodes)-1, 0), "LongestPath(%q).Edges", test.weight)
+			for i, e := range got.Edges {
+				assert.Truef(t, e.Tail == got.Nodes[i] && e.Head == got.Nodes[i+1], "LongestPath(%q).Edges[%d] connects %s -> %s", test.weight, i, e.Tail.ID, e.Head.ID)
+			}
+			assert.EqualValuesf(t, got.Length, test.wantLength, "LongestPath(%q).Length", test.weight)
+		})
+	}
+
+	errTests := map[string]struct {
+		in      string
+		weight  string
+		wantErr string
+	}{
+		"Undirected": {
+			in:      `graph { a -- b }`,
+			wantErr: "longest path requires a directed graph",
+		},
+		"Cycle": {
+			in: `digraph {
+	start -> a
+	a -> b
+	b -> a
+	b -> end
+}`,
+			wantErr: "2:11: graph has a cycle through node a",
+		},
+		"SelfLoop": {
+			in:      `digraph { a -> b -> b }`,
+			wantErr: "1:16: graph has a cycle through node b",
+		},
+		"WeightIsNotANumber": {
+			in:      `digraph { a -> b [minutes=five] }`,
+			weight:  "minutes",
+			wantErr: `1:11: edge a -> b has minutes "five" that is not a number`,
+		},
+	}
+
+	for name, test := range errTests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			_, err = graph.Build(g).LongestPath(test.weight)
+			require.NotNilf(t, err, "LongestPath(%q)", test.weight)
+			assert.EqualValuesf(t, err.Error(), test.wantErr, "LongestPath(%q)", test.weight)
+		})
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	tests := map[string]struct {
+		in         string
+		from, to   string
+		weight     string
+		wantNodes  []string
+		wantLength float64
+	}{
+		"SameNode": {
+			in:        `digraph { a -> b }`,
+			from:      "a",
+			to:        "a",
+			wantNodes: []string{"a"},
+		},
+		"FewestEdges": {
+			in: `digraph {
+	checkout -> build -> test -> deploy
+	checkout -> lint -> deploy
+}`,
+			from:       "checkout",
+			to:         "deploy",
+			wantNodes:  []string{"checkout", "lint", "deploy"},
+			wantLength: 2,
+		},
+		"Weighted": {
+			in: `digraph {
+	a -> b [minutes=2]
+	b -> c [minutes=2]
+	a -> c [minutes=5]
+	c -> d
+}`,
+			from:       "a",
+			to:         "d",
+			weight:     "minutes",
+			wantNodes:  []string{"a", "b", "c", "d"},
+			wantLength: 5,
+		},
+		"EdgesAreDirected": {
+			in:   `digraph { a -> b }`,
+			from: "b",
+			to:   "a",
+		},
+		"UndirectedEdgesAreFollowedBothWays": {
+			in:         `graph { a -- b; c -- b }`,
+			from:       "a",
+			to:         "c",
+			wantNodes:  []string{"a", "b", "c"},
+			wantLength: 2,
+		},
+		"Unreachable": {
+			in:   `digraph { a -> b; c }`,
+			from: "a",
+			to:   "c",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+			model := graph.Build(g)
+			from, _ := model.Node(test.from)
+			to, _ := model.Node(test.to)
+
+			got, err := model.ShortestPath(from, to, test.weight)
+			require.NoErrorf(t, err, "ShortestPath(%s, %s, %q)", test.from, test.to, test.weight)
+
+			var nodes []string
+			for _, n := range got.Nodes {
+				nodes = append(nodes, n.ID)
+			}
+			assert.EqualValuesf(t, nodes, test.wantNodes, "ShortestPath(%s, %s, %q).Nodes", test.from, test.to, test.weight)
+			assert.EqualValuesf(t, len(got.Edges), max(len(got.Nodes)-1, 0), "ShortestPath(%s, %s, %q).Edges", test.from, test.to, test.weight)
+			for i, e := range got.Edges {
+				connects := e.Tail == got.Nodes[i] && e.Head == got.Nodes[i+1] ||
+					!model.Directed && e.Head == got.Nodes[i] && e.Tail == got.Nodes[i+1]
+				assert.Truef(t, connects, "ShortestPath(%s, %s, %q).Edges[%d] connects %s -> %s", test.from, test.to, test.weight, i, e.Tail.ID, e.Head.ID)
+			}
+			assert.EqualValuesf(t, got.Length, test.wantLength, "ShortestPath(%s, %s, %q).Length", test.from, test.to, test.weight)
+		})
+	}
+
+	errTests := map[string]struct {
+		in      string
+		weight  string
+		wantErr string
+	}{
+		"WeightIsNotANumber": {
+			in:      `digraph { a -> b [minutes=five] }`,
+			weight:  "minutes",
+			wantErr: `1:11: edge a -> b has minutes "five" that is not a number`,
+		},
+		"WeightIsNegative": {
+			in:      `digraph { a -> b [minutes=-1] }`,
+			weight:  "minutes",
+			wantErr: `1:11: edge a -> b has minutes "-1" that is negative`,
+		},
+	}
+
+	for name, test := range errTests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+			model := graph.Build(g)
+
+			_, err = model.ShortestPath(model.Nodes[0], model.Nodes[1], test.weight)
+			require.NotNilf(t, err, "ShortestPath(%q)", test.weight)
+			assert.EqualValuesf(t, err.Error(), test.wantErr, "ShortestPath(%q)", test.weight)
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := map[string]struct {
+		in            string
+		attrs         []ast.Attribute
+		wantNodes     []string
+		wantEdges     []string
+		wantSubgraphs []string
+	}{
+		"SingleEdges": {
+			in: `digraph {
+	a -> b [label=x]
+	b -> c
+	a -> c
+}`,
+			wantNodes: []string{"a [color=red penwidth=2]", "b [color=red penwidth=2]", "c [color=red penwidth=2]"},
+			wantEdges: []string{"a -> b [label=x color=red penwidth=2]", "b -> c [color=red penwidth=2]", "a -> c []"},
+		},
+		"CustomAttributes": {
+			in: `digraph {
+	a -> b
+}`,
+			attrs:     []ast.Attribute{{Name: ast.NewID("style"), Value: ast.NewID("bold")}},
+			wantNodes: []string{"a [style=bold]", "b [style=bold]"},
+			wantEdges: []string{"a -> b [style=bold]"},
+		},
+		"ChainedEdgesAreSplit": {
+			in: `digraph {
+	x -> a -> b [label=x]
+	y -> z -> a
+}`,
+			wantNodes: []string{
+				"x []",
+				"a [color=red penwidth=2]",
+				"b [color=red penwidth=2]",
+				"y [color=red penwidth=2]",
+				"z [color=red penwidth=2]",
+			},
+			wantEdges: []string{
+				"x -> a [label=x]",
+				"a -> b [label=x color=red penwidth=2]",
+				"y -> z [color=red penwidth=2]",
+				"z -> a [color=red penwidth=2]",
+			},
+		},
+		"SubgraphOperandsAreSplit": {
+			in: `digraph {
+	a -> b
+	b -> subgraph s { node [shape=box] c; d -> e } -> f
+}`,
+			wantNodes: []string{
+				"a [color=red penwidth=2]",
+				"b [color=red penwidth=2]",
+				"c [shape=box]",
+				"d [shape=box color=red penwidth=2]",
+				"e [shape=box color=red penwidth=2]",
+				"f [color=red penwidth=2]",
+			},
+			wantEdges: []string{
+				"a -> b [color=red penwidth=2]",
+				"d -> e [color=red penwidth=2]",
+				"b -> c []",
+				"b -> d [color=red penwidth=2]",
+				"b -> e []",
+				"c -> f []",
+				"d -> f []",
+				"e -> f [color=red penwidth=2]",
+			},
+			wantSubgraphs: []string{"s [] {c d e}"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+			path, err := graph.Build(g).LongestPath("")
+			require.NoErrorf(t, err, "LongestPath(%q)", "")
+
+			err = graph.Highlight(&g, path, test.attrs...)
+			require.NoErrorf(t, err, "Highlight(%q)", test.in)
+
+			got := graph.Build(g)
+			var nodes, edges, subgraphs []string
+			for _, n := range got.Nodes {
+				nodes = append(nodes, n.ID+" "+attrs(n.Attrs))
+			}
+			for _, e := range got.Edges {
+				edges = append(edges, e.Tail.ID+" -> "+e.Head.ID+" "+attrs(e.Attrs))
+			}
+			for _, s := range got.Subgraphs {
+				subgraphs = append(subgraphs, subgraph(s))
+			}
+			assert.EqualValuesf(t, nodes, test.wantNodes, "Highlight(%q).Nodes", test.in)
+			assert.EqualValuesf(t, edges, test.wantEdges, "Highlight(%q).Edges", test.in)
+			assert.EqualValuesf(t, subgraphs, test.wantSubgraphs, "Highlight(%q).Subgraphs", test.in)
+		})
+	}
+
+	t.Run("PathOfAnotherGraph", func(t *testing.T) {
+		in := `digraph {
+	a -> b
+}`
+		g, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+		other, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+		path, err := graph.Build(other).LongestPath("")
+		require.NoErrorf(t, err, "LongestPath(%q)", "")
+
+		err = graph.Highlight(&g, path)
+
+		require.NotNilf(t, err, "Highlight(%q) with a path of another graph", in)
+		assert.EqualValuesf(t, err.Error(), "edge a -> b of the path is not declared in the graph", "Highlight(%q)", in)
+		assert.EqualValuesf(t, g.String(), "digraph {\n\ta -> b\n}", "Highlight(%q) should not change the graph", in)
+	})
+}
@@ -0,0 +1,192 @@
+package graph
+
+import "errors"
+
+// Descendants returns the nodes reachable from the node by following edges from their tail to their
+// head. The node itself is a descendant only if it is on a cycle. Edges of undirected graphs are
+// followed in both directions so the descendants are the other nodes of the connected component of
+// the node. Nodes are returned in the order they first appear in the graph.
+func (g *Graph) Descendants(n *Node) []*Node {
+	return reachable(g, n, func(e *Edge) (*Node, *Node) { return e.Tail, e.Head })
+}
+
+// Ancestors returns the nodes the node is reachable from. They are the nodes the node is a
+// descendant of as described by [Graph.Descendants].
+func (g *Graph) Ancestors(n *Node) []*Node {
+	return reachable(g, n, func(e *Edge) (*Node, *Node) { return e.Head, e.Tail })
+}
+
+// reachable returns the nodes reachable from the start node in the order they appear in the graph.
+// The direction returns the node an edge is followed from and the node it leads to.
+func reachable(g *Graph, start *Node, direction func(*Edge) (from, to *Node)) []*Node {
+	next := make(map[*Node][]*Node)
+	for _, e := range g.Edges {
+		from, to := direction(e)
+		next[from] = append(next[from], to)
+		if !g.Directed {
+			next[to] = append(next[to], from)
+		}
+	}
+
+	visited := make(map[*Node]bool)
+	queue := []*Node{start}
+	for i := 0; i < len(queue); i++ {
+		for _, n := range next[queue[i]] {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	if !g.Directed {
+		visited[start] = false // every undirected edge leads back to the start
+	}
+
+	var result []*Node
+	for _, n := range g.Nodes {
+		if visited[n] {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// Cycles returns the elementary cycles of a directed graph. A cycle is elementary if no node but
+// its first and last is visited twice. Every cycle is returned as a path starting and ending at
+// the node of the cycle that appears first in the graph. A self-loop is a cycle of a single edge.
+// Multi-edges between the same nodes are a single edge of which the first is part of the path.
+// Cycles are returned ordered by their start node. The graph is acyclic if there is none. An error
+// is returned if the graph is undirected.
+//
+// Cycles are found using Johnson's algorithm which takes time linear in the size of the graph for
+// every cycle it finds. Note that a graph can have exponentially many cycles in the number of its
+// nodes.
+func (g *Graph) Cycles() ([]Path, error) {
+	if !g.Directed {
+		return nil, errors.New("cycles requires a directed graph")
+	}
+
+	index := make(map[*Node]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n] = i
+	}
+	// out holds the first edge to every head by tail so multi-edges are followed once
+	out := make(map[*Node][]*Edge)
+	in := make(map[*Node][]*Node)
+	seen := make(map[[2]*Node]bool)
+	for _, e := range g.Edges {
+		if key := [2]*Node{e.Tail, e.Head}; !seen[key] {
+			seen[key] = true
+			out[e.Tail] = append(out[e.Tail], e)
+			in[e.Head] = append(in[e.Head], e.Tail)
+		}
+	}
+
+	var result []Path
+	for _, start := range g.Nodes {
+		c := cycles{
+			start:     start,
+			component: component(start, index, out, in),
+			out:       out,
+			blocked:   make(map[*Node]bool),
+			blocks:    make(map[*Node][]*Node),
+		}
+		c.circuit(start)
+		result = append(result, c.found...)
+	}
+	return result, nil
+}
+
+// component returns the strongly connected component of the start node in the subgraph induced by
+// the start node and the nodes appearing after it. Nodes appearing before it were the start of an
+// earlier search which found all cycles through them.
+func component(start *Node, index map[*Node]int, out map[*Node][]*Edge, in map[*Node][]*Node) map[*Node]bool {
+	forward := map[*Node]bool{start: true}
+	queue := []*Node{start}
+	for i := 0; i < len(queue); i++ {
+		for _, e := range out[queue[i]] {
+			if index[e.Head] > index[start] && !forward[e.Head] {
+				forward[e.Head] = true
+				queue = append(queue, e.Head)
+			}
+		}
+	}
+
+	result := map[*Node]bool{start: true}
+	queue = []*Node{start}
+	for i := 0; i < len(queue); i++ {
+		for _, n := range in[queue[i]] {
+			if forward[n] && !result[n] {
+				result[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return result
+}
+
+// cycles finds the cycles through the start node within its component.
+type cycles struct {
+	start     *Node
+	component map[*Node]bool
+	out       map[*Node][]*Edge
+	blocked   map[*Node]bool    // blocked holds the nodes that cannot lead back to the start without visiting the path again
+	blocks    map[*Node][]*Node // blocks holds the nodes to unblock by the node that blocks them
+	path      []*Edge           // path holds the edges from the start to the current node
+	found     []Path
+}
+
+// circuit extends the path by the edges of the node. It reports whether a cycle was found.
+func (c *cycles) circuit(n *Node) bool {
+	var found bool
+	c.blocked[n] = true
+	for _, e := range c.out[n] {
+		if !c.component[e.Head] {
+			continue
+		}
+		c.path = append(c.path, e)
+		if e.Head == c.start {
+			found = true
+			c.found = append(c.found, newCycle(c.path))
+		} else if !c.blocked[e.Head] && c.circuit(e.Head) {
+			found = true
+		}
+		c.path = c.path[:len(c.path)-1]
+	}
+
+	if found {
+		c.unblock(n)
+		return true
+	}
+	for _, e := range c.out[n] {
+		if c.component[e.Head] {
+			c.blocks[e.Head] = append(c.blocks[e.Head], n)
+		}
+	}
+	return false
+}
+
+func (c *cycles) unblock(n *Node) {
+	c.blocked[n] = false
+	blocks := c.blocks[n]
+	c.blocks[n] = nil
+	for _, b := range blocks {
+		if c.blocked[b] {
+			c.unblock(b)
+		}
+	}
+}
+
+// newCycle returns the path of the edges. Its length is the number of edges.
+func newCycle(edges []*Edge) Path {
+	result := Path{
+		Nodes:  []*Node{edges[0].Tail},
+		Edges:  make([]*Edge, len(edges)),
+		Length: float64(len(edges)),
+	}
+	copy(result.Edges, edges)
+	for _, e := range edges {
+		result.Nodes = append(result.Nodes, e.Head)
+	}
+	return result
+}
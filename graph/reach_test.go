@@ -0,0 +1,137 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+)
+
+func TestDescendantsAndAncestors(t *testing.T) {
+	tests := map[string]struct {
+		in              string
+		node            string
+		wantDescendants []string
+		wantAncestors   []string
+	}{
+		"Directed": {
+			in: `digraph {
+	deploy
+	build -> test -> deploy
+	checkout -> build
+	lint -> deploy
+}`,
+			node:            "build",
+			wantDescendants: []string{"deploy", "test"},
+			wantAncestors:   []string{"checkout"},
+		},
+		"NodeOnCycleIsItsOwnDescendant": {
+			in:              `digraph { a -> b -> a; b -> c }`,
+			node:            "a",
+			wantDescendants: []string{"a", "b", "c"},
+			wantAncestors:   []string{"a", "b"},
+		},
+		"Undirected": {
+			in:              `graph { a -- b; c -- b; d }`,
+			node:            "b",
+			wantDescendants: []string{"a", "c"},
+			wantAncestors:   []string{"a", "c"},
+		},
+		"Isolated": {
+			in:   `digraph { a; b -> c }`,
+			node: "a",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+			model := graph.Build(g)
+			n, ok := model.Node(test.node)
+			require.Truef(t, ok, "Node(%q)", test.node)
+
+			assert.EqualValuesf(t, ids(model.Descendants(n)), test.wantDescendants, "Descendants(%s)", test.node)
+			assert.EqualValuesf(t, ids(model.Ancestors(n)), test.wantAncestors, "Ancestors(%s)", test.node)
+		})
+	}
+}
+
+func TestCycles(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want [][]string
+	}{
+		"Acyclic": {
+			in: `digraph { a -> b -> c; a -> c }`,
+		},
+		"Cycles": {
+			in: `digraph {
+	a -> b -> c -> a
+	b -> a
+	c -> c
+	d -> e -> d
+	e -> a
+}`,
+			want: [][]string{
+				{"a", "b", "c", "a"},
+				{"a", "b", "a"},
+				{"c", "c"},
+				{"d", "e", "d"},
+			},
+		},
+		"MultiEdgesAreFollowedOnce": {
+			in: `digraph { a -> b; a -> b; b -> a }`,
+			want: [][]string{
+				{"a", "b", "a"},
+			},
+		},
+		"CompleteGraph": {
+			in: `digraph { 1 -> {2 3}; 2 -> {1 3}; 3 -> {1 2} }`,
+			want: [][]string{
+				{"1", "2", "1"},
+				{"1", "2", "3", "1"},
+				{"1", "3", "1"},
+				{"1", "3", "2", "1"},
+				{"2", "3", "2"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got, err := graph.Build(g).Cycles()
+			require.NoErrorf(t, err, "Cycles()")
+
+			var cycles [][]string
+			for _, c := range got {
+				cycles = append(cycles, ids(c.Nodes))
+				assert.EqualValuesf(t, len(c.Edges), len(c.Nodes)-1, "Cycles() edges of %v", ids(c.Nodes))
+				assert.EqualValuesf(t, c.Length, float64(len(c.Edges)), "Cycles() length of %v", ids(c.Nodes))
+			}
+			assert.EqualValuesf(t, cycles, test.want, "Cycles()")
+		})
+	}
+
+	t.Run("Undirected", func(t *testing.T) {
+		g, err := dot.Parse([]byte(`graph { a -- b -- a }`))
+		require.NoErrorf(t, err, "Parse()")
+
+		_, err = graph.Build(g).Cycles()
+		require.NotNilf(t, err, "Cycles()")
+		assert.EqualValuesf(t, err.Error(), "cycles requires a directed graph", "Cycles()")
+	})
+}
+
+func ids(nodes []*graph.Node) []string {
+	var result []string
+	for _, n := range nodes {
+		result = append(result, n.ID)
+	}
+	return result
+}
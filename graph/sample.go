@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// SampleStrategy defines how [Graph.Sample] picks the nodes of a sample.
+type SampleStrategy int
+
+const (
+	ForestFire SampleStrategy = iota // ForestFire spreads from random nodes to random shares of their neighbors like a fire.
+	RandomWalk                       // RandomWalk walks from a random node to random neighbors returning to its start now and then.
+	TopDegree                        // TopDegree picks the nodes with the most edges.
+)
+
+var sampleStrategyStrings = map[SampleStrategy]string{
+	ForestFire: "forest-fire",
+	RandomWalk: "random-walk",
+	TopDegree:  "top-degree",
+}
+
+func (s SampleStrategy) String() string {
+	return sampleStrategyStrings[s]
+}
+
+// burnProbability is the probability of the fire of [ForestFire] to spread to one more neighbor.
+const burnProbability = 0.7
+
+// flyBackProbability is the probability of [RandomWalk] to return to the node it started at.
+const flyBackProbability = 0.15
+
+// Sample returns a copy of the AST of the graph keeping a sample of n of its nodes as described
+// by [Graph.Filter]. The sample is meant for visualizing graphs too large to render. Sampled nodes
+// stay in their subgraphs and clusters and keep the edges between them. Edges are followed in both
+// directions. All nodes are kept if the graph has no more than n nodes.
+//
+// The [ForestFire] and [RandomWalk] strategies keep the local structure of the graph as they pick
+// neighbors of sampled nodes. They start over at a random node that is not yet sampled once they
+// cannot reach any more nodes. The randomness is taken from rnd so samples can be reproduced. The
+// [TopDegree] strategy does not use rnd which can be nil. It keeps the hubs of the graph in the
+// order they appear if their degrees are equal.
+func (g *Graph) Sample(n int, strategy SampleStrategy, rnd *rand.Rand) ast.Graph {
+	n = max(n, 0)
+	if n >= len(g.Nodes) {
+		return g.Filter(nil, nil)
+	}
+
+	neighbors := make(map[*Node][]*Node)
+	for _, e := range g.Edges {
+		neighbors[e.Tail] = append(neighbors[e.Tail], e.Head)
+		neighbors[e.Head] = append(neighbors[e.Head], e.Tail)
+	}
+
+	s := sample{g: g, n: n, neighbors: neighbors, rnd: rnd, nodes: make(map[*Node]bool)}
+	switch strategy {
+	case ForestFire:
+		s.forestFire()
+	case RandomWalk:
+		s.randomWalk()
+	case TopDegree:
+		s.topDegree()
+	}
+	return g.Filter(func(n *Node) bool { return s.nodes[n] }, nil)
+}
+
+type sample struct {
+	g         *Graph
+	n         int
+	neighbors map[*Node][]*Node
+	rnd       *rand.Rand
+	nodes     map[*Node]bool // nodes holds the sampled nodes
+}
+
+func (s *sample) full() bool {
+	return len(s.nodes) >= s.n
+}
+
+func (s *sample) add(n *Node) {
+	s.nodes[n] = true
+}
+
+// unsampled returns a random node that is not yet sampled.
+func (s *sample) unsampled() *Node {
+	var candidates []*Node
+	for _, n := range s.g.Nodes {
+		if !s.nodes[n] {
+			candidates = append(candidates, n)
+		}
+	}
+	return candidates[s.rnd.IntN(len(candidates))]
+}
+
+func (s *sample) forestFire() {
+	for !s.full() {
+		seed := s.unsampled()
+		s.add(seed)
+		burning := []*Node{seed}
+		for len(burning) > 0 && !s.full() {
+			n := burning[0]
+			burning = burning[1:]
+
+			var unburnt []*Node
+			for _, neighbor := range s.neighbors[n] {
+				if !s.nodes[neighbor] && !slices.Contains(unburnt, neighbor) {
+					unburnt = append(unburnt, neighbor)
+				}
+			}
+			s.rnd.Shuffle(len(unburnt), func(i, j int) { unburnt[i], unburnt[j] = unburnt[j], unburnt[i] })
+			// the fire spreads to a geometrically distributed number of neighbors
+			for _, neighbor := range unburnt {
+				if s.full() || s.rnd.Float64() >= burnProbability {
+					break
+				}
+				s.add(neighbor)
+				burning = append(burning, neighbor)
+			}
+		}
+	}
+}
+
+func (s *sample) randomWalk() {
+	for !s.full() {
+		start := s.unsampled()
+		s.add(start)
+		// give up on the walk if it does not find a new node as it is stuck in a small component
+		for cur, steps := start, 0; !s.full() && steps < 100*s.n; steps++ {
+			neighbors := s.neighbors[cur]
+			if len(neighbors) == 0 || s.rnd.Float64() < flyBackProbability {
+				cur = start
+				if len(s.neighbors[start]) == 0 {
+					break
+				}
+				continue
+			}
+			cur = neighbors[s.rnd.IntN(len(neighbors))]
+			if !s.nodes[cur] {
+				s.add(cur)
+				steps = 0
+			}
+		}
+	}
+}
+
+func (s *sample) topDegree() {
+	nodes := slices.Clone(s.g.Nodes)
+	slices.SortStableFunc(nodes, func(a, b *Node) int {
+		return cmp.Compare(len(s.neighbors[b]), len(s.neighbors[a]))
+	})
+	for _, n := range nodes[:s.n] {
+		s.add(n)
+	}
+}
@@ -0,0 +1,53 @@
+package graph_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+)
+
+func TestSample(t *testing.T) {
+	in := `graph {
+	subgraph cluster_core {
+		hub -- {a b c d}
+		a -- b
+	}
+	c -- e -- f
+	g -- h
+	i
+}`
+	tree, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+	g := graph.Build(tree)
+
+	t.Run("TopDegree", func(t *testing.T) {
+		got := graph.Build(g.Sample(3, graph.TopDegree, nil))
+
+		assert.EqualValuesf(t, ids(got.Nodes), []string{"hub", "a", "b"}, "Sample(3, TopDegree).Nodes")
+		require.EqualValuesf(t, len(got.Subgraphs), 1, "Sample(3, TopDegree).Subgraphs")
+		assert.EqualValuesf(t, got.Subgraphs[0].ID, "cluster_core", "Sample(3, TopDegree).Subgraphs")
+		assert.EqualValuesf(t, len(got.Edges), 3, "Sample(3, TopDegree).Edges")
+	})
+
+	for _, strategy := range []graph.SampleStrategy{graph.ForestFire, graph.RandomWalk} {
+		t.Run(strategy.String(), func(t *testing.T) {
+			for n := 0; n <= len(g.Nodes); n++ {
+				got := graph.Build(g.Sample(n, strategy, rand.New(rand.NewPCG(1, 2))))
+				again := graph.Build(g.Sample(n, strategy, rand.New(rand.NewPCG(1, 2))))
+
+				assert.EqualValuesf(t, len(got.Nodes), n, "Sample(%d, %s).Nodes", n, strategy)
+				assert.EqualValuesf(t, ids(again.Nodes), ids(got.Nodes), "Sample(%d, %s) with the same seed", n, strategy)
+			}
+		})
+	}
+
+	t.Run("AllNodes", func(t *testing.T) {
+		got := g.Sample(len(g.Nodes)+1, graph.ForestFire, nil)
+
+		assert.EqualValuesf(t, got.String(), tree.String(), "Sample(%d, ForestFire)", len(g.Nodes)+1)
+	})
+}
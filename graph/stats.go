@@ -0,0 +1,112 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// Stats summarizes the size and shape of a graph.
+type Stats struct {
+	Nodes      int            // Nodes is the number of nodes.
+	Edges      int            // Edges is the number of edges. Edges of strict graphs connecting the same nodes count once.
+	Subgraphs  int            // Subgraphs is the number of subgraphs including nested subgraphs and clusters.
+	Clusters   int            // Clusters is the number of subgraphs with an ID starting with cluster.
+	MaxDepth   int            // MaxDepth is the deepest nesting of subgraphs. It is 0 if the graph has no subgraphs.
+	Attrs      map[string]int // Attrs counts how often attributes are set in the source by their name.
+	Components int            // Components is the number of connected components ignoring the direction of edges.
+	Acyclic    bool           // Acyclic indicates that the graph has no cycle. Self-loops are cycles.
+}
+
+// Stats computes statistics of the graph. Attributes are counted as they appear in the source so
+// an attribute of a node attribute statement counts once no matter how many nodes it applies to.
+func (g *Graph) Stats() Stats {
+	result := Stats{
+		Nodes:      len(g.Nodes),
+		Edges:      len(g.Edges),
+		Attrs:      make(map[string]int),
+		Components: components(g),
+	}
+
+	var visit func(subgraphs []*Subgraph, depth int)
+	visit = func(subgraphs []*Subgraph, depth int) {
+		for _, s := range subgraphs {
+			result.Subgraphs++
+			if strings.HasPrefix(s.ID, "cluster") {
+				result.Clusters++
+			}
+			result.MaxDepth = max(result.MaxDepth, depth)
+			visit(s.Subgraphs, depth+1)
+		}
+	}
+	visit(g.Subgraphs, 1)
+
+	ast.Inspect(g.AST, func(n ast.Node) bool {
+		if a, ok := n.(ast.Attribute); ok {
+			result.Attrs[a.Name.Unquoted()]++
+			return false
+		}
+		return true
+	})
+
+	if g.Directed {
+		result.Acyclic = isAcyclic(g)
+	} else {
+		// an undirected graph is a forest if every component is a tree with one edge less than nodes
+		result.Acyclic = result.Edges == result.Nodes-result.Components
+	}
+	return result
+}
+
+// components returns the number of connected components of the graph ignoring the direction of
+// its edges.
+func components(g *Graph) int {
+	parent := make(map[*Node]*Node, len(g.Nodes))
+	var find func(n *Node) *Node
+	find = func(n *Node) *Node {
+		p, ok := parent[n]
+		if !ok || p == n {
+			return n
+		}
+		root := find(p)
+		parent[n] = root
+		return root
+	}
+
+	result := len(g.Nodes)
+	for _, e := range g.Edges {
+		tail, head := find(e.Tail), find(e.Head)
+		if tail != head {
+			parent[tail] = head
+			result--
+		}
+	}
+	return result
+}
+
+// isAcyclic determines if the directed graph has no cycle by sorting it topologically using Kahn's
+// algorithm. Nodes on a cycle are never sorted.
+func isAcyclic(g *Graph) bool {
+	out := make(map[*Node][]*Node)
+	inDegree := make(map[*Node]int)
+	for _, e := range g.Edges {
+		out[e.Tail] = append(out[e.Tail], e.Head)
+		inDegree[e.Head]++
+	}
+
+	var order []*Node
+	for _, n := range g.Nodes {
+		if inDegree[n] == 0 {
+			order = append(order, n)
+		}
+	}
+	for i := 0; i < len(order); i++ {
+		for _, head := range out[order[i]] {
+			inDegree[head]--
+			if inDegree[head] == 0 {
+				order = append(order, head)
+			}
+		}
+	}
+	return len(order) == len(g.Nodes)
+}
@@ -0,0 +1,107 @@
+package graph_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/graph"
+)
+
+func TestStats(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want graph.Stats
+	}{
+		"Empty": {
+			in:   `digraph {}`,
+			want: graph.Stats{Attrs: map[string]int{}, Acyclic: true},
+		},
+		"Directed": {
+			in: `digraph {
+	rankdir=LR
+	node [shape=box]
+	subgraph cluster_build {
+		label=build
+		a -> b [color=red]
+		subgraph { rank=same c d }
+	}
+	b -> {e f} [color=blue]
+	g
+}`,
+			want: graph.Stats{
+				Nodes:      7,
+				Edges:      3,
+				Subgraphs:  3,
+				Clusters:   1,
+				MaxDepth:   2,
+				Attrs:      map[string]int{"rankdir": 1, "shape": 1, "label": 1, "color": 2, "rank": 1},
+				Components: 4,
+				Acyclic:    true,
+			},
+		},
+		"DirectedCycle": {
+			in: `digraph {
+	a -> b -> c -> a
+	d -> d
+}`,
+			want: graph.Stats{
+				Nodes:      4,
+				Edges:      4,
+				Attrs:      map[string]int{},
+				Components: 2,
+				Acyclic:    false,
+			},
+		},
+		"UndirectedForest": {
+			in: `graph {
+	a -- b -- c
+	d -- e
+}`,
+			want: graph.Stats{
+				Nodes:      5,
+				Edges:      3,
+				Attrs:      map[string]int{},
+				Components: 2,
+				Acyclic:    true,
+			},
+		},
+		"UndirectedCycle": {
+			in: `graph {
+	a -- b -- c -- a
+}`,
+			want: graph.Stats{
+				Nodes:      3,
+				Edges:      3,
+				Attrs:      map[string]int{},
+				Components: 1,
+				Acyclic:    false,
+			},
+		},
+		"StrictMergesEdges": {
+			in: `strict graph {
+	a -- b
+	b -- a
+}`,
+			want: graph.Stats{
+				Nodes:      2,
+				Edges:      1,
+				Attrs:      map[string]int{},
+				Components: 1,
+				Acyclic:    true,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := graph.Build(g).Stats()
+
+			assert.EqualValuesf(t, got, test.want, "Stats(%q)", test.in)
+		})
+	}
+}
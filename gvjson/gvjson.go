@@ -0,0 +1,407 @@
+// Package gvjson reads and writes graphs in the JSON format of Graphviz as produced by dot -Tjson
+// or dot -Tjson0. Refer to https://graphviz.org/docs/outputs/json/ for the format.
+//
+// The JSON output holds the attributes computed by the Graphviz layout like the positions of nodes
+// and edges in pos or the bounding box of the graph in bb. Reading it back into an [ast.Graph]
+// allows manipulating a graph with this module while keeping the layout computed by Graphviz.
+// Writing a graph allows handing it to tools like web visualizations that understand the objects
+// and edges of the Graphviz JSON format without running Graphviz.
+package gvjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/graph"
+	"github.com/teleivo/dot/token"
+)
+
+// Read reads a graph in the JSON format of Graphviz from r.
+//
+// Graph, subgraph, node and edge attributes are kept including the ones computed by the layout like
+// pos. Drawing operations like _draw_ of dot -Tjson are dropped as they cannot be expressed in dot.
+// Subgraphs only list their nodes. Nodes are declared with their attributes in the root graph
+// followed by the edges. Attributes are sorted by name as the JSON format does not preserve their
+// order.
+func Read(r io.Reader) (ast.Graph, error) {
+	var in jsonGraph
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return ast.Graph{}, fmt.Errorf("failed to decode Graphviz JSON: %w", err)
+	}
+
+	subgraphs := make(map[int]object)
+	nodes := make(map[int]object)
+	var nodeOrder []int
+	nested := make(map[int]bool)
+	for i, obj := range in.Objects {
+		if obj.GVID == nil {
+			return ast.Graph{}, errors.New("failed to decode Graphviz JSON: object is missing its _gvid")
+		}
+		// subgraphs are listed before the nodes
+		if i < in.SubgraphCount {
+			subgraphs[*obj.GVID] = obj
+			for _, id := range obj.Subgraphs {
+				nested[id] = true
+			}
+		} else {
+			nodes[*obj.GVID] = obj
+			nodeOrder = append(nodeOrder, *obj.GVID)
+		}
+	}
+
+	g := ast.Graph{
+		Directed: in.Directed,
+	}
+	if in.Strict {
+		g.StrictStart = &token.Position{}
+	}
+	if in.Name != "" {
+		id := ast.NewID(in.Name)
+		g.ID = &id
+	}
+
+	for _, attr := range in.attributes {
+		g.Stmts = append(g.Stmts, attr)
+	}
+
+	for _, obj := range in.Objects {
+		if _, ok := subgraphs[*obj.GVID]; ok && !nested[*obj.GVID] {
+			sub, err := newSubgraph(obj, subgraphs, nodes, make(map[int]bool))
+			if err != nil {
+				return ast.Graph{}, err
+			}
+			g.Stmts = append(g.Stmts, sub)
+		}
+	}
+
+	for _, id := range nodeOrder {
+		obj := nodes[id]
+		g.Stmts = append(g.Stmts, &ast.NodeStmt{
+			NodeID:   ast.NodeID{ID: ast.NewID(obj.Name)},
+			AttrList: newAttrList(obj.attributes),
+		})
+	}
+
+	for _, e := range in.Edges {
+		tail, ok := nodes[e.Tail]
+		if !ok {
+			return ast.Graph{}, fmt.Errorf("failed to decode Graphviz JSON: edge tail %d is not a node", e.Tail)
+		}
+		head, ok := nodes[e.Head]
+		if !ok {
+			return ast.Graph{}, fmt.Errorf("failed to decode Graphviz JSON: edge head %d is not a node", e.Head)
+		}
+		g.Stmts = append(g.Stmts, &ast.EdgeStmt{
+			Left: ast.NodeID{ID: ast.NewID(tail.Name)},
+			Right: ast.EdgeRHS{
+				Directed: in.Directed,
+				Right:    ast.NodeID{ID: ast.NewID(head.Name)},
+			},
+			AttrList: newAttrList(e.attributes),
+		})
+	}
+
+	return g, nil
+}
+
+// newSubgraph creates the subgraph of obj and its nested subgraphs. The path holds the IDs of the
+// subgraphs obj is nested in so cyclic references between subgraphs are reported.
+func newSubgraph(obj object, subgraphs, nodes map[int]object, path map[int]bool) (ast.Subgraph, error) {
+	if path[*obj.GVID] {
+		return ast.Subgraph{}, fmt.Errorf("failed to decode Graphviz JSON: subgraph %d of %q is nested in itself", *obj.GVID, obj.Name)
+	}
+	if len(path) >= dot.MaxDepth {
+		return ast.Subgraph{}, fmt.Errorf("failed to decode Graphviz JSON: subgraphs are nested deeper than the max depth of %d", dot.MaxDepth)
+	}
+	path[*obj.GVID] = true
+	defer delete(path, *obj.GVID)
+
+	sub := ast.Subgraph{
+		SubgraphStart: &token.Position{},
+	}
+	if obj.Name != "" {
+		id := ast.NewID(obj.Name)
+		sub.ID = &id
+	}
+	for _, attr := range obj.attributes {
+		sub.Stmts = append(sub.Stmts, attr)
+	}
+
+	// nodes of nested subgraphs are also listed on their parents so only declare them once in the
+	// innermost subgraph
+	inNested := make(map[int]bool)
+	for _, id := range obj.Subgraphs {
+		nestedObj, ok := subgraphs[id]
+		if !ok {
+			return ast.Subgraph{}, fmt.Errorf("failed to decode Graphviz JSON: subgraph %d of %q is not a subgraph", id, obj.Name)
+		}
+		for _, nodeID := range nestedObj.Nodes {
+			inNested[nodeID] = true
+		}
+		nested, err := newSubgraph(nestedObj, subgraphs, nodes, path)
+		if err != nil {
+			return ast.Subgraph{}, err
+		}
+		sub.Stmts = append(sub.Stmts, nested)
+	}
+	for _, id := range obj.Nodes {
+		node, ok := nodes[id]
+		if !ok {
+			return ast.Subgraph{}, fmt.Errorf("failed to decode Graphviz JSON: node %d of %q is not a node", id, obj.Name)
+		}
+		if inNested[id] {
+			continue
+		}
+		sub.Stmts = append(sub.Stmts, &ast.NodeStmt{NodeID: ast.NodeID{ID: ast.NewID(node.Name)}})
+	}
+
+	return sub, nil
+}
+
+func newAttrList(attrs []ast.Attribute) *ast.AttrList {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	var first, prev *ast.AList
+	for _, attr := range attrs {
+		cur := &ast.AList{Attribute: attr}
+		if first == nil {
+			first = cur
+		} else {
+			prev.Next = cur
+		}
+		prev = cur
+	}
+	return &ast.AttrList{AList: first}
+}
+
+// Write writes the graph in the JSON format of Graphviz as produced by dot -Tjson0 to w.
+//
+// The graph is written as interpreted by Graphviz using the model of package graph. Nodes are
+// unique by their ID and edges between subgraphs are expanded into edges between their nodes.
+// Nodes and edges list the attributes in effect for them including the ones set by attribute
+// statements like node [shape=box]. The ports of edges are written as tailport and headport. The
+// objects list all subgraphs in the order they appear followed by the nodes. Subgraphs list their
+// nodes and nested subgraphs but not their edges. Attributes computed by a layout are only written
+// if they are set in the graph.
+func Write(w io.Writer, g ast.Graph) error {
+	model := graph.Build(g)
+
+	var subgraphs []*graph.Subgraph
+	var collect func(subs []*graph.Subgraph)
+	collect = func(subs []*graph.Subgraph) {
+		for _, sub := range subs {
+			subgraphs = append(subgraphs, sub)
+			collect(sub.Subgraphs)
+		}
+	}
+	collect(model.Subgraphs)
+
+	gvids := make(map[any]int, len(subgraphs)+len(model.Nodes))
+	for i, sub := range subgraphs {
+		gvids[sub] = i
+	}
+	for i, n := range model.Nodes {
+		gvids[n] = len(subgraphs) + i
+	}
+
+	objects := make([]fields, 0, len(subgraphs)+len(model.Nodes))
+	for _, sub := range subgraphs {
+		obj := fields{{"_gvid", gvids[sub]}, {"name", sub.ID}}
+		obj = obj.appendAttrs(sub.Attrs)
+		if len(sub.Subgraphs) > 0 {
+			ids := make([]int, 0, len(sub.Subgraphs))
+			for _, nested := range sub.Subgraphs {
+				ids = append(ids, gvids[nested])
+			}
+			obj = append(obj, field{"subgraphs", ids})
+		}
+		ids := make([]int, 0, len(sub.Nodes))
+		for _, n := range sub.Nodes {
+			ids = append(ids, gvids[n])
+		}
+		objects = append(objects, append(obj, field{"nodes", ids}))
+	}
+	for _, n := range model.Nodes {
+		obj := fields{{"_gvid", gvids[n]}, {"name", n.ID}}
+		objects = append(objects, obj.appendAttrs(n.Attrs))
+	}
+
+	edges := make([]fields, 0, len(model.Edges))
+	for i, e := range model.Edges {
+		obj := fields{{"_gvid", i}, {"tail", gvids[e.Tail]}, {"head", gvids[e.Head]}}
+		if e.TailPort != nil {
+			obj = append(obj, field{"tailport", port(e.TailPort)})
+		}
+		if e.HeadPort != nil {
+			obj = append(obj, field{"headport", port(e.HeadPort)})
+		}
+		edges = append(edges, obj.appendAttrs(e.Attrs))
+	}
+
+	root := fields{
+		{"name", model.ID},
+		{"directed", model.Directed},
+		{"strict", model.Strict},
+	}
+	root = root.appendAttrs(model.Attrs)
+	root = append(root, field{"_subgraph_cnt", len(subgraphs)}, field{"objects", objects}, field{"edges", edges})
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+// port returns the port as written in the tailport and headport attributes like p:n.
+func port(p *ast.Port) string {
+	var result []string
+	if p.Name != nil {
+		result = append(result, p.Name.Unquoted())
+	}
+	if p.CompassPoint != nil {
+		result = append(result, p.CompassPoint.String())
+	}
+	return strings.Join(result, ":")
+}
+
+// field is a field of a JSON object.
+type field struct {
+	name  string
+	value any
+}
+
+// fields is a JSON object that keeps the order of its fields.
+type fields []field
+
+// appendAttrs appends the attributes as string fields. The last value of an attribute set multiple
+// times wins. Attributes keep the position they were first set at.
+func (f fields) appendAttrs(attrs []graph.Attribute) fields {
+	index := make(map[string]int, len(attrs))
+	for _, attr := range attrs {
+		if i, ok := index[attr.Name]; ok {
+			f[i].value = attr.Value
+			continue
+		}
+		index[attr.Name] = len(f)
+		f = append(f, field{attr.Name, attr.Value})
+	}
+	return f
+}
+
+func (f fields) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+	out.WriteByte('{')
+	for i, field := range f {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		// HTML-like labels are kept readable as the encoder escapes <, > and & by default
+		enc := json.NewEncoder(&out)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(field.name); err != nil {
+			return nil, err
+		}
+		out.Truncate(out.Len() - 1) // drop the newline written by Encode
+		out.WriteByte(':')
+		if err := enc.Encode(field.value); err != nil {
+			return nil, err
+		}
+		out.Truncate(out.Len() - 1)
+	}
+	out.WriteByte('}')
+	return out.Bytes(), nil
+}
+
+// jsonGraph is the root object of the Graphviz JSON format.
+type jsonGraph struct {
+	Name          string   `json:"name"`
+	Directed      bool     `json:"directed"`
+	Strict        bool     `json:"strict"`
+	SubgraphCount int      `json:"_subgraph_cnt"`
+	Objects       []object `json:"objects"`
+	Edges         []edge   `json:"edges"`
+	attributes    []ast.Attribute
+}
+
+func (g *jsonGraph) UnmarshalJSON(b []byte) error {
+	type plain jsonGraph
+	if err := json.Unmarshal(b, (*plain)(g)); err != nil {
+		return err
+	}
+	attrs, err := unmarshalAttributes(b, "name", "directed", "strict", "_subgraph_cnt", "objects", "edges")
+	g.attributes = attrs
+	return err
+}
+
+// object is a subgraph or a node in the Graphviz JSON format.
+type object struct {
+	GVID       *int   `json:"_gvid"`
+	Name       string `json:"name"`
+	Nodes      []int  `json:"nodes"`
+	Edges      []int  `json:"edges"`
+	Subgraphs  []int  `json:"subgraphs"`
+	attributes []ast.Attribute
+}
+
+func (o *object) UnmarshalJSON(b []byte) error {
+	type plain object
+	if err := json.Unmarshal(b, (*plain)(o)); err != nil {
+		return err
+	}
+	attrs, err := unmarshalAttributes(b, "_gvid", "name", "nodes", "edges", "subgraphs")
+	o.attributes = attrs
+	return err
+}
+
+// edge is an edge in the Graphviz JSON format.
+type edge struct {
+	GVID       int `json:"_gvid"`
+	Tail       int `json:"tail"`
+	Head       int `json:"head"`
+	attributes []ast.Attribute
+}
+
+func (e *edge) UnmarshalJSON(b []byte) error {
+	type plain edge
+	if err := json.Unmarshal(b, (*plain)(e)); err != nil {
+		return err
+	}
+	attrs, err := unmarshalAttributes(b, "_gvid", "tail", "head")
+	e.attributes = attrs
+	return err
+}
+
+// unmarshalAttributes unmarshals all string valued fields of the JSON object b into attributes
+// sorted by name. Fields with given names are skipped as well as fields of other types like the
+// drawing operations.
+func unmarshalAttributes(b []byte, skip ...string) ([]ast.Attribute, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	var result []ast.Attribute
+	for name, raw := range fields {
+		if slices.Contains(skip, name) {
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			continue
+		}
+		result = append(result, ast.Attribute{Name: ast.NewID(name), Value: ast.NewID(value)})
+	}
+	slices.SortFunc(result, func(a, b ast.Attribute) int {
+		return strings.Compare(a.Name.Unquoted(), b.Name.Unquoted())
+	})
+	return result, nil
+}
@@ -0,0 +1,221 @@
+package gvjson_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/gvjson"
+	"github.com/teleivo/dot/internal/assertx"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string]struct {
+			in   string
+			want string
+		}{
+			"Empty": {
+				in:   `{"name": "G", "directed": false, "strict": false, "_subgraph_cnt": 0}`,
+				want: `graph G {}`,
+			},
+			"NodesAndEdgesWithLayout": {
+				in: `{
+  "name": "G",
+  "directed": true,
+  "strict": true,
+  "bb": "0,0,54,108",
+  "_subgraph_cnt": 0,
+  "objects": [
+    {"_gvid": 0, "name": "A", "label": "\\N", "pos": "27,90", "width": "0.75",
+     "_draw_": [{"op": "e", "rect": [27.0, 90.0, 27.0, 18.0]}]},
+    {"_gvid": 1, "name": "B 1", "label": "say \"hi\"", "pos": "27,18"}
+  ],
+  "edges": [
+    {"_gvid": 0, "tail": 0, "head": 1, "pos": "e,27,36.104 27,71.697", "label": "edge"}
+  ]
+}`,
+				want: `strict digraph G {
+	bb="0,0,54,108"
+	A [label="\N",pos="27,90",width=0.75]
+	"B 1" [label="say \"hi\"",pos="27,18"]
+	A -> "B 1" [label="edge",pos="e,27,36.104 27,71.697"]
+}`,
+			},
+			"Clusters": {
+				in: `{
+  "name": "G",
+  "directed": false,
+  "_subgraph_cnt": 2,
+  "objects": [
+    {"_gvid": 0, "name": "cluster_a", "label": "A", "bb": "8,8,70,83", "subgraphs": [1], "nodes": [2, 3]},
+    {"_gvid": 1, "name": "cluster_b", "nodes": [3]},
+    {"_gvid": 2, "name": "A"},
+    {"_gvid": 3, "name": "B"},
+    {"_gvid": 4, "name": "C"}
+  ],
+  "edges": [
+    {"_gvid": 0, "tail": 2, "head": 4}
+  ]
+}`,
+				want: `graph G {
+	subgraph cluster_a {bb="8,8,70,83" label=A subgraph cluster_b {B} A}
+	A
+	B
+	C
+	A -- C
+}`,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				g, err := gvjson.Read(strings.NewReader(test.in))
+
+				require.NoErrorf(t, err, "Read(%q)", test.in)
+				assert.EqualValuesf(t, g.String(), test.want, "Read(%q)", test.in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			in     string
+			errMsg string
+		}{
+			"InvalidJSON": {
+				in:     `{"name": `,
+				errMsg: "failed to decode Graphviz JSON",
+			},
+			"EdgeToUnknownNode": {
+				in:     `{"objects": [{"_gvid": 0, "name": "A"}], "edges": [{"_gvid": 0, "tail": 0, "head": 1}]}`,
+				errMsg: "edge head 1 is not a node",
+			},
+			"SubgraphWithUnknownNode": {
+				in:     `{"_subgraph_cnt": 1, "objects": [{"_gvid": 0, "name": "cluster_a", "nodes": [1]}]}`,
+				errMsg: `node 1 of "cluster_a" is not a node`,
+			},
+			"CyclicSubgraphs": {
+				in: `{"_subgraph_cnt": 3, "objects": [
+					{"_gvid": 0, "name": "cluster_a", "subgraphs": [1]},
+					{"_gvid": 1, "name": "cluster_b", "subgraphs": [2]},
+					{"_gvid": 2, "name": "cluster_c", "subgraphs": [1]}
+				]}`,
+				errMsg: `subgraph 1 of "cluster_b" is nested in itself`,
+			},
+			"SubgraphsNestedTooDeep": {
+				in:     nestedSubgraphs(dot.MaxDepth + 1),
+				errMsg: fmt.Sprintf("subgraphs are nested deeper than the max depth of %d", dot.MaxDepth),
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := gvjson.Read(strings.NewReader(test.in))
+
+				require.NotNilf(t, err, "Read(%q)", test.in)
+				assertx.Contains(t, err.Error(), test.errMsg)
+			})
+		}
+	})
+}
+
+// nestedSubgraphs returns Graphviz JSON of n subgraphs each nested in the previous one.
+func nestedSubgraphs(n int) string {
+	var objects []string
+	for i := range n {
+		objects = append(objects, fmt.Sprintf(`{"_gvid": %d, "name": "cluster_%d", "subgraphs": [%d]}`, i, i, i+1))
+	}
+	objects[n-1] = fmt.Sprintf(`{"_gvid": %d, "name": "cluster_%d"}`, n-1, n-1)
+	return fmt.Sprintf(`{"_subgraph_cnt": %d, "objects": [%s]}`, n, strings.Join(objects, ","))
+}
+
+func TestWrite(t *testing.T) {
+	in := `graph G {
+	bgcolor=white
+	subgraph cluster_a { A }
+	A -- B:s [label="say \"hi\""]
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	var got bytes.Buffer
+	err = gvjson.Write(&got, g)
+	require.NoErrorf(t, err, "Write(%q)", in)
+
+	want := `{
+  "name": "G",
+  "directed": false,
+  "strict": false,
+  "bgcolor": "white",
+  "_subgraph_cnt": 1,
+  "objects": [
+    {
+      "_gvid": 0,
+      "name": "cluster_a",
+      "nodes": [
+        1
+      ]
+    },
+    {
+      "_gvid": 1,
+      "name": "A"
+    },
+    {
+      "_gvid": 2,
+      "name": "B"
+    }
+  ],
+  "edges": [
+    {
+      "_gvid": 0,
+      "tail": 1,
+      "head": 2,
+      "headport": "s",
+      "label": "say \"hi\""
+    }
+  ]
+}
+`
+	assert.EqualValuesf(t, got.String(), want, "Write(%q)", in)
+
+	t.Run("ReadBack", func(t *testing.T) {
+		in := `strict digraph G {
+	rankdir=LR
+	node [shape=box]
+	subgraph cluster_a {
+		label=<<b>A</b>>
+		a:p:n -> b
+		subgraph { c }
+	}
+	a -> {d e} [color=red]
+}`
+		g, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+
+		var out bytes.Buffer
+		err = gvjson.Write(&out, g)
+		require.NoErrorf(t, err, "Write(%q)", in)
+		got, err := gvjson.Read(&out)
+		require.NoErrorf(t, err, "Read(%q)", out.String())
+
+		want := `strict digraph G {
+	rankdir=LR
+	subgraph cluster_a {label="<b>A</b>" subgraph {c} a b}
+	subgraph {d e}
+	a [shape=box]
+	b [shape=box]
+	c [shape=box]
+	d [shape=box]
+	e [shape=box]
+	a -> b [tailport="p:n"]
+	a -> d [color=red]
+	a -> e [color=red]
+}`
+		assert.EqualValuesf(t, got.String(), want, "Read(Write(%q))", in)
+	})
+}
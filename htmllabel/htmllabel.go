@@ -0,0 +1,345 @@
+// Package htmllabel validates HTML-like labels as defined by
+// https://graphviz.org/doc/info/shapes.html#html.
+//
+// HTML-like labels are HTML strings like <<b>bold</b>> given as value of a label attribute. The
+// scanner only makes sure the '<' and '>' of an HTML string are balanced. [Validate] checks that
+// the label only uses the tags and attributes Graphviz supports, that tags are closed and nested as
+// Graphviz expects.
+package htmllabel
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// Error is a problem in an HTML-like label.
+type Error struct {
+	Start  token.Position // Start is the position of the first rune of the offending markup.
+	End    token.Position // End is the position of the last rune of the offending markup.
+	Reason string         // Reason describes the problem.
+}
+
+func (e Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Start, e.Reason)
+}
+
+// attrs maps the lowercase names of the supported tags to their lowercase attribute names.
+var attrs = map[string][]string{
+	"table": {
+		"align", "bgcolor", "border", "cellborder", "cellpadding", "cellspacing", "color",
+		"columns", "fixedsize", "gradientangle", "height", "href", "id", "port", "rows", "sides",
+		"style", "target", "title", "tooltip", "valign", "width",
+	},
+	"tr": nil,
+	"td": {
+		"align", "balign", "bgcolor", "border", "cellpadding", "cellspacing", "color", "colspan",
+		"fixedsize", "gradientangle", "height", "href", "id", "port", "rowspan", "sides", "style",
+		"target", "title", "tooltip", "valign", "width",
+	},
+	"font": {"color", "face", "point-size"},
+	"br":   {"align"},
+	"img":  {"scale", "src"},
+	"i":    nil,
+	"b":    nil,
+	"u":    nil,
+	"o":    nil,
+	"sub":  nil,
+	"sup":  nil,
+	"s":    nil,
+	"hr":   nil,
+	"vr":   nil,
+}
+
+// isEmpty determines if the tag cannot have any content and must thus be written like <br/>.
+func isEmpty(tag string) bool {
+	switch tag {
+	case "br", "hr", "vr", "img":
+		return true
+	}
+	return false
+}
+
+// parents maps the lowercase names of tags that are only allowed in a specific tag to it.
+var parents = map[string]string{
+	"tr": "table",
+	"hr": "table",
+	"td": "tr",
+	"vr": "tr",
+}
+
+// Validate validates the HTML-like label. The positions of the errors are positions in the dot
+// source code the ID was parsed from. Nothing is reported for IDs that are not HTML strings.
+func Validate(id ast.ID) []Error {
+	if !id.IsHTML() || len(id.Literal) < 2 {
+		return nil
+	}
+
+	p := parser{}
+	pos := id.StartPos
+	for _, r := range id.Literal {
+		p.src = append(p.src, r)
+		p.pos = append(p.pos, pos)
+		if r == '\n' {
+			pos = token.Position{Row: pos.Row + 1, Column: 1}
+		} else {
+			pos.Column++
+		}
+	}
+	// skip the delimiting '<' and '>' of the HTML string
+	p.i = 1
+	p.src = p.src[:len(p.src)-1]
+	p.parse()
+	return p.errs
+}
+
+// element is an open tag.
+type element struct {
+	name       string // name is the name as written in the label.
+	tag        string // tag is the lowercase name.
+	start, end token.Position
+}
+
+type parser struct {
+	src   []rune
+	pos   []token.Position // pos holds the position of each rune in src.
+	i     int
+	stack []element
+	errs  []Error
+}
+
+func (p *parser) parse() {
+	for p.i < len(p.src) {
+		if p.src[p.i] != '<' {
+			p.parseText()
+			continue
+		}
+
+		var ok bool
+		if p.hasPrefix("<!--") {
+			ok = p.parseComment()
+		} else if p.hasPrefix("</") {
+			ok = p.parseClosingTag()
+		} else {
+			ok = p.parseOpeningTag()
+		}
+		if !ok {
+			break
+		}
+	}
+
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		el := p.stack[i]
+		p.error(el.start, el.end, fmt.Sprintf("missing closing tag </%s>", el.name))
+	}
+}
+
+func (p *parser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(p.src[p.i:min(p.i+len(prefix), len(p.src))]), prefix)
+}
+
+func (p *parser) error(start, end token.Position, reason string) {
+	p.errs = append(p.errs, Error{Start: start, End: end, Reason: reason})
+}
+
+// parent returns the lowercase name of the innermost open tag.
+func (p *parser) parent() string {
+	if len(p.stack) == 0 {
+		return ""
+	}
+	return p.stack[len(p.stack)-1].tag
+}
+
+func (p *parser) parseText() {
+	start := p.i
+	for p.i < len(p.src) && p.src[p.i] != '<' {
+		p.i++
+	}
+
+	text := string(p.src[start:p.i])
+	if tag := p.parent(); (tag == "table" || tag == "tr") && strings.TrimSpace(text) != "" {
+		first := start + strings.IndexFunc(text, func(r rune) bool { return !unicode.IsSpace(r) })
+		el := p.stack[len(p.stack)-1]
+		p.error(p.pos[first], p.pos[p.i-1], fmt.Sprintf("text is not allowed in <%s>", el.name))
+	}
+}
+
+func (p *parser) parseComment() bool {
+	start := p.i
+	for p.i += 4; p.i < len(p.src); p.i++ {
+		if p.hasPrefix("-->") {
+			p.i += 3
+			return true
+		}
+	}
+	p.error(p.pos[start], p.pos[len(p.src)-1], "missing closing marker '-->' of comment")
+	return false
+}
+
+func (p *parser) parseClosingTag() bool {
+	start := p.i
+	p.i += 2
+	name := p.parseName()
+	p.skipWhitespace()
+	if p.i >= len(p.src) || p.src[p.i] != '>' {
+		p.error(p.pos[start], p.pos[min(p.i, len(p.src)-1)], fmt.Sprintf("missing '>' of closing tag </%s", name))
+		return false
+	}
+	end := p.pos[p.i]
+	p.i++
+
+	tag := strings.ToLower(name)
+	idx := -1
+	for i := len(p.stack) - 1; i >= 0; i-- {
+		if p.stack[i].tag == tag {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		p.error(p.pos[start], end, fmt.Sprintf("closing tag </%s> has no opening tag", name))
+		return true
+	}
+	for i := len(p.stack) - 1; i > idx; i-- {
+		el := p.stack[i]
+		p.error(el.start, el.end, fmt.Sprintf("missing closing tag </%s>", el.name))
+	}
+	p.stack = p.stack[:idx]
+	return true
+}
+
+func (p *parser) parseOpeningTag() bool {
+	start := p.i
+	p.i++
+	name := p.parseName()
+	if name == "" {
+		p.error(p.pos[start], p.pos[start], "missing tag name after '<'")
+		return p.skipTag()
+	}
+	tag := strings.ToLower(name)
+	allowed, known := attrs[tag]
+
+	seen := make(map[string]bool)
+	var selfClosing bool
+	for {
+		p.skipWhitespace()
+		if p.i >= len(p.src) {
+			p.error(p.pos[start], p.pos[len(p.src)-1], fmt.Sprintf("missing '>' of tag <%s", name))
+			return false
+		}
+		if p.src[p.i] == '>' {
+			break
+		}
+		if p.hasPrefix("/>") {
+			selfClosing = true
+			p.i++
+			break
+		}
+
+		attrStart := p.i
+		attrName := p.parseName()
+		if attrName == "" {
+			p.error(p.pos[p.i], p.pos[p.i], fmt.Sprintf("unexpected %q in tag <%s>", p.src[p.i], name))
+		}
+		if attrName == "" || !p.parseAttrValue(name, attrName) {
+			// continue validating after the malformed tag
+			for p.i < len(p.src) && p.src[p.i] != '>' {
+				p.i++
+			}
+			if p.i >= len(p.src) {
+				return false
+			}
+			selfClosing = p.src[p.i-1] == '/'
+			break
+		}
+		attrEnd := p.pos[attrStart+len([]rune(attrName))-1]
+		attr := strings.ToLower(attrName)
+
+		if seen[attr] {
+			p.error(p.pos[attrStart], attrEnd, fmt.Sprintf("duplicate attribute %s in tag <%s>", attrName, name))
+		} else if known && !slices.Contains(allowed, attr) {
+			p.error(p.pos[attrStart], attrEnd, fmt.Sprintf("unknown attribute %s of tag <%s>", attrName, name))
+		}
+		seen[attr] = true
+	}
+	end := p.pos[p.i]
+	p.i++
+
+	if !known {
+		p.error(p.pos[start], end, fmt.Sprintf("unknown tag <%s>", name))
+	} else if want, ok := parents[tag]; ok && p.parent() != want {
+		p.error(p.pos[start], end, fmt.Sprintf("tag <%s> is only allowed in <%s>", name, want))
+	} else if parent := p.parent(); !ok && (parent == "table" || parent == "tr") {
+		el := p.stack[len(p.stack)-1]
+		p.error(p.pos[start], end, fmt.Sprintf("tag <%s> is not allowed in <%s>", name, el.name))
+	}
+
+	if selfClosing {
+		return true
+	}
+	if isEmpty(tag) {
+		p.error(p.pos[start], end, fmt.Sprintf("tag <%s> must be self-closing like <%s/>", name, name))
+		return true
+	}
+	p.stack = append(p.stack, element{name: name, tag: tag, start: p.pos[start], end: end})
+	return true
+}
+
+// parseAttrValue parses the '=' followed by the quoted value of an attribute.
+func (p *parser) parseAttrValue(tag, attr string) bool {
+	p.skipWhitespace()
+	if p.i >= len(p.src) || p.src[p.i] != '=' {
+		p.error(p.pos[p.i-1], p.pos[p.i-1], fmt.Sprintf("missing value of attribute %s in tag <%s>", attr, tag))
+		return false
+	}
+	p.i++
+	p.skipWhitespace()
+	if p.i >= len(p.src) || (p.src[p.i] != '"' && p.src[p.i] != '\'') {
+		pos := p.pos[min(p.i, len(p.src)-1)]
+		p.error(pos, pos, fmt.Sprintf("value of attribute %s in tag <%s> must be quoted", attr, tag))
+		return false
+	}
+
+	quote := p.src[p.i]
+	start := p.i
+	for p.i++; p.i < len(p.src) && p.src[p.i] != quote; p.i++ {
+	}
+	if p.i >= len(p.src) {
+		p.error(p.pos[start], p.pos[len(p.src)-1], fmt.Sprintf("missing closing quote of attribute %s in tag <%s>", attr, tag))
+		return false
+	}
+	p.i++
+	return true
+}
+
+// parseName parses a tag or attribute name.
+func (p *parser) parseName() string {
+	start := p.i
+	for p.i < len(p.src) && (isLetter(p.src[p.i]) || (p.i > start && p.src[p.i] == '-')) {
+		p.i++
+	}
+	return string(p.src[start:p.i])
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func (p *parser) skipWhitespace() {
+	for p.i < len(p.src) && unicode.IsSpace(p.src[p.i]) {
+		p.i++
+	}
+}
+
+// skipTag advances past the next '>' so validation can continue after a malformed tag.
+func (p *parser) skipTag() bool {
+	for p.i < len(p.src) && p.src[p.i] != '>' {
+		p.i++
+	}
+	p.i++
+	return p.i <= len(p.src)
+}
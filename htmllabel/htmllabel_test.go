@@ -0,0 +1,180 @@
+package htmllabel_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/htmllabel"
+	"github.com/teleivo/dot/printer"
+	"github.com/teleivo/dot/token"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []htmllabel.Error
+	}{
+		"NotHTML": {
+			in: `"<b>A"`,
+		},
+		"Table": {
+			in: `<<TABLE BORDER="0"><TR><TD PORT='a'>A<BR ALIGN="left"/>B</TD><VR/><TD><IMG SRC="a.png"/></TD></TR><HR/></TABLE>>`,
+		},
+		"TextWithFontsEntitiesAndComments": {
+			in: `<<font point-size="8"><b>x</b> &amp; <i>y</i></font><!-- <x> -->>`,
+		},
+		"UnknownTag": {
+			in: `<<p>x</p>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 2), End: pos(1, 4), Reason: "unknown tag <p>"},
+			},
+		},
+		"UnknownAndDuplicateAttributes": {
+			in: `<<table foo="1" border="1" border="2"></table>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 9), End: pos(1, 11), Reason: "unknown attribute foo of tag <table>"},
+				{Start: pos(1, 28), End: pos(1, 33), Reason: "duplicate attribute border in tag <table>"},
+			},
+		},
+		"TextInRow": {
+			in: "<<table>\n\t<tr>\n\t\tA<td>B</td>\n\t</tr>\n</table>>",
+			want: []htmllabel.Error{
+				{Start: pos(3, 3), End: pos(3, 3), Reason: "text is not allowed in <tr>"},
+			},
+		},
+		"TagInTable": {
+			in: `<<table><b>A</b></table>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 9), End: pos(1, 11), Reason: "tag <b> is not allowed in <table>"},
+			},
+		},
+		"CellsAndRowsOutsideOfTable": {
+			in: `<<td>A</td><tr></tr>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 2), End: pos(1, 5), Reason: "tag <td> is only allowed in <tr>"},
+				{Start: pos(1, 12), End: pos(1, 15), Reason: "tag <tr> is only allowed in <table>"},
+			},
+		},
+		"MissingClosingTag": {
+			in: `<<b><i>A</b>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 5), End: pos(1, 7), Reason: "missing closing tag </i>"},
+			},
+		},
+		"MissingClosingTagAtEnd": {
+			in: `<<b>A>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 2), End: pos(1, 4), Reason: "missing closing tag </b>"},
+			},
+		},
+		"MissingOpeningTag": {
+			in: `<A</b>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 3), End: pos(1, 6), Reason: "closing tag </b> has no opening tag"},
+			},
+		},
+		"EmptyTagNotSelfClosing": {
+			in: `<A<br>B>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 3), End: pos(1, 6), Reason: "tag <br> must be self-closing like <br/>"},
+			},
+		},
+		"UnquotedAttributeValue": {
+			in: `<<font color=red>A</font>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 14), End: pos(1, 14), Reason: "value of attribute color in tag <font> must be quoted"},
+			},
+		},
+		"MissingAttributeValue": {
+			in: `<<font color>A</font>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 12), End: pos(1, 12), Reason: "missing value of attribute color in tag <font>"},
+			},
+		},
+		"UnclosedAttributeValue": {
+			in: `<<font color="red>A</font>>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 14), End: pos(1, 26), Reason: "missing closing quote of attribute color in tag <font>"},
+			},
+		},
+		"UnclosedComment": {
+			in: `<A<!-- B>`,
+			want: []htmllabel.Error{
+				{Start: pos(1, 3), End: pos(1, 8), Reason: "missing closing marker '-->' of comment"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := htmllabel.Validate(ast.ID{Literal: test.in, StartPos: pos(1, 1)})
+
+			assert.EqualValuesf(t, got, test.want, "Validate(%q)", test.in)
+		})
+	}
+}
+
+func pos(row, column int) token.Position {
+	return token.Position{Row: row, Column: column}
+}
+
+// TestGraphvizExamples validates the HTML-like labels of the examples of the Graphviz
+// documentation in testdata. Their HTML strings must be printed as is.
+func TestGraphvizExamples(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.dot"))
+	require.NoErrorf(t, err, "Glob(%q)", "testdata")
+	require.Truef(t, len(files) > 0, "no examples in %q", "testdata")
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			in, err := os.ReadFile(file)
+			require.NoErrorf(t, err, "ReadFile(%q)", file)
+			g, err := dot.Parse(in)
+			require.NoErrorf(t, err, "Parse(%q)", file)
+
+			want := htmlIDs(g)
+			require.Truef(t, len(want) > 0, "no HTML strings in %q", file)
+			for _, id := range want {
+				errs := htmllabel.Validate(id)
+				assert.Truef(t, len(errs) == 0, "Validate(%q) = %v, want no errors", id.Literal, errs)
+			}
+
+			var out bytes.Buffer
+			err = printer.NewPrinter(bytes.NewReader(in), &out).Print()
+			require.NoErrorf(t, err, "Print(%q)", file)
+			printed, err := dot.Parse(out.Bytes())
+			require.NoErrorf(t, err, "Parse(%q)", out.String())
+
+			got := htmlIDs(printed)
+			require.EqualValuesf(t, len(got), len(want), "number of HTML strings in printed %q", file)
+			for i := range want {
+				assert.EqualValuesf(t, got[i].Literal, want[i].Literal, "printed HTML string of %q", file)
+			}
+		})
+	}
+}
+
+// htmlIDs returns the HTML strings used as node IDs and attribute values in the graph.
+func htmlIDs(g ast.Graph) []ast.ID {
+	var result []ast.ID
+	ast.Inspect(g, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case ast.NodeID:
+			if n.ID.IsHTML() {
+				result = append(result, n.ID)
+			}
+		case ast.Attribute:
+			if n.Value.IsHTML() {
+				result = append(result, n.Value)
+			}
+		}
+		return true
+	})
+	return result
+}
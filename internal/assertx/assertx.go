@@ -0,0 +1,15 @@
+// Package assertx provides test assertions missing from github.com/teleivo/assertive.
+package assertx
+
+import (
+	"strings"
+	"testing"
+)
+
+// Contains asserts that got contains want. The test continues if it does not.
+func Contains(t testing.TB, got, want string) {
+	t.Helper()
+	if !strings.Contains(got, want) {
+		t.Errorf("got %q which does not contain %q", got, want)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2022 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package diff computes line based differences between texts in the unified diff format.
+//
+// It is adapted from the package internal/diff of the Go distribution at
+// $GOROOT/src/internal/diff/diff.go which cannot be imported outside the standard library. Its
+// code is licensed under the BSD-style license in the LICENSE file of this directory.
+package diff
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// context is the number of unchanged lines shown before and after a change.
+const context = 3
+
+// pair is a pair of line indexes into the old and new text.
+type pair struct {
+	x, y int
+}
+
+// Diff returns the unified diff of the old text to the new text or nil if they are equal. The
+// texts are labeled using given names like
+//
+//	diff graph.dot.orig graph.dot
+//	--- graph.dot.orig
+//	+++ graph.dot
+//	@@ -1,3 +1,3 @@
+//
+// Lines are matched by anchoring the diff on lines that are unique in both texts like the diff of
+// gofmt -d. The resulting diff is not always minimal but it is computed in O(n log n) time even if
+// every line changed.
+func Diff(oldName string, old []byte, newName string, new []byte) []byte {
+	if bytes.Equal(old, new) {
+		return nil
+	}
+	x := lines(old)
+	y := lines(new)
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "diff %s %s\n", oldName, newName)
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	var (
+		done  pair     // done is the end of the lines that have been handled
+		hunk  pair     // hunk is the start of the current hunk
+		count pair     // count is the number of lines of each text in the current hunk
+		text  []string // text are the lines of the current hunk
+	)
+	for _, m := range anchors(x, y) {
+		if m.x < done.x { // the anchor is part of lines that have been handled
+			continue
+		}
+
+		// expand the anchor to the run of equal lines x[start.x:end.x] == y[start.y:end.y]
+		start := m
+		for start.x > done.x && start.y > done.y && x[start.x-1] == y[start.y-1] {
+			start.x--
+			start.y--
+		}
+		end := m
+		for end.x < len(x) && end.y < len(y) && x[end.x] == y[end.y] {
+			end.x++
+			end.y++
+		}
+
+		for _, s := range x[done.x:start.x] {
+			text = append(text, "-"+s)
+			count.x++
+		}
+		for _, s := range y[done.y:start.y] {
+			text = append(text, "+"+s)
+			count.y++
+		}
+
+		// too few equal lines to end the hunk so they are part of it
+		if (end.x < len(x) || end.y < len(y)) &&
+			(end.x-start.x < context || (len(text) > 0 && end.x-start.x < 2*context)) {
+			for _, s := range x[start.x:end.x] {
+				text = append(text, " "+s)
+				count.x++
+				count.y++
+			}
+			done = end
+			continue
+		}
+
+		if len(text) > 0 {
+			n := min(end.x-start.x, context)
+			for _, s := range x[start.x : start.x+n] {
+				text = append(text, " "+s)
+				count.x++
+				count.y++
+			}
+			done = pair{start.x + n, start.y + n}
+
+			// line numbers start at 1 while an empty range starts at 0
+			if count.x > 0 {
+				hunk.x++
+			}
+			if count.y > 0 {
+				hunk.y++
+			}
+			fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", hunk.x, count.x, hunk.y, count.y)
+			for _, s := range text {
+				out.WriteString(s)
+			}
+			count = pair{}
+			text = text[:0]
+		}
+
+		if end.x >= len(x) && end.y >= len(y) {
+			break
+		}
+
+		// start the next hunk with the equal lines preceding the next change
+		hunk = pair{end.x - context, end.y - context}
+		for _, s := range x[hunk.x:end.x] {
+			text = append(text, " "+s)
+			count.x++
+			count.y++
+		}
+		done = end
+	}
+
+	return out.Bytes()
+}
+
+// lines splits the text into lines keeping their line endings. A missing newline at the end of the
+// text is marked like diff does.
+func lines(text []byte) []string {
+	result := strings.SplitAfter(string(text), "\n")
+	if result[len(result)-1] == "" {
+		return result[:len(result)-1]
+	}
+	result[len(result)-1] += "\n\\ No newline at end of file\n"
+	return result
+}
+
+// anchors returns the pairs of indexes of the longest common subsequence of the lines that occur
+// exactly once in x and once in y. The sequence starts with the pair {0, 0} and ends with the pair
+// {len(x), len(y)}. The longest common subsequence is computed using Algorithm A of Thomas G.
+// Szymanski, "A Special Case of the Maximal Common Subsequence Problem", Princeton TR #170 (1975).
+func anchors(x, y []string) []pair {
+	// count the occurrences in x as 0, -1 or -2 for many and in y as 0, -4 or -8 for many so
+	// positive values can be used as indexes below
+	m := make(map[string]int)
+	for _, s := range x {
+		if c := m[s]; c > -2 {
+			m[s] = c - 1
+		}
+	}
+	for _, s := range y {
+		if c := m[s]; c > -8 {
+			m[s] = c - 4
+		}
+	}
+
+	// xi and yi are the increasing indexes of the unique lines in x and y while inv[i] is the index
+	// j such that x[xi[i]] == y[yi[j]]
+	var xi, yi, inv []int
+	for i, s := range y {
+		if m[s] == -1+-4 {
+			m[s] = len(yi)
+			yi = append(yi, i)
+		}
+	}
+	for i, s := range x {
+		if j, ok := m[s]; ok && j >= 0 {
+			xi = append(xi, i)
+			inv = append(inv, j)
+		}
+	}
+
+	// the longest common subsequence of inv and 0..n-1 is the longest increasing subsequence of inv
+	n := len(xi)
+	tails := make([]int, n)
+	lengths := make([]int, n)
+	for i := range tails {
+		tails[i] = n + 1
+	}
+	for i := 0; i < n; i++ {
+		k := sort.Search(n, func(k int) bool {
+			return tails[k] >= inv[i]
+		})
+		tails[k] = inv[i]
+		lengths[i] = k + 1
+	}
+	k := 0
+	for _, l := range lengths {
+		k = max(k, l)
+	}
+
+	seq := make([]pair, 2+k)
+	seq[0] = pair{0, 0}
+	seq[1+k] = pair{len(x), len(y)}
+	last := n
+	for i := n - 1; i >= 0; i-- {
+		if lengths[i] == k && inv[i] < last {
+			seq[k] = pair{xi[i], yi[inv[i]]}
+			last = inv[i]
+			k--
+		}
+	}
+	return seq
+}
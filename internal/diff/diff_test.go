@@ -0,0 +1,90 @@
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/dot/internal/diff"
+)
+
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		old  string
+		new  string
+		want string
+	}{
+		"Equal": {
+			old:  "graph {\n\ta\n}\n",
+			new:  "graph {\n\ta\n}\n",
+			want: "",
+		},
+		"ChangedLine": {
+			old: "graph {\n  a -- b\n}\n",
+			new: "graph {\n\ta -- b\n}\n",
+			want: `diff old.dot new.dot
+--- old.dot
++++ new.dot
+@@ -1,3 +1,3 @@
+ graph {
+-  a -- b
++	a -- b
+ }
+`,
+		},
+		"ChangesFarApartAreSeparateHunks": {
+			old: "graph {\n  a\n\tb\n\tc\n\td\n\te\n\tf\n\tg\n\th\n  i\n}\n",
+			new: "graph {\n\ta\n\tb\n\tc\n\td\n\te\n\tf\n\tg\n\th\n\ti\n}\n",
+			want: `diff old.dot new.dot
+--- old.dot
++++ new.dot
+@@ -1,5 +1,5 @@
+ graph {
+-  a
++	a
+ 	b
+ 	c
+ 	d
+@@ -7,5 +7,5 @@
+ 	f
+ 	g
+ 	h
+-  i
++	i
+ }
+`,
+		},
+		"MissingNewlineAtEndOfFile": {
+			old: "graph { a }",
+			new: "graph {\n\ta\n}\n",
+			want: `diff old.dot new.dot
+--- old.dot
++++ new.dot
+@@ -1,1 +1,3 @@
+-graph { a }
+\ No newline at end of file
++graph {
++	a
++}
+`,
+		},
+		"EmptyOld": {
+			old: "",
+			new: "graph {}\n",
+			want: `diff old.dot new.dot
+--- old.dot
++++ new.dot
+@@ -0,0 +1,1 @@
++graph {}
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := diff.Diff("old.dot", []byte(test.old), "new.dot", []byte(test.new))
+
+			if string(got) != test.want {
+				t.Errorf("\n\ngot:\n%s\n\n\nwant:\n%s\n", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Package snippet renders the line of dot source code a problem was found in with a caret pointing
+// at the offending character. It is shared by dot.FormatErrors and package diagnostic so errors
+// and diagnostics look the same.
+package snippet
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Lines returns a function returning the line of src with given number starting at 1. Lines are
+// returned without their line ending. An empty string is returned if there is no such line.
+func Lines(src []byte) func(nr int) string {
+	lines := bytes.Split(src, []byte("\n"))
+	return func(nr int) string {
+		if nr < 1 || nr > len(lines) {
+			return ""
+		}
+		return strings.TrimSuffix(string(lines[nr-1]), "\r")
+	}
+}
+
+// Write writes the line of source code followed by a caret pointing at given column. Both are
+// indented by a tab. Nothing is written if the line is empty.
+func Write(out *strings.Builder, line string, column int) {
+	if line == "" {
+		return
+	}
+	out.WriteRune('\t')
+	out.WriteString(line)
+	out.WriteString("\n\t")
+	// align the caret using the whitespace of the offending line so tabs line up
+	col := 1
+	for _, r := range line {
+		if col >= column {
+			break
+		}
+		if r == '\t' {
+			out.WriteRune('\t')
+		} else {
+			out.WriteRune(' ')
+		}
+		col++
+	}
+	out.WriteString("^\n")
+}
@@ -0,0 +1,46 @@
+package snippet_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/dot/internal/snippet"
+)
+
+func TestLines(t *testing.T) {
+	line := snippet.Lines([]byte("graph {\r\n\tA\n}"))
+
+	tests := map[int]string{
+		0: "",
+		1: "graph {",
+		2: "\tA",
+		3: "}",
+		4: "",
+	}
+	for nr, want := range tests {
+		assert.EqualValuesf(t, line(nr), want, "Lines()(%d)", nr)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		line   string
+		column int
+		want   string
+	}{
+		"Empty":       {line: "", column: 1, want: ""},
+		"FirstColumn": {line: "graph {", column: 1, want: "\tgraph {\n\t^\n"},
+		"Tabs":        {line: "\tA ! B", column: 4, want: "\t\tA ! B\n\t\t  ^\n"},
+		"PastTheEnd":  {line: "A", column: 5, want: "\tA\n\t ^\n"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out strings.Builder
+			snippet.Write(&out, test.line, test.column)
+
+			assert.EqualValuesf(t, out.String(), test.want, "Write(%q, %d)", test.line, test.column)
+		})
+	}
+}
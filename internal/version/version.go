@@ -0,0 +1,89 @@
+// Package version describes the build of a command like its module version and VCS revision so
+// users can report issues accurately and tools can check for a minimum version.
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Info describes the build of a command.
+type Info struct {
+	Module    string   // Module is the path of the main module.
+	Version   string   // Version is the version of the main module which is (devel) if built from a checkout.
+	Revision  string   // Revision is the optional VCS revision the command was built from.
+	Time      string   // Time is the optional time of the VCS revision in RFC3339 format.
+	Modified  bool     // Modified indicates that the command was built from a checkout with uncommitted changes.
+	GoVersion string   // GoVersion is the version of the Go toolchain that built the command.
+	Features  []string // Features lists the features of the command like the version of the canonical form.
+}
+
+// Read returns the build information embedded in the running binary along with given features.
+func Read(features ...string) Info {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Info{Version: "unknown", Features: features}
+	}
+	return New(bi, features...)
+}
+
+// New returns the build information bi along with given features.
+func New(bi *debug.BuildInfo, features ...string) Info {
+	info := Info{
+		Module:    bi.Main.Path,
+		Version:   bi.Main.Version,
+		GoVersion: bi.GoVersion,
+		Features:  features,
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// Describe returns the build information of the named command on multiple lines like
+//
+//	dotfmt v0.4.0
+//	module   github.com/teleivo/dot
+//	revision 2209ff7c (modified) at 2024-08-01T10:00:00Z
+//	go       go1.22.5
+//	features canonical=2
+//
+// Lines of missing information are left out.
+func (i Info) Describe(command string) string {
+	var out strings.Builder
+
+	out.WriteString(command)
+	out.WriteRune(' ')
+	out.WriteString(i.Version)
+	out.WriteRune('\n')
+	line := func(name, value string) {
+		if value == "" {
+			return
+		}
+		out.WriteString(name)
+		out.WriteString(strings.Repeat(" ", len("revision")-len(name)+1))
+		out.WriteString(value)
+		out.WriteRune('\n')
+	}
+	line("module", i.Module)
+	revision := i.Revision
+	if revision != "" && i.Modified {
+		revision += " (modified)"
+	}
+	if revision != "" && i.Time != "" {
+		revision += " at " + i.Time
+	}
+	line("revision", revision)
+	line("go", i.GoVersion)
+	line("features", strings.Join(i.Features, " "))
+
+	return out.String()
+}
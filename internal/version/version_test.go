@@ -0,0 +1,53 @@
+package version_test
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/dot/internal/version"
+)
+
+func TestInfo(t *testing.T) {
+	tests := map[string]struct {
+		bi   *debug.BuildInfo
+		want string
+	}{
+		"Release": {
+			bi: &debug.BuildInfo{
+				GoVersion: "go1.22.5",
+				Main:      debug.Module{Path: "github.com/teleivo/dot", Version: "v0.4.0"},
+			},
+			want: `dotfmt v0.4.0
+module   github.com/teleivo/dot
+go       go1.22.5
+features canonical=2
+`,
+		},
+		"Checkout": {
+			bi: &debug.BuildInfo{
+				GoVersion: "go1.22.5",
+				Main:      debug.Module{Path: "github.com/teleivo/dot", Version: "(devel)"},
+				Settings: []debug.BuildSetting{
+					{Key: "vcs.revision", Value: "2209ff7c"},
+					{Key: "vcs.time", Value: "2024-08-01T10:00:00Z"},
+					{Key: "vcs.modified", Value: "true"},
+				},
+			},
+			want: `dotfmt (devel)
+module   github.com/teleivo/dot
+revision 2209ff7c (modified) at 2024-08-01T10:00:00Z
+go       go1.22.5
+features canonical=2
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := version.New(test.bi, "canonical=2").Describe("dotfmt")
+
+			assert.EqualValuesf(t, got, test.want, "Describe(%q)", "dotfmt")
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package width_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/dot/internal/width"
+)
+
+func TestString(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want int
+	}{
+		"Empty":             {in: "", want: 0},
+		"ASCII":             {in: "a -> b", want: 6},
+		"Tab":               {in: "\ta", want: 2},
+		"Latin":             {in: "Zürich", want: 6},
+		"CombiningMark":     {in: "Cafe\u0301", want: 4},
+		"Han":               {in: "东京", want: 4},
+		"Hangul":            {in: "서울", want: 4},
+		"Fullwidth":         {in: "ＡＢ", want: 4},
+		"Emoji":             {in: "🚀🪐", want: 4},
+		"VariationSelector": {in: "☁\ufe0f", want: 1},
+		"ZeroWidthJoiner":   {in: "👩\u200d👩", want: 4},
+		"SkinTone":          {in: "👍\U0001f3fd", want: 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualValuesf(t, width.String(test.in), test.want, "String(%q)", test.in)
+		})
+	}
+}
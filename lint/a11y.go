@@ -0,0 +1,171 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// minContrastRatio is the minimum contrast ratio for text required by
+// https://www.w3.org/TR/WCAG21/#contrast-minimum.
+const minContrastRatio = 4.5
+
+var colorOnlyRule = Rule{
+	Name: "color-only",
+	Doc: "Edges that only differ by their color cannot be told apart by readers with color vision " +
+		"deficiencies or in grayscale prints. Also vary the style, arrowhead or label of the edges. " +
+		"https://www.w3.org/TR/WCAG21/#use-of-color",
+	Severity: Warning,
+	Check:    checkColorOnly,
+}
+
+var contrastRule = Rule{
+	Name: "contrast",
+	Doc: "Labels of filled nodes need enough contrast between their fontcolor and fillcolor to be " +
+		"readable. Only colors given in hex, HSV or by their X11 or SVG name are checked. " +
+		"https://www.w3.org/TR/WCAG21/#contrast-minimum",
+	Severity: Warning,
+	Check:    checkContrast,
+}
+
+var textRule = Rule{
+	Name: "text",
+	Doc: "Nodes without a label such as points are only identifiable by their position. Add an " +
+		"xlabel or a tooltip describing them. https://www.w3.org/TR/WCAG21/#non-text-content",
+	Severity: Info,
+	Check:    checkText,
+}
+
+func checkColorOnly(g ast.Graph) []Diagnostic {
+	type coloredEdge struct {
+		color     string
+		colorAttr *ast.Attribute
+	}
+	// group edges by all their attributes but the color
+	groups := make(map[string][]coloredEdge)
+	var keys []string
+	ast.Inspect(g, func(n ast.Node) bool {
+		es, ok := n.(*ast.EdgeStmt)
+		if !ok {
+			return true
+		}
+
+		var edge coloredEdge
+		var other []string
+		for _, attr := range attributes(es.AttrList) {
+			if attr.Name.Unquoted() == "color" {
+				edge.color = attr.Value.Unquoted()
+				edge.colorAttr = &attr
+				continue
+			}
+			other = append(other, attr.Name.Unquoted()+"="+attr.Value.Unquoted())
+		}
+		slices.Sort(other)
+		key := strings.Join(other, ",")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], edge)
+		return true
+	})
+
+	var result []Diagnostic
+	for _, key := range keys {
+		edges := groups[key]
+		var colors []string
+		for _, edge := range edges {
+			if !slices.Contains(colors, edge.color) {
+				colors = append(colors, edge.color)
+			}
+		}
+		if len(colors) < 2 {
+			continue
+		}
+		for _, edge := range edges {
+			if edge.colorAttr == nil {
+				continue
+			}
+			result = append(result, Diagnostic{
+				Start:   edge.colorAttr.Start(),
+				End:     edge.colorAttr.End(),
+				Message: fmt.Sprintf("edge differs from %d other edges only by its color %s", len(edges)-1, edge.colorAttr.Value),
+			})
+		}
+	}
+	return result
+}
+
+func checkContrast(g ast.Graph) []Diagnostic {
+	var result []Diagnostic
+	nodeStmts(g, func(ns *ast.NodeStmt, attrs []ast.Attribute) {
+		style, _ := lookup(attrs, "style")
+		filled := slices.ContainsFunc(strings.Split(style.Value.Unquoted(), ","), func(s string) bool {
+			return strings.TrimSpace(s) == "filled"
+		})
+		if !filled {
+			return
+		}
+
+		// Graphviz fills with the color if no fillcolor is set and with lightgrey if neither is
+		fill := "lightgrey"
+		if attr, ok := lookup(attrs, "color"); ok {
+			fill = attr.Value.Unquoted()
+		}
+		if attr, ok := lookup(attrs, "fillcolor"); ok {
+			fill = attr.Value.Unquoted()
+		}
+		font := "black"
+		if attr, ok := lookup(attrs, "fontcolor"); ok {
+			font = attr.Value.Unquoted()
+		}
+
+		fillColor, ok := parseColor(fill)
+		if !ok {
+			return
+		}
+		fontColor, ok := parseColor(font)
+		if !ok {
+			return
+		}
+		if ratio := contrastRatio(fillColor, fontColor); ratio < minContrastRatio {
+			result = append(result, Diagnostic{
+				Start: ns.Start(),
+				End:   ns.End(),
+				Message: fmt.Sprintf(
+					"contrast ratio of %.1f:1 between fontcolor %s and fillcolor %s is below %.1f:1",
+					ratio, font, fill, minContrastRatio,
+				),
+			})
+		}
+	})
+	return result
+}
+
+func checkText(g ast.Graph) []Diagnostic {
+	var result []Diagnostic
+	nodeStmts(g, func(ns *ast.NodeStmt, attrs []ast.Attribute) {
+		label, hasLabel := lookup(attrs, "label")
+		shape, _ := lookup(attrs, "shape")
+		hasText := !hasLabel || label.Value.Unquoted() != ""
+		if shape.Value.Unquoted() == "point" {
+			hasText = false
+		}
+		for _, name := range []string{"xlabel", "tooltip"} {
+			if attr, ok := lookup(attrs, name); ok && attr.Value.Unquoted() != "" {
+				hasText = true
+			}
+		}
+		if hasText {
+			return
+		}
+
+		result = append(result, Diagnostic{
+			Start:   ns.Start(),
+			End:     ns.End(),
+			Message: fmt.Sprintf("node %s has no text, add an xlabel or tooltip", ns.NodeID.ID),
+		})
+	})
+	return result
+}
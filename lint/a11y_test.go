@@ -0,0 +1,132 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestA11y(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"EdgesDistinguishedByStyle": {
+			in: `digraph {
+	A -> B [color=red,style=dashed]
+	B -> C [color=blue]
+}`,
+		},
+		"EdgesDifferingOnlyByColor": {
+			in: `digraph {
+	A -> B [color=red]
+	B -> C
+	C -> D [color="blue"]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 2, Column: 10},
+					End:      token.Position{Row: 2, Column: 18},
+					Severity: lint.Warning,
+					Code:     "color-only",
+					Message:  "edge differs from 2 other edges only by its color red",
+				},
+				{
+					Start:    token.Position{Row: 4, Column: 10},
+					End:      token.Position{Row: 4, Column: 21},
+					Severity: lint.Warning,
+					Code:     "color-only",
+					Message:  `edge differs from 2 other edges only by its color "blue"`,
+				},
+			},
+		},
+		"FilledNodesWithEnoughContrast": {
+			in: `digraph {
+	A [style=filled]
+	B [style="rounded,filled",fillcolor=black,fontcolor=white]
+	C [fillcolor=black]
+	D [style=filled,fillcolor=red3]
+}`,
+		},
+		"FilledNodesWithLowContrast": {
+			in: `digraph {
+	node [style=filled,fillcolor="#000080"]
+	A
+	subgraph {
+		node [fontcolor=white]
+		B
+	}
+	C [color=yellow,fillcolor="1.0,1.0,1.0",fontcolor=white]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 2},
+					End:      token.Position{Row: 3, Column: 2},
+					Severity: lint.Warning,
+					Code:     "contrast",
+					Message:  "contrast ratio of 1.3:1 between fontcolor black and fillcolor #000080 is below 4.5:1",
+				},
+				{
+					Start:    token.Position{Row: 8, Column: 2},
+					End:      token.Position{Row: 8, Column: 57},
+					Severity: lint.Warning,
+					Code:     "contrast",
+					Message:  "contrast ratio of 4.0:1 between fontcolor white and fillcolor 1.0,1.0,1.0 is below 4.5:1",
+				},
+			},
+		},
+		"FilledNodeWithSpacesInStyle": {
+			in: `digraph {
+	A [style="rounded, filled",fillcolor=navy]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 2, Column: 2},
+					End:      token.Position{Row: 2, Column: 43},
+					Severity: lint.Warning,
+					Code:     "contrast",
+					Message:  "contrast ratio of 1.3:1 between fontcolor black and fillcolor navy is below 4.5:1",
+				},
+			},
+		},
+		"NodesWithoutText": {
+			in: `digraph {
+	A [label=""]
+	B [shape=point,tooltip="start"]
+	C [shape=point]
+	D [label="",xlabel=D]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 2, Column: 2},
+					End:      token.Position{Row: 2, Column: 13},
+					Severity: lint.Info,
+					Code:     "text",
+					Message:  "node A has no text, add an xlabel or tooltip",
+				},
+				{
+					Start:    token.Position{Row: 4, Column: 2},
+					End:      token.Position{Row: 4, Column: 16},
+					Severity: lint.Info,
+					Code:     "text",
+					Message:  "node C has no text, add an xlabel or tooltip",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// attributes returns the attributes of all the attribute lists chained together.
+func attributes(attrList *ast.AttrList) []ast.Attribute {
+	var result []ast.Attribute
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			result = append(result, aList.Attribute)
+		}
+	}
+	return result
+}
+
+// graphAttrs returns the attributes of the root graph. These are attribute statements like
+// label=G and attributes of graph attribute statements like graph [label=G] that are not nested in
+// a subgraph.
+func graphAttrs(g ast.Graph) []ast.Attribute {
+	var result []ast.Attribute
+	for _, stmt := range g.Stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			result = append(result, st)
+		case *ast.AttrStmt:
+			if token.Lookup(st.ID.Literal) == token.Graph {
+				result = append(result, attributes(&st.AttrList)...)
+			}
+		}
+	}
+	return result
+}
+
+// edgeAttrs returns all attributes applying to edges. These are the attributes of edge statements
+// and of edge attribute statements like edge [color=red].
+func edgeAttrs(g ast.Graph) []ast.Attribute {
+	var result []ast.Attribute
+	ast.Inspect(g, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.EdgeStmt:
+			result = append(result, attributes(n.AttrList)...)
+		case *ast.AttrStmt:
+			if token.Lookup(n.ID.Literal) == token.Edge {
+				result = append(result, attributes(&n.AttrList)...)
+			}
+			return false
+		case *ast.NodeStmt:
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// clusters returns the subgraphs that are clusters by their name. Clusters are subgraphs with an ID
+// starting with cluster.
+func clusters(g ast.Graph) map[string]ast.Subgraph {
+	result := make(map[string]ast.Subgraph)
+	ast.Inspect(g, func(n ast.Node) bool {
+		if subgraph, ok := n.(ast.Subgraph); ok && subgraph.ID != nil {
+			if name := subgraph.ID.Unquoted(); strings.HasPrefix(name, "cluster") {
+				result[name] = subgraph
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// isTrue determines if the value of a boolean attribute is true as interpreted by Graphviz. True
+// and yes in any case as well as non-zero integers are true.
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "true", "yes":
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n != 0
+}
+
+// nodeStmts calls f for every node statement with the attributes in effect for it. These are the
+// ones of node attribute statements like node [shape=box] preceding it in its graph or any of its
+// enclosing subgraphs followed by its own attributes.
+func nodeStmts(g ast.Graph, f func(ns *ast.NodeStmt, attrs []ast.Attribute)) {
+	var walk func(stmts []ast.Stmt, defaults []ast.Attribute)
+	walkOperand := func(operand ast.EdgeOperand, defaults []ast.Attribute) {
+		if subgraph, ok := operand.(ast.Subgraph); ok {
+			walk(subgraph.Stmts, defaults)
+		}
+	}
+	walk = func(stmts []ast.Stmt, defaults []ast.Attribute) {
+		// clip the capacity so appending in a subgraph does not leak into its parent
+		defaults = defaults[:len(defaults):len(defaults)]
+		for _, stmt := range stmts {
+			switch st := stmt.(type) {
+			case *ast.NodeStmt:
+				f(st, append(defaults[:len(defaults):len(defaults)], attributes(st.AttrList)...))
+			case *ast.AttrStmt:
+				if token.Lookup(st.ID.Literal) == token.Node {
+					defaults = append(defaults, attributes(&st.AttrList)...)
+				}
+			case *ast.EdgeStmt:
+				walkOperand(st.Left, defaults)
+				for cur := &st.Right; cur != nil; cur = cur.Next {
+					walkOperand(cur.Right, defaults)
+				}
+			case ast.Subgraph:
+				walk(st.Stmts, defaults)
+			}
+		}
+	}
+	walk(g.Stmts, nil)
+}
+
+// lookup returns the last attribute with given name.
+func lookup(attrs []ast.Attribute, name string) (ast.Attribute, bool) {
+	var result ast.Attribute
+	var found bool
+	for _, attr := range attrs {
+		if attr.Name.Unquoted() == name {
+			result, found = attr, true
+		}
+	}
+	return result, found
+}
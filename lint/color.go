@@ -0,0 +1,106 @@
+package lint
+
+import (
+	"errors"
+	"math"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/color"
+)
+
+var invalidColorRule = Rule{
+	Name: "invalid-color",
+	Doc: "Graphviz warns about colors it does not know and falls back to black. Colors are given " +
+		"in hex like #ff8000, as HSV triple like 0.1,1,1 or by their X11 or SVG name. Attributes " +
+		"like fillcolor also accept lists of weighted colors like red;0.3:blue. Graphs setting a " +
+		"colorscheme are not checked. https://graphviz.org/docs/attr-types/color/ " +
+		"https://graphviz.org/docs/attr-types/colorList/",
+	Severity: Warning,
+	Check:    checkInvalidColor,
+}
+
+// colorAttrs maps the names of attributes holding a color to whether they accept a color list.
+var colorAttrs = map[string]bool{
+	"bgcolor":        true,
+	"color":          true,
+	"fillcolor":      true,
+	"fontcolor":      false,
+	"labelfontcolor": false,
+	"pencolor":       false,
+}
+
+func checkInvalidColor(g ast.Graph) []Diagnostic {
+	var attrs []ast.Attribute
+	var hasScheme bool
+	ast.Inspect(g, func(n ast.Node) bool {
+		if attr, ok := n.(ast.Attribute); ok {
+			attrs = append(attrs, attr)
+			hasScheme = hasScheme || attr.Name.Unquoted() == "colorscheme"
+			return false
+		}
+		return true
+	})
+	// names are looked up in the colorscheme which might not be supported by package color
+	if hasScheme {
+		return nil
+	}
+
+	var result []Diagnostic
+	for _, attr := range attrs {
+		isList, ok := colorAttrs[attr.Name.Unquoted()]
+		if !ok {
+			continue
+		}
+		var err error
+		if isList {
+			_, err = color.ParseList(attr.Value.Unquoted())
+		} else {
+			_, err = color.Parse(attr.Value.Unquoted())
+		}
+		if err == nil || errors.Is(err, color.ErrUnsupported) {
+			continue
+		}
+		result = append(result, Diagnostic{
+			Start:   attr.Value.Start(),
+			End:     attr.Value.End(),
+			Message: attr.Name.Unquoted() + ": " + err.Error(),
+		})
+	}
+	return result
+}
+
+// rgb is a color with red, green and blue components in the range [0, 1].
+type rgb struct {
+	r, g, b float64
+}
+
+// parseColor parses a color as described by [color.Parse]. The alpha channel is ignored.
+func parseColor(value string) (rgb, bool) {
+	c, err := color.Parse(value)
+	if err != nil {
+		return rgb{}, false
+	}
+	return rgb{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255}, true
+}
+
+// luminance returns the relative luminance as defined by
+// https://www.w3.org/TR/WCAG21/#dfn-relative-luminance.
+func (c rgb) luminance() float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.r) + 0.7152*linear(c.g) + 0.0722*linear(c.b)
+}
+
+// contrastRatio returns the contrast ratio between the colors as defined by
+// https://www.w3.org/TR/WCAG21/#dfn-contrast-ratio. It ranges from 1 to 21.
+func contrastRatio(a, b rgb) float64 {
+	la, lb := a.luminance(), b.luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
@@ -0,0 +1,80 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestInvalidColor(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"ValidColors": {
+			in: `digraph {
+	bgcolor="#ffffff80"
+	node [fillcolor="red;0.3:/svg/green" fontcolor=gray50]
+	A [color="0.5 1 1" pencolor=red3]
+	A -> B [color="/blues9/3"]
+}`,
+		},
+		"TransparentColors": {
+			in: `digraph {
+	bgcolor=transparent
+	A [fillcolor=transparent color=none]
+	A -> B [color="red:invis"]
+}`,
+		},
+		"InvalidColors": {
+			in: `digraph {
+	A [fillcolor=blu]
+	A -> B [color="red;0.6:blue;0.6" fontcolor="red:blue"]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 2, Column: 15},
+					End:      token.Position{Row: 2, Column: 17},
+					Severity: lint.Warning,
+					Code:     "invalid-color",
+					Message:  `fillcolor: invalid color list "blu": invalid color "blu": unknown color name`,
+				},
+				{
+					Start:    token.Position{Row: 3, Column: 16},
+					End:      token.Position{Row: 3, Column: 33},
+					Severity: lint.Warning,
+					Code:     "invalid-color",
+					Message:  `color: invalid color list "red;0.6:blue;0.6": weights add up to 1.2 which is more than 1`,
+				},
+				{
+					Start:    token.Position{Row: 3, Column: 45},
+					End:      token.Position{Row: 3, Column: 54},
+					Severity: lint.Warning,
+					Code:     "invalid-color",
+					Message:  `fontcolor: invalid color "red:blue": unknown color name`,
+				},
+			},
+		},
+		"ColorschemeIsNotChecked": {
+			in: `digraph {
+	node [colorscheme=blues9]
+	A [fillcolor=3]
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
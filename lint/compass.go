@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+var compassRule = Rule{
+	Name: "compass",
+	Doc: "Compass points attach edges to a side of a node. The rankdir of the graph rotates the layout " +
+		"but not the compass points, so :n and :s in a graph with rankdir=LR attach edges " +
+		"perpendicular to the flow of the graph. This is often a leftover of changing the rankdir. " +
+		"Graphs with the default rankdir=TB are not checked as :e and :w are commonly used in them. " +
+		"https://graphviz.org/docs/attrs/rankdir/ https://graphviz.org/docs/attr-types/portPos/",
+	Severity: Info,
+	Check:    checkCompass,
+}
+
+// perpendicularCompassPoints maps each rankdir other than the default TB to the compass points
+// perpendicular to its flow and the compass point they most likely should be. The suggestion
+// assumes the compass points were chosen for a perpendicular flow like the one of the default
+// rankdir=TB for LR and RL. It maps the start and end of that flow to the start and end of the flow
+// of the rankdir.
+var perpendicularCompassPoints = map[string]map[ast.CompassPointType]ast.CompassPointType{
+	"BT": {
+		ast.CompassPointWest: ast.CompassPointSouth,
+		ast.CompassPointEast: ast.CompassPointNorth,
+	},
+	"LR": {
+		ast.CompassPointNorth: ast.CompassPointWest,
+		ast.CompassPointSouth: ast.CompassPointEast,
+	},
+	"RL": {
+		ast.CompassPointNorth: ast.CompassPointEast,
+		ast.CompassPointSouth: ast.CompassPointWest,
+	},
+}
+
+func checkCompass(g ast.Graph) []Diagnostic {
+	var result []Diagnostic
+
+	rankdir := "TB"
+	for _, attr := range graphAttrs(g) {
+		if attr.Name.Unquoted() == "rankdir" {
+			rankdir = strings.ToUpper(attr.Value.Unquoted())
+		}
+	}
+	perpendicular, ok := perpendicularCompassPoints[rankdir]
+	if !ok {
+		return nil
+	}
+
+	check := func(operand ast.EdgeOperand) {
+		nid, ok := operand.(ast.NodeID)
+		if !ok || nid.Port == nil || nid.Port.CompassPoint == nil {
+			return
+		}
+		cp := nid.Port.CompassPoint
+		suggested, ok := perpendicular[cp.Type]
+		if !ok {
+			return
+		}
+
+		result = append(result, Diagnostic{
+			Start: cp.StartPos,
+			End:   cp.EndPos,
+			Message: fmt.Sprintf(
+				"compass point %s is perpendicular to the flow of rankdir=%s, did you mean %s?",
+				cp.Type, rankdir, suggested,
+			),
+			Fixes: []Fix{
+				{
+					Message: fmt.Sprintf("Replace compass point %s with %s", cp.Type, suggested),
+					Edits: []Edit{
+						{
+							Start:   cp.StartPos,
+							End:     token.Position{Row: cp.EndPos.Row, Column: cp.EndPos.Column + 1},
+							NewText: suggested.String(),
+						},
+					},
+				},
+			},
+		})
+	}
+
+	ast.Inspect(g, func(n ast.Node) bool {
+		if es, ok := n.(*ast.EdgeStmt); ok {
+			check(es.Left)
+			for cur := &es.Right; cur != nil; cur = cur.Next {
+				check(cur.Right)
+			}
+		}
+		return true
+	})
+
+	return result
+}
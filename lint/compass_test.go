@@ -0,0 +1,107 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestCompass(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"CompassPointsAlongDefaultRankdir": {
+			in: `digraph {
+	A:s -> B:n
+	C:p1:s -> D:_
+}`,
+		},
+		"CompassPointsAlongRankdirLR": {
+			in: `digraph {
+	rankdir=LR
+	A:e -> B:w
+	C:ne -> D:c
+}`,
+		},
+		"SidesWithDefaultRankdir": {
+			in: `digraph {
+	A:e -> B:w
+}`,
+		},
+		"CompassPointsAcrossRankdirBT": {
+			in: `digraph {
+	rankdir=BT
+	A:e -> B
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 4},
+					End:      token.Position{Row: 3, Column: 4},
+					Severity: lint.Info,
+					Code:     "compass",
+					Message:  "compass point e is perpendicular to the flow of rankdir=BT, did you mean n?",
+					Fixes: []lint.Fix{
+						{
+							Message: "Replace compass point e with n",
+							Edits: []lint.Edit{
+								{
+									Start:   token.Position{Row: 3, Column: 4},
+									End:     token.Position{Row: 3, Column: 5},
+									NewText: "n",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"CompassPointsAcrossRankdirLR": {
+			in: `digraph {
+	graph [rankdir="LR"]
+	{ A -> B:n }
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 11},
+					End:      token.Position{Row: 3, Column: 11},
+					Severity: lint.Info,
+					Code:     "compass",
+					Message:  "compass point n is perpendicular to the flow of rankdir=LR, did you mean w?",
+					Fixes: []lint.Fix{
+						{
+							Message: "Replace compass point n with w",
+							Edits: []lint.Edit{
+								{
+									Start:   token.Position{Row: 3, Column: 11},
+									End:     token.Position{Row: 3, Column: 12},
+									NewText: "w",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"NodeStmtsAreIgnored": {
+			in: `digraph {
+	A:e
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+var compoundRule = Rule{
+	Name: "compound",
+	Doc: "The edge attributes lhead and ltail clip an edge at the boundary of a cluster. They need to " +
+		"name an existing cluster and require compound=true on the graph. Graphviz ignores them " +
+		"otherwise. https://graphviz.org/docs/attrs/lhead/",
+	Severity: Warning,
+	Check:    checkCompound,
+}
+
+func checkCompound(g ast.Graph) []Diagnostic {
+	var result []Diagnostic
+
+	var isCompound bool
+	for _, attr := range graphAttrs(g) {
+		if attr.Name.Unquoted() == "compound" {
+			isCompound = isTrue(attr.Value.Unquoted())
+		}
+	}
+
+	clusters := clusters(g)
+	var fixed bool
+	for _, attr := range edgeAttrs(g) {
+		name := attr.Name.Unquoted()
+		if name != "lhead" && name != "ltail" {
+			continue
+		}
+
+		if _, ok := clusters[attr.Value.Unquoted()]; !ok {
+			result = append(result, Diagnostic{
+				Start:   attr.Value.Start(),
+				End:     attr.Value.End(),
+				Message: fmt.Sprintf("%s refers to unknown cluster %q", name, attr.Value.Unquoted()),
+			})
+		}
+		if !isCompound {
+			d := Diagnostic{
+				Start:   attr.Start(),
+				End:     attr.End(),
+				Message: fmt.Sprintf("%s requires compound=true on the graph", name),
+			}
+			// only offer the fix once so applying all fixes adds compound=true once
+			if !fixed {
+				d.Fixes = []Fix{
+					{
+						Message: "Add compound=true to the graph",
+						Edits: []Edit{
+							{
+								Start:   token.Position{Row: g.LeftBrace.Row, Column: g.LeftBrace.Column + 1},
+								End:     token.Position{Row: g.LeftBrace.Row, Column: g.LeftBrace.Column + 1},
+								NewText: "\n\tcompound=true",
+							},
+						},
+					},
+				}
+				fixed = true
+			}
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
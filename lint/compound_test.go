@@ -0,0 +1,146 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestCompound(t *testing.T) {
+	addCompound := []lint.Fix{
+		{
+			Message: "Add compound=true to the graph",
+			Edits: []lint.Edit{
+				{
+					Start:   token.Position{Row: 1, Column: 10},
+					End:     token.Position{Row: 1, Column: 10},
+					NewText: "\n\tcompound=true",
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"WithoutClusterEdges": {
+			in: `digraph {
+	A -> B [color=red]
+}`,
+		},
+		"CompoundGraphWithExistingClusters": {
+			in: `digraph {
+	compound=true
+	subgraph cluster_a { A }
+	subgraph "cluster b" { B }
+	A -> B [lhead="cluster b", ltail=cluster_a]
+}`,
+		},
+		"CompoundSetViaGraphAttrStmt": {
+			in: `digraph {
+	graph [compound=yes]
+	subgraph cluster_a { A }
+	edge [lhead=cluster_a]
+	B -> A
+}`,
+		},
+		"MissingCompound": {
+			in: `digraph {
+	subgraph cluster_a { A }
+	B -> A [lhead=cluster_a]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 10},
+					End:      token.Position{Row: 3, Column: 24},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  "lhead requires compound=true on the graph",
+					Fixes:    addCompound,
+				},
+			},
+		},
+		"MissingCompoundOnMultipleEdges": {
+			in: `digraph {
+	subgraph cluster_a { A }
+	B -> A [lhead=cluster_a]
+	C -> A [lhead=cluster_a]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 10},
+					End:      token.Position{Row: 3, Column: 24},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  "lhead requires compound=true on the graph",
+					Fixes:    addCompound,
+				},
+				{
+					Start:    token.Position{Row: 4, Column: 10},
+					End:      token.Position{Row: 4, Column: 24},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  "lhead requires compound=true on the graph",
+				},
+			},
+		},
+		"CompoundFalse": {
+			in: `digraph {
+	compound=false
+	subgraph cluster_a { A }
+	edge [ltail=cluster_a]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 4, Column: 8},
+					End:      token.Position{Row: 4, Column: 22},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  "ltail requires compound=true on the graph",
+					Fixes:    addCompound,
+				},
+			},
+		},
+		"UnknownCluster": {
+			in: `digraph {
+	compound=1
+	subgraph cluster_a { A }
+	subgraph b { B }
+	A -> B [lhead=b]
+	{ C -> D [ltail="cluster_c"] }
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 5, Column: 16},
+					End:      token.Position{Row: 5, Column: 16},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  `lhead refers to unknown cluster "b"`,
+				},
+				{
+					Start:    token.Position{Row: 6, Column: 18},
+					End:      token.Position{Row: 6, Column: 28},
+					Severity: lint.Warning,
+					Code:     "compound",
+					Message:  `ltail refers to unknown cluster "cluster_c"`,
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/teleivo/dot/ast"
+)
+
+var generatedEditRule = Rule{
+	Name: "generated-edit",
+	Doc: "Graphs generated by a program are marked by a header comment like // Code generated by " +
+		"dotgen. DO NOT EDIT. Edits by hand are lost once the graph is generated again. Change the " +
+		"program or its source instead. Graphs printed with a provenance header record the checksum " +
+		"of the graph in the header which no longer matches once the graph is edited. Formatting " +
+		"the graph keeps the checksum. https://go.dev/s/generatedcode",
+	Severity: Warning,
+	Check:    checkGeneratedEdit,
+}
+
+// checksumRegexp matches the checksum recorded in the provenance header printed by the printer.
+var checksumRegexp = regexp.MustCompile(` with checksum ([0-9a-f]+)\. DO NOT EDIT\.$`)
+
+func checkGeneratedEdit(g ast.Graph) []Diagnostic {
+	if !ast.IsGenerated(g) {
+		return nil
+	}
+
+	var result []Diagnostic
+	for _, c := range g.Comments {
+		if !c.StartPos.Before(g.Start()) {
+			break
+		}
+		match := checksumRegexp.FindStringSubmatch(c.Text)
+		if !ast.IsGeneratedComment(c) || match == nil {
+			continue
+		}
+		if checksum := ast.Checksum(g); match[1] != checksum {
+			result = append(result, Diagnostic{
+				Start: c.StartPos,
+				End:   c.EndPos,
+				Message: fmt.Sprintf(
+					"generated graph was edited by hand, its checksum %s does not match the checksum %s of the header",
+					checksum, match[1],
+				),
+			})
+		}
+	}
+	return result
+}
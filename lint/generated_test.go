@@ -0,0 +1,68 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/printer"
+	"github.com/teleivo/dot/token"
+)
+
+func TestGeneratedEdit(t *testing.T) {
+	src := `digraph {
+	A -> B [color=red]
+}`
+	var generated strings.Builder
+	err := printer.NewPrinter(strings.NewReader(src), &generated, printer.WithProvenance(printer.Provenance{
+		Tool: "dotgen",
+	})).Print()
+	require.NoErrorf(t, err, "Print(%q)", src)
+
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"NotGenerated": {
+			in: src,
+		},
+		"GeneratedWithoutChecksum": {
+			in: `// Code generated by dotgen. DO NOT EDIT.
+digraph {
+	A -> B
+}`,
+		},
+		"Generated": {
+			in: generated.String(),
+		},
+		"GeneratedAndFormatted": {
+			in: strings.NewReplacer("\n\t", " ", "\n}", " }").Replace(generated.String()),
+		},
+		"GeneratedAndEdited": {
+			in: strings.Replace(generated.String(), "red", "blue", 1),
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 1, Column: 1},
+					End:      token.Position{Row: 1, Column: 72},
+					Severity: lint.Warning,
+					Code:     "generated-edit",
+					Message:  "generated graph was edited by hand, its checksum 3521cfd41832f2d4 does not match the checksum 328a8f72f0e72c5c of the header",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g, lint.WithSeverity("text", lint.Off))
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
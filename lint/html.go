@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/htmllabel"
+)
+
+var invalidHTMLLabelRule = Rule{
+	Name: "invalid-html-label",
+	Doc: "Graphviz fails to render a graph if an HTML-like label uses unknown tags or attributes, " +
+		"misses closing tags or nests tags like <TD> outside of a <TR>. Empty tags like <BR/> " +
+		"must be self-closing. https://graphviz.org/doc/info/shapes.html#html",
+	Severity: Error,
+	Check:    checkInvalidHTMLLabel,
+}
+
+// labelAttrs are the names of attributes accepting HTML-like labels.
+var labelAttrs = map[string]bool{
+	"label":     true,
+	"xlabel":    true,
+	"headlabel": true,
+	"taillabel": true,
+}
+
+func checkInvalidHTMLLabel(g ast.Graph) []Diagnostic {
+	var result []Diagnostic
+	ast.Inspect(g, func(n ast.Node) bool {
+		attr, ok := n.(ast.Attribute)
+		if !ok {
+			return true
+		}
+		if labelAttrs[attr.Name.Unquoted()] {
+			for _, err := range htmllabel.Validate(attr.Value) {
+				result = append(result, Diagnostic{
+					Start:   err.Start,
+					End:     err.End,
+					Message: attr.Name.Unquoted() + ": " + err.Reason,
+				})
+			}
+		}
+		return false
+	})
+	return result
+}
@@ -0,0 +1,60 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestInvalidHTMLLabel(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"ValidLabels": {
+			in: `digraph {
+	A [label=<<table><tr><td port="p">A</td></tr></table>>]
+	A -> B [headlabel=<<b>head</b>> tooltip=<<p>>]
+}`,
+		},
+		"InvalidLabels": {
+			in: `digraph {
+	A [label=<<table>
+		<td>A</td>
+	</table>>]
+	A -> B [xlabel=<x<br>y>]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 3, Column: 3},
+					End:      token.Position{Row: 3, Column: 6},
+					Severity: lint.Error,
+					Code:     "invalid-html-label",
+					Message:  "label: tag <td> is only allowed in <tr>",
+				},
+				{
+					Start:    token.Position{Row: 5, Column: 19},
+					End:      token.Position{Row: 5, Column: 22},
+					Severity: lint.Error,
+					Code:     "invalid-html-label",
+					Message:  "xlabel: tag <br> must be self-closing like <br/>",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
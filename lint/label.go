@@ -0,0 +1,82 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/geometry"
+)
+
+var shortEdgeLabelRule = Rule{
+	Name: "short-edge-label",
+	Doc: "Labels on edges that are shorter than the label itself overlap the nodes they connect. " +
+		"This is only checked for graphs with positions computed by a Graphviz layout in the pos " +
+		"attribute of their nodes like the output of dot -Tdot. Consider using a shorter label, an " +
+		"xlabel or increasing the distance between the nodes. https://graphviz.org/docs/attrs/pos/",
+	Severity: Info,
+	Check:    checkShortEdgeLabel,
+}
+
+func checkShortEdgeLabel(g ast.Graph) []Diagnostic {
+	positions := make(map[string]geometry.Point)
+	nodeStmts(g, func(ns *ast.NodeStmt, attrs []ast.Attribute) {
+		if pos, ok := lookup(attrs, "pos"); ok {
+			if p, err := geometry.ParsePoint(pos.Value.Unquoted()); err == nil {
+				positions[ns.NodeID.ID.Unquoted()] = p
+			}
+		}
+	})
+	if len(positions) == 0 {
+		return nil
+	}
+
+	var result []Diagnostic
+	ast.Inspect(g, func(n ast.Node) bool {
+		es, ok := n.(*ast.EdgeStmt)
+		if !ok {
+			return true
+		}
+		attrs := attributes(es.AttrList)
+		label, ok := lookup(attrs, "label")
+		if !ok {
+			return true
+		}
+		fontSize := geometry.DefaultFontSize
+		if attr, ok := lookup(attrs, "fontsize"); ok {
+			if f, err := strconv.ParseFloat(attr.Value.Unquoted(), 64); err == nil && f > 0 {
+				fontSize = f
+			}
+		}
+		width := geometry.TextSize(label.Value.Unquoted(), fontSize).Width * geometry.PointsPerInch
+
+		tail, ok := es.Left.(ast.NodeID)
+		if !ok {
+			return true
+		}
+		for cur := &es.Right; cur != nil; cur = cur.Next {
+			head, ok := cur.Right.(ast.NodeID)
+			if !ok {
+				tail = ast.NodeID{}
+				continue
+			}
+			from, hasFrom := positions[tail.ID.Unquoted()]
+			to, hasTo := positions[head.ID.Unquoted()]
+			if hasFrom && hasTo {
+				if length := geometry.Distance(from, to); length < width {
+					result = append(result, Diagnostic{
+						Start: label.Start(),
+						End:   label.End(),
+						Message: fmt.Sprintf(
+							"label of edge %s %s %s is %.0fpt wide but the edge is only %.0fpt long",
+							tail.ID, cur.Op(), head.ID, width, length,
+						),
+					})
+				}
+			}
+			tail = head
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,115 @@
+// Package lint reports problems in dot graphs that are syntactically valid but likely not what the
+// author intended. Graphviz silently ignores many of these.
+package lint
+
+import (
+	"slices"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/diagnostic"
+)
+
+// Severity is the severity of a [Diagnostic].
+type Severity = diagnostic.Severity
+
+const (
+	Error   = diagnostic.Error   // Error is a problem that leads to a wrong rendering.
+	Warning = diagnostic.Warning // Warning is a problem that likely leads to an unintended rendering.
+	Info    = diagnostic.Info    // Info is a hint on how to improve the graph.
+	Off     = diagnostic.Off     // Off turns a rule off when passed to [WithSeverity].
+)
+
+// ParseSeverity parses a severity given as error, warning, warn, info or off in any case.
+func ParseSeverity(in string) (Severity, error) {
+	return diagnostic.ParseSeverity(in)
+}
+
+// Diagnostic is a problem found in a graph. Its code is the name of the rule that reported it.
+type Diagnostic = diagnostic.Diagnostic
+
+// Fix is a suggested change resolving a [Diagnostic].
+type Fix = diagnostic.Fix
+
+// Edit replaces the source code between Start and End with NewText.
+type Edit = diagnostic.Edit
+
+// Rule checks a graph for a specific problem.
+type Rule struct {
+	Name     string                       // Name uniquely identifies the rule.
+	Doc      string                       // Doc describes the problem the rule reports.
+	Severity Severity                     // Severity of the diagnostics reported by the rule.
+	Check    func(ast.Graph) []Diagnostic // Check reports the problems found in the graph.
+}
+
+// rules lists all rules run by [Lint].
+var rules = []Rule{
+	compoundRule,
+	compassRule,
+	invalidColorRule,
+	invalidHTMLLabelRule,
+	colorOnlyRule,
+	contrastRule,
+	textRule,
+	shortEdgeLabelRule,
+	generatedEditRule,
+}
+
+// Rules returns all rules run by [Lint].
+func Rules() []Rule {
+	return slices.Clone(rules)
+}
+
+// Option configures how [Lint] runs the rules.
+type Option func(*config)
+
+type config struct {
+	severities map[string]Severity
+}
+
+// WithSeverity overrides the severity of the rule with given name. The rule is not run if the
+// severity is [Off].
+func WithSeverity(rule string, severity Severity) Option {
+	return func(c *config) {
+		c.severities[rule] = severity
+	}
+}
+
+// Lint runs all rules on the graph. The diagnostics are sorted by their start position.
+func Lint(g ast.Graph, opts ...Option) []Diagnostic {
+	c := config{severities: make(map[string]Severity)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	var result []Diagnostic
+	for _, rule := range rules {
+		severity, ok := c.severities[rule.Name]
+		if !ok {
+			severity = rule.Severity
+		}
+		if severity == Off {
+			continue
+		}
+
+		for _, d := range rule.Check(g) {
+			d.Code = rule.Name
+			d.Severity = severity
+			result = append(result, d)
+		}
+	}
+
+	diagnostic.Sort(result)
+	return result
+}
+
+// Count returns the number of diagnostics per severity.
+func Count(diagnostics []Diagnostic) map[Severity]int {
+	return diagnostic.Count(diagnostics)
+}
+
+// Fails reports whether any of the diagnostics is at least as severe as the threshold. This allows
+// failing on warnings as well as errors by passing [Warning]. No diagnostic fails the threshold
+// [Off].
+func Fails(diagnostics []Diagnostic, threshold Severity) bool {
+	return diagnostic.Fails(diagnostics, threshold)
+}
@@ -0,0 +1,151 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/lint"
+	"github.com/teleivo/dot/token"
+)
+
+func TestLint(t *testing.T) {
+	in := `digraph {
+	rankdir=LR
+	A:n -> B [lhead=cluster_b]
+	C [label=""]
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	rulesAndSeverities := func(diagnostics []lint.Diagnostic) map[string]lint.Severity {
+		result := make(map[string]lint.Severity)
+		for _, d := range diagnostics {
+			result[d.Code] = d.Severity
+		}
+		return result
+	}
+
+	t.Run("DefaultSeverities", func(t *testing.T) {
+		got := lint.Lint(g)
+
+		assert.EqualValuesf(t, rulesAndSeverities(got), map[string]lint.Severity{
+			"compass":  lint.Info,
+			"compound": lint.Warning,
+			"text":     lint.Info,
+		}, "Lint(%q)", in)
+		assert.EqualValuesf(t, lint.Count(got), map[lint.Severity]int{
+			lint.Info:    2,
+			lint.Warning: 2,
+		}, "Count(Lint(%q))", in)
+	})
+
+	t.Run("OverriddenSeverities", func(t *testing.T) {
+		got := lint.Lint(g,
+			lint.WithSeverity("compass", lint.Error),
+			lint.WithSeverity("compound", lint.Off),
+		)
+
+		assert.EqualValuesf(t, rulesAndSeverities(got), map[string]lint.Severity{
+			"compass": lint.Error,
+			"text":    lint.Info,
+		}, "Lint(%q)", in)
+	})
+}
+
+func TestFails(t *testing.T) {
+	diagnostics := []lint.Diagnostic{{Severity: lint.Info}, {Severity: lint.Warning}}
+
+	tests := map[lint.Severity]bool{
+		lint.Error:   false,
+		lint.Warning: true,
+		lint.Info:    true,
+		lint.Off:     false,
+	}
+
+	for threshold, want := range tests {
+		t.Run(threshold.String(), func(t *testing.T) {
+			got := lint.Fails(diagnostics, threshold)
+
+			assert.EqualValuesf(t, got, want, "Fails(%v, %s)", diagnostics, threshold)
+		})
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	tests := map[string]lint.Severity{
+		"error":   lint.Error,
+		"Warning": lint.Warning,
+		"warn":    lint.Warning,
+		"INFO":    lint.Info,
+		"off":     lint.Off,
+	}
+
+	for in, want := range tests {
+		t.Run(in, func(t *testing.T) {
+			got, err := lint.ParseSeverity(in)
+
+			require.NoErrorf(t, err, "ParseSeverity(%q)", in)
+			assert.EqualValuesf(t, got, want, "ParseSeverity(%q)", in)
+		})
+	}
+
+	_, err := lint.ParseSeverity("fatal")
+	require.NotNilf(t, err, "ParseSeverity(%q)", "fatal")
+}
+
+func TestShortEdgeLabel(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want []lint.Diagnostic
+	}{
+		"WithoutLayout": {
+			in: `digraph {
+	A -> B [label="a very long label"]
+}`,
+		},
+		"LabelsFittingTheirEdges": {
+			in: `digraph {
+	A [pos="27,90"]
+	B [pos="27,18"]
+	A -> B [label=short]
+}`,
+		},
+		"LabelsWiderThanTheirEdges": {
+			in: `digraph {
+	A [pos="27,90"]
+	B [pos="27,18"]
+	C [pos="100,18"]
+	A -> B -> C [label="a very long label",fontsize=20]
+}`,
+			want: []lint.Diagnostic{
+				{
+					Start:    token.Position{Row: 5, Column: 15},
+					End:      token.Position{Row: 5, Column: 39},
+					Severity: lint.Info,
+					Code:     "short-edge-label",
+					Message:  "label of edge A -> B is 139pt wide but the edge is only 72pt long",
+				},
+				{
+					Start:    token.Position{Row: 5, Column: 15},
+					End:      token.Position{Row: 5, Column: 39},
+					Severity: lint.Info,
+					Code:     "short-edge-label",
+					Message:  "label of edge B -> C is 139pt wide but the edge is only 73pt long",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			got := lint.Lint(g)
+
+			assert.EqualValuesf(t, got, test.want, "Lint(%q)", test.in)
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Package matrix reads graphs from adjacency matrices.
+package matrix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// Options configures how an adjacency matrix is read by [Read].
+type Options struct {
+	Directed  bool    // Directed creates a directed instead of an undirected graph.
+	Threshold float64 // Threshold is the weight an entry must exceed to create an edge.
+}
+
+// Read reads an adjacency matrix from r and returns it as a graph. An edge from the node of row i to
+// the node of column j is created if the entry in row i and column j exceeds the threshold. The
+// entries of a row are separated by whitespace or commas. Names can be double-quoted to contain
+// either. Empty lines are skipped.
+//
+// The first row is a header holding the node names if it contains an entry that is not a number.
+// Rows can start with the name of their node as well. Nodes are named by their zero based index if
+// neither is given
+//
+//	  A B C
+//	A 0 1 0
+//	B 0 0 1
+//	C 1 0 0
+//
+// An edge in an undirected graph is created if either of the entries i,j or j,i exceeds the
+// threshold. All nodes are declared so nodes without edges are part of the graph as well.
+func Read(r io.Reader, opts Options) (ast.Graph, error) {
+	var header []string
+	var rowNames []string
+	var rows [][]float64
+
+	sc := bufio.NewScanner(r)
+	for lineNr := 1; sc.Scan(); lineNr++ {
+		fields := splitFields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(rows) == 0 && header == nil && !isRow(fields) {
+			header = fields
+			continue
+		}
+
+		var name string
+		if _, err := strconv.ParseFloat(fields[0], 64); err != nil {
+			name, fields = fields[0], fields[1:]
+		}
+		row := make([]float64, len(fields))
+		for i, field := range fields {
+			weight, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return ast.Graph{}, fmt.Errorf("line %d: entry %q is not a number", lineNr, field)
+			}
+			row[i] = weight
+		}
+		if len(rows) > 0 && len(row) != len(rows[0]) {
+			return ast.Graph{}, fmt.Errorf("line %d: row has %d entries instead of %d", lineNr, len(row), len(rows[0]))
+		}
+		if name != "" {
+			rowNames = append(rowNames, name)
+		}
+		rows = append(rows, row)
+	}
+	if err := sc.Err(); err != nil {
+		return ast.Graph{}, err
+	}
+
+	if len(rows) > 0 && len(rows) != len(rows[0]) {
+		return ast.Graph{}, fmt.Errorf("matrix is not square: it has %d rows and %d columns", len(rows), len(rows[0]))
+	}
+	if header != nil && len(header) != len(rows) {
+		return ast.Graph{}, fmt.Errorf("header has %d names for %d rows", len(header), len(rows))
+	}
+	if len(rowNames) > 0 && len(rowNames) != len(rows) {
+		return ast.Graph{}, fmt.Errorf("only %d of %d rows start with a name", len(rowNames), len(rows))
+	}
+
+	names := header
+	if names == nil {
+		names = rowNames
+	}
+	if names == nil {
+		for i := range rows {
+			names = append(names, strconv.Itoa(i))
+		}
+	}
+
+	g := ast.Graph{Directed: opts.Directed}
+	for _, name := range names {
+		g.Stmts = append(g.Stmts, &ast.NodeStmt{NodeID: ast.NodeID{ID: ast.NewID(name)}})
+	}
+	for i, row := range rows {
+		for j, weight := range row {
+			if !opts.Directed {
+				if j < i {
+					continue
+				}
+				weight = max(weight, rows[j][i])
+			}
+			if weight <= opts.Threshold {
+				continue
+			}
+			g.Stmts = append(g.Stmts, &ast.EdgeStmt{
+				Left: ast.NodeID{ID: ast.NewID(names[i])},
+				Right: ast.EdgeRHS{
+					Directed: opts.Directed,
+					Right:    ast.NodeID{ID: ast.NewID(names[j])},
+				},
+			})
+		}
+	}
+	return g, nil
+}
+
+// isRow determines if the fields are a row of the matrix which is the case if all but an optional
+// leading name are numbers.
+func isRow(fields []string) bool {
+	for i, field := range fields {
+		if _, err := strconv.ParseFloat(field, 64); err != nil && i > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFields splits the line into fields separated by whitespace or commas. Separators within
+// double quotes are part of the field while the quotes are not.
+func splitFields(line string) []string {
+	var fields []string
+	var field strings.Builder
+	var inField, inQuotes bool
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inField = true
+		case !inQuotes && (r == ',' || unicode.IsSpace(r)):
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteRune(r)
+			inField = true
+		}
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
@@ -0,0 +1,133 @@
+package matrix_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot/internal/assertx"
+	"github.com/teleivo/dot/matrix"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		tests := map[string]struct {
+			in   string
+			opts matrix.Options
+			want string
+		}{
+			"Empty": {
+				in:   "",
+				want: `graph {}`,
+			},
+			"DirectedWithoutNames": {
+				in: `0 1 0
+0 0 1
+
+1 0 1`,
+				opts: matrix.Options{Directed: true},
+				want: `digraph {
+	0
+	1
+	2
+	0 -> 1
+	1 -> 2
+	2 -> 0
+	2 -> 2
+}`,
+			},
+			"UndirectedUsesBothTriangles": {
+				in: `0,1,0
+0,0,0
+1,0,0`,
+				want: `graph {
+	0
+	1
+	2
+	0 -- 1
+	0 -- 2
+}`,
+			},
+			"HeaderAndRowNames": {
+				in: `   A   "B C"  node
+A      0  0.5     0
+"B C"  0    0   0.9
+node   0    0     0`,
+				opts: matrix.Options{Directed: true, Threshold: 0.5},
+				want: `digraph {
+	A
+	"B C"
+	"node"
+	"B C" -> "node"
+}`,
+			},
+			"HeaderOnly": {
+				in: `A,B
+0,1
+0,0`,
+				opts: matrix.Options{Directed: true},
+				want: `digraph {
+	A
+	B
+	A -> B
+}`,
+			},
+			"RowNamesOnly": {
+				in: `A 0 1
+B 1 0`,
+				want: `graph {
+	A
+	B
+	A -- B
+}`,
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				g, err := matrix.Read(strings.NewReader(test.in), test.opts)
+
+				require.NoErrorf(t, err, "Read(%q)", test.in)
+				assert.EqualValuesf(t, g.String(), test.want, "Read(%q)", test.in)
+			})
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			in     string
+			errMsg string
+		}{
+			"NotANumber": {
+				in:     "0 1\n0 x",
+				errMsg: `line 2: entry "x" is not a number`,
+			},
+			"RowsOfDifferentLength": {
+				in:     "0 1\n0",
+				errMsg: "line 2: row has 1 entries instead of 2",
+			},
+			"NotSquare": {
+				in:     "0 1\n0 1\n1 1",
+				errMsg: "matrix is not square: it has 3 rows and 2 columns",
+			},
+			"HeaderOfDifferentLength": {
+				in:     "A B C\n0 1\n0 1",
+				errMsg: "header has 3 names for 2 rows",
+			},
+			"NotAllRowsNamed": {
+				in:     "A 0 1\n0 1",
+				errMsg: "only 1 of 2 rows start with a name",
+			},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := matrix.Read(strings.NewReader(test.in), matrix.Options{})
+
+				require.NotNilf(t, err, "Read(%q)", test.in)
+				assertx.Contains(t, err.Error(), test.errMsg)
+			})
+		}
+	})
+}
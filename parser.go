@@ -2,23 +2,99 @@
 package dot
 
 import (
-	"errors"
+	"bufio"
+	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"slices"
+	"unicode/utf8"
 
 	"github.com/teleivo/dot/ast"
 	"github.com/teleivo/dot/token"
 )
 
+// MaxDepth is the max number of nested subgraphs the parser accepts and the printer prints. It
+// protects them and any code recursively walking the AST from overflowing the stack on adversarial
+// input.
+const MaxDepth = 1000
+
 type Parser struct {
-	scanner   *Scanner
-	curToken  token.Token
-	peekToken token.Token
-	comments  []ast.Comment
+	scanner    *Scanner
+	curToken   token.Token
+	peekToken  token.Token
+	comments   []ast.Comment
+	depth      int            // depth is the current number of nested subgraphs
+	nextGraph  bool           // nextGraph indicates that the current token starts the header of the next graph
+	keepTokens bool           // keepTokens indicates that all tokens read are kept in tokens
+	tokens     []token.Token  // tokens lists all tokens read if keepTokens is set
+	lastEnd    token.Position // lastEnd is the position of the last rune of the last token read
+	hints      bool           // hints indicates that errors are followed by a hint on the grammar production
+	production string         // production is the grammar production being parsed if hints is set
+}
+
+// ParserOption configures a [Parser].
+type ParserOption func(*Parser)
+
+// WithTokens makes the parser keep all tokens it reads including comments. Access them using
+// [Parser.Tokens] after parsing. Tools can use them to navigate from an AST node to its neighboring
+// tokens.
+func WithTokens() ParserOption {
+	return func(p *Parser) {
+		p.keepTokens = true
+	}
+}
+
+// WithGrammarHints makes the parser follow errors about unexpected tokens by a hint on the
+// production of the [DOT grammar] it was parsing like
+//
+//	1:14: expected next token to be "[" but got "}" instead; in attr_stmt : (graph | node | edge) attr_list see https://graphviz.org/doc/info/lang.html
+//
+// The hints help users new to DOT. Errors are terse without this option which suits CI logs.
+//
+// [DOT grammar]: https://graphviz.org/doc/info/lang.html
+func WithGrammarHints() ParserOption {
+	return func(p *Parser) {
+		p.hints = true
+	}
+}
+
+// productions maps the productions of the DOT grammar to their rule as defined in
+// https://graphviz.org/doc/info/lang.html.
+var productions = map[string]string{
+	"graph":     "[ strict ] (graph | digraph) [ ID ] '{' stmt_list '}'",
+	"stmt":      "node_stmt | edge_stmt | attr_stmt | ID '=' ID | subgraph",
+	"attr_stmt": "(graph | node | edge) attr_list",
+	"attr_list": "'[' [ a_list ] ']' [ attr_list ]",
+	"a_list":    "ID '=' ID [ (';' | ',') ] [ a_list ]",
+	"edgeRHS":   "edgeop (node_id | subgraph) [ edgeRHS ]",
+	"node_id":   "ID [ port ]",
+	"port":      "':' ID [ ':' compass_pt ] | ':' compass_pt",
+	"subgraph":  "[ subgraph [ ID ] ] '{' stmt_list '}'",
 }
 
-func NewParser(r io.Reader) (*Parser, error) {
+// enter records that the parser starts parsing the grammar production if hints are enabled. Call
+// the returned function once the production is parsed.
+func (p *Parser) enter(production string) func() {
+	if !p.hints {
+		return func() {}
+	}
+	prev := p.production
+	p.production = production
+	return func() { p.production = prev }
+}
+
+// hint returns the hint on the grammar production being parsed to follow an error message. It is
+// empty if hints are not enabled.
+func (p *Parser) hint() string {
+	rule, ok := productions[p.production]
+	if !p.hints || !ok {
+		return ""
+	}
+	return "; in " + p.production + " : " + rule + " see https://graphviz.org/doc/info/lang.html"
+}
+
+func NewParser(r io.Reader, opts ...ParserOption) (*Parser, error) {
 	scanner, err := NewScanner(r)
 	if err != nil {
 		return nil, err
@@ -27,6 +103,9 @@ func NewParser(r io.Reader) (*Parser, error) {
 	p := Parser{
 		scanner: scanner,
 	}
+	for _, opt := range opts {
+		opt(&p)
+	}
 
 	// initialize peek token
 	err = p.nextToken()
@@ -37,12 +116,46 @@ func NewParser(r io.Reader) (*Parser, error) {
 	return &p, nil
 }
 
+// Parse parses the dot source code in src into a graph. Empty statements like runs of semicolons
+// separating statements are not part of the graph. They are dropped as they carry no meaning so
+// formatting them drops them as well.
+func Parse(src []byte) (ast.Graph, error) {
+	return ParseReader(bytes.NewReader(src))
+}
+
+// ParseReader parses the dot source code read from r into a graph.
+func ParseReader(r io.Reader) (ast.Graph, error) {
+	p, err := NewParser(r)
+	if err != nil {
+		return ast.Graph{}, err
+	}
+	return p.Parse()
+}
+
+// ParseFile parses the dot source code in the file at given path into a graph. Errors are prefixed
+// with the path of the file.
+func ParseFile(path string) (ast.Graph, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ast.Graph{}, err
+	}
+	defer f.Close()
+
+	g, err := ParseReader(bufio.NewReader(f))
+	if err != nil {
+		return g, fmt.Errorf("%s: %w", path, err)
+	}
+	return g, nil
+}
+
 // nextToken advances to the next non-comment token. Any comments that are encountered in the
 // process are collected.
 func (p *Parser) nextToken() error {
 	var tok token.Token
 	var err error
 	for tok, err = p.scanner.Next(); err == nil && tok.Type == token.Comment; tok, err = p.scanner.Next() {
+		p.keepToken(tok)
+		p.lastEnd = tok.End
 		comment := ast.Comment{
 			Text:     tok.Literal,
 			StartPos: tok.Start,
@@ -53,6 +166,10 @@ func (p *Parser) nextToken() error {
 	if err != nil {
 		return err
 	}
+	p.keepToken(tok)
+	if tok.Type != token.EOF {
+		p.lastEnd = tok.End
+	}
 
 	p.curToken = p.peekToken
 	p.peekToken = tok
@@ -60,12 +177,28 @@ func (p *Parser) nextToken() error {
 	return nil
 }
 
+func (p *Parser) keepToken(tok token.Token) {
+	if p.keepTokens && tok.Type != token.EOF {
+		p.tokens = append(p.tokens, tok)
+	}
+}
+
+// Tokens returns all tokens read by the parser in the order they appear in the source if the
+// parser was created using [WithTokens]. Use [token.Index] to find the token of an AST node.
+func (p *Parser) Tokens() []token.Token {
+	return p.tokens
+}
+
+// Parse parses the next graph. Call it again to parse any graphs that follow. A graph that is
+// missing its closing brace is returned along with an error holding the statements parsed up to
+// the end of the file or the header of the next graph. The next graph is parsed by the next call
+// so a single unclosed graph does not affect its siblings.
 func (p *Parser) Parse() (ast.Graph, error) {
-	// if p.isDone() {
-	if p.peekTokenIs(token.EOF) {
+	if !p.nextGraph && p.peekTokenIs(token.EOF) {
 		var graph ast.Graph
 		return graph, nil
 	}
+	defer p.enter("graph")()
 
 	graph, err := p.parseHeader()
 	if err != nil {
@@ -77,30 +210,43 @@ func (p *Parser) Parse() (ast.Graph, error) {
 		return graph, err
 	}
 	graph.LeftBrace = p.curToken.Start
-	// TODO improve/test what if brace is unbalanced/EOF
 	err = p.nextToken()
 	if err != nil {
 		return graph, err
 	}
 
-	stmts, err := p.parseStatementList(graph)
+	stmts, err := p.parseStatementList(graph, graph.LeftBrace)
+	graph.Stmts = stmts
+	graph.Comments = p.comments
 	if err != nil {
 		return graph, err
 	}
-	graph.Stmts = stmts
 	graph.RightBrace = p.curToken.End
-	graph.Comments = p.comments
 
 	return graph, err
 }
 
-func (p *Parser) parseStatementList(graph ast.Graph) ([]ast.Stmt, error) {
+// parseStatementList parses statements up to the right brace closing the left brace at given
+// position. An error is returned if the file ends or the next graph starts before that.
+func (p *Parser) parseStatementList(graph ast.Graph, leftBrace token.Position) ([]ast.Stmt, error) {
 	var stmts []ast.Stmt
 	var err error
-	for ; !p.curTokenIsOneOf(token.EOF, token.RightBrace) && err == nil; err = p.nextToken() {
+	for ; !p.curTokenIs(token.RightBrace) && err == nil; err = p.nextToken() {
+		if p.isEOF() {
+			return stmts, p.syntaxError(p.curToken, "expected '}' to close the '{' at %s but reached the end of the file", leftBrace)
+		}
+		if p.curTokenStartsGraph() {
+			p.nextGraph = true
+			return stmts, p.syntaxError(p.curToken, "expected '}' to close the '{' at %s but got the next graph", leftBrace)
+		}
+
 		var stmt ast.Stmt
 		stmt, err = p.parseStatement(graph)
 		if err != nil {
+			// keep the statements of an unclosed subgraph
+			if subgraph, ok := stmt.(ast.Subgraph); ok && subgraph.LeftBrace.Row > 0 {
+				stmts = append(stmts, subgraph)
+			}
 			return stmts, err
 		}
 
@@ -115,9 +261,14 @@ func (p *Parser) parseStatementList(graph ast.Graph) ([]ast.Stmt, error) {
 func (p *Parser) parseHeader() (ast.Graph, error) {
 	var graph ast.Graph
 
-	err := p.expectPeekTokenIsOneOf(token.Strict, token.Graph, token.Digraph)
-	if err != nil {
-		return graph, err
+	// the header of a graph following an unclosed graph has already been read
+	if p.nextGraph {
+		p.nextGraph = false
+	} else {
+		err := p.expectPeekTokenIsOneOf(token.Strict, token.Graph, token.Digraph)
+		if err != nil {
+			return graph, err
+		}
 	}
 
 	if p.curTokenIs(token.Strict) {
@@ -146,12 +297,16 @@ func (p *Parser) parseHeader() (ast.Graph, error) {
 			StartPos: p.curToken.Start,
 			EndPos:   p.curToken.End,
 		}
+	} else if !p.peekTokenIs(token.Strict) && isKeyword(p.peekToken.Type) {
+		return graph, keywordAsIDError(p.peekToken)
 	}
 
 	return graph, nil
 }
 
 func (p *Parser) parseStatement(graph ast.Graph) (ast.Stmt, error) {
+	defer p.enter("stmt")()
+
 	if p.curTokenIs(token.Identifier) && p.peekTokenIs(token.Equal) {
 		return p.parseAttribute()
 	} else if p.curTokenIsOneOf(token.Identifier, token.Subgraph, token.LeftBrace) {
@@ -183,7 +338,7 @@ func (p *Parser) parseStatement(graph ast.Graph) (ast.Stmt, error) {
 		} else if p.curTokenIs(token.Subgraph) || p.curTokenIs(token.LeftBrace) {
 			subraph, err := p.parseSubgraph(graph)
 			if err != nil {
-				return stmt, err
+				return subraph, err
 			}
 
 			left = subraph
@@ -226,7 +381,7 @@ func (p *Parser) parseStatement(graph ast.Graph) (ast.Stmt, error) {
 	} else if p.curTokenIsOneOf(token.Graph, token.Node, token.Edge) {
 		return p.parseAttrStatement()
 	} else if p.curTokenIs(token.Equal) {
-		return nil, errors.New(`expected an "IDENTIFIER" before the '='`)
+		return nil, p.syntaxError(p.curToken, `expected an "IDENTIFIER" before the '='%s`, p.hint())
 	}
 
 	return nil, nil
@@ -241,6 +396,8 @@ func (p *Parser) parseEdgeOperand(graph ast.Graph) (ast.EdgeOperand, error) {
 }
 
 func (p *Parser) parseEdgeRHS(graph ast.Graph) (ast.EdgeRHS, error) {
+	defer p.enter("edgeRHS")()
+
 	var first, cur *ast.EdgeRHS
 	for p.curTokenIsOneOf(token.UndirectedEgde, token.DirectedEgde) {
 		operatorStart := p.curToken.Start
@@ -249,10 +406,10 @@ func (p *Parser) parseEdgeRHS(graph ast.Graph) (ast.EdgeRHS, error) {
 			directed = true
 		}
 		if directed && !graph.Directed {
-			return ast.EdgeRHS{}, errors.New("undirected graph cannot contain directed edges")
+			return ast.EdgeRHS{}, p.syntaxError(p.curToken, "undirected graph cannot contain directed edges")
 		}
 		if !directed && graph.Directed {
-			return ast.EdgeRHS{}, errors.New("directed graph cannot contain undirected edges")
+			return ast.EdgeRHS{}, p.syntaxError(p.curToken, "directed graph cannot contain undirected edges")
 		}
 
 		err := p.expectPeekTokenIsOneOf(token.Identifier, token.Subgraph, token.LeftBrace)
@@ -293,6 +450,8 @@ func (p *Parser) parseEdgeRHS(graph ast.Graph) (ast.EdgeRHS, error) {
 }
 
 func (p *Parser) parseNodeID() (ast.NodeID, error) {
+	defer p.enter("node_id")()
+
 	nid := ast.NodeID{
 		ID: ast.ID{
 			Literal:  p.curToken.Literal,
@@ -316,6 +475,8 @@ func (p *Parser) parseNodeID() (ast.NodeID, error) {
 }
 
 func (p *Parser) parsePort() (*ast.Port, error) {
+	defer p.enter("port")()
+
 	err := p.expectPeekTokenIsOneOf(token.Identifier)
 	if err != nil {
 		return nil, err
@@ -361,8 +522,9 @@ func (p *Parser) parsePort() (*ast.Port, error) {
 
 	cp, ok := ast.IsCompassPoint(p.curToken.Literal)
 	if !ok {
-		return &port, fmt.Errorf(
-			"expected a compass point %v instead got %q",
+		return &port, p.syntaxError(
+			p.curToken,
+			"expected a compass point %v instead got %q%s",
 			[]string{
 				ast.CompassPointUnderscore.String(),
 				ast.CompassPointNorth.String(),
@@ -376,6 +538,7 @@ func (p *Parser) parsePort() (*ast.Port, error) {
 				ast.CompassPointCenter.String(),
 			},
 			p.curToken.Literal,
+			p.hint(),
 		)
 	}
 	port.CompassPoint = &ast.CompassPoint{
@@ -388,6 +551,8 @@ func (p *Parser) parsePort() (*ast.Port, error) {
 }
 
 func (p *Parser) parseAttrStatement() (*ast.AttrStmt, error) {
+	defer p.enter("attr_stmt")()
+
 	ns := &ast.AttrStmt{ID: ast.ID{
 		Literal:  p.curToken.Literal,
 		StartPos: p.curToken.Start,
@@ -410,6 +575,8 @@ func (p *Parser) parseAttrStatement() (*ast.AttrStmt, error) {
 }
 
 func (p *Parser) parseAttrList() (*ast.AttrList, error) {
+	defer p.enter("attr_list")()
+
 	var first, cur *ast.AttrList
 	for p.curTokenIs(token.LeftBracket) {
 		openingBracketStart := p.curToken.Start
@@ -457,6 +624,8 @@ func (p *Parser) parseAttrList() (*ast.AttrList, error) {
 }
 
 func (p *Parser) parseAList() (*ast.AList, error) {
+	defer p.enter("a_list")()
+
 	var first, cur *ast.AList
 	for p.curTokenIs(token.Identifier) {
 		attr, err := p.parseAttribute()
@@ -516,8 +685,16 @@ func (p *Parser) parseAttribute() (ast.Attribute, error) {
 }
 
 func (p *Parser) parseSubgraph(graph ast.Graph) (ast.Subgraph, error) {
+	defer p.enter("subgraph")()
+
 	var subgraph ast.Subgraph
 
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > MaxDepth {
+		return subgraph, p.syntaxError(p.curToken, "subgraphs are nested deeper than the max depth of %d", MaxDepth)
+	}
+
 	if p.curTokenIs(token.Subgraph) {
 		subgraph.SubgraphStart = p.curPos()
 
@@ -533,6 +710,8 @@ func (p *Parser) parseSubgraph(graph ast.Graph) (ast.Subgraph, error) {
 				StartPos: p.curToken.Start,
 				EndPos:   p.curToken.End,
 			}
+		} else if isKeyword(p.peekToken.Type) {
+			return subgraph, keywordAsIDError(p.peekToken)
 		}
 
 		err = p.expectPeekTokenIsOneOf(token.LeftBrace)
@@ -546,17 +725,25 @@ func (p *Parser) parseSubgraph(graph ast.Graph) (ast.Subgraph, error) {
 		return subgraph, err
 	}
 
-	stmts, err := p.parseStatementList(graph)
+	stmts, err := p.parseStatementList(graph, subgraph.LeftBrace)
+	subgraph.Stmts = stmts
 	if err != nil {
-		return subgraph, nil
+		return subgraph, err
 	}
-	subgraph.Stmts = stmts
 
 	subgraph.RightBrace = p.curToken.End
 
 	return subgraph, nil
 }
 
+// curTokenStartsGraph reports whether the current token starts the header of a graph. The graph
+// keyword only starts a graph if it is not followed by the attribute list of an attribute
+// statement.
+func (p *Parser) curTokenStartsGraph() bool {
+	return p.curTokenIsOneOf(token.Strict, token.Digraph) ||
+		(p.curTokenIs(token.Graph) && p.peekTokenIsOneOf(token.Identifier, token.LeftBrace))
+}
+
 func (p *Parser) isDone() bool {
 	return p.isEOF()
 }
@@ -585,10 +772,13 @@ func (p *Parser) peekTokenIs(t token.TokenType) bool {
 // Otherwise, the parser position is not changed and an error is returned.
 func (p *Parser) expectPeekTokenIsOneOf(want ...token.TokenType) error {
 	if !p.peekTokenIsOneOf(want...) {
+		if slices.Contains(want, token.Identifier) && isKeyword(p.peekToken.Type) {
+			return keywordAsIDError(p.peekToken)
+		}
 		if len(want) == 1 {
-			return fmt.Errorf("expected next token to be %q but got %q instead", want[0], p.peekToken)
+			return p.syntaxError(p.peekToken, "expected next token to be %q but got %q instead%s", want[0], p.peekToken, p.hint())
 		}
-		return fmt.Errorf("expected next token to be one of %q but got %q instead", want, p.peekToken)
+		return p.syntaxError(p.peekToken, "expected next token to be one of %q but got %q instead%s", want, p.peekToken, p.hint())
 	}
 
 	err := p.nextToken()
@@ -599,6 +789,49 @@ func (p *Parser) expectPeekTokenIsOneOf(want ...token.TokenType) error {
 	return nil
 }
 
+// isKeyword reports whether the token type is a keyword.
+func isKeyword(tokenType token.TokenType) bool {
+	switch tokenType {
+	case token.Strict, token.Graph, token.Digraph, token.Node, token.Edge, token.Subgraph:
+		return true
+	}
+	return false
+}
+
+// keywordAsIDError returns an error of category [KeywordAsID] for the keyword token used in place
+// of an identifier. Keywords are case-independent so graph, Graph and GRAPH can only be used as
+// identifiers if quoted.
+func keywordAsIDError(tok token.Token) Error {
+	first, _ := utf8.DecodeRuneInString(tok.Literal)
+	return Error{
+		LineNr:      tok.Start.Row,
+		CharacterNr: tok.Start.Column,
+		Character:   first,
+		Reason:      fmt.Sprintf("keyword %s cannot be used as an identifier unless quoted like %q", tok.Literal, tok.Literal),
+		Category:    KeywordAsID,
+		Resume:      token.Position{Row: tok.End.Row, Column: tok.End.Column + 1},
+	}
+}
+
+// syntaxError returns an error of category [InvalidSyntax] at the start of the token. Errors at
+// the end of the file are reported right after the last token read.
+func (p *Parser) syntaxError(tok token.Token, format string, args ...any) Error {
+	err := Error{
+		LineNr:      tok.Start.Row,
+		CharacterNr: tok.Start.Column,
+		Reason:      fmt.Sprintf(format, args...),
+		Category:    InvalidSyntax,
+		Resume:      token.Position{Row: tok.End.Row, Column: tok.End.Column + 1},
+	}
+	if tok.Type == token.EOF {
+		err.LineNr, err.CharacterNr = p.lastEnd.Row, p.lastEnd.Column+1
+		err.Resume = token.Position{Row: err.LineNr, Column: err.CharacterNr}
+		return err
+	}
+	err.Character, _ = utf8.DecodeRuneInString(tok.Literal)
+	return err
+}
+
 func (p *Parser) advanceIfPeekTokenIsOneOf(tokens ...token.TokenType) (bool, error) {
 	if !p.peekTokenIsOneOf(tokens...) {
 		return false, nil
@@ -1,6 +1,9 @@
 package dot_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -8,6 +11,7 @@ import (
 	"github.com/teleivo/assertive/require"
 	"github.com/teleivo/dot"
 	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/internal/assertx"
 	"github.com/teleivo/dot/token"
 )
 
@@ -131,7 +135,7 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
@@ -162,6 +166,25 @@ graph {
 					RightBrace: token.Position{Row: 1, Column: 13},
 				},
 			},
+			"EmptyStatementsAreDropped": {
+				in: "graph { ;; foo ;;; ; }",
+				want: ast.Graph{
+					GraphStart: token.Position{Row: 1, Column: 1},
+					Stmts: []ast.Stmt{
+						&ast.NodeStmt{
+							NodeID: ast.NodeID{
+								ID: ast.ID{
+									Literal:  "foo",
+									StartPos: token.Position{Row: 1, Column: 12},
+									EndPos:   token.Position{Row: 1, Column: 14},
+								},
+							},
+						},
+					},
+					LeftBrace:  token.Position{Row: 1, Column: 7},
+					RightBrace: token.Position{Row: 1, Column: 22},
+				},
+			},
 			"OnlyNodes": {
 				in: `graph { foo ; bar baz
 					trash
@@ -1005,7 +1028,7 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
@@ -1473,7 +1496,7 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
@@ -1693,7 +1716,7 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
@@ -1814,7 +1837,7 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
@@ -1951,6 +1974,14 @@ graph {
 					in:     "graph { { }",
 					errMsg: `expected next token to be one of ["}" "IDENTIFIER"]`,
 				},
+				"NestedDeeperThanMaxDepth": {
+					in:     "graph { " + strings.Repeat("subgraph {", 100_000) + strings.Repeat("}", 100_000) + " }",
+					errMsg: "subgraphs are nested deeper than the max depth of 1000",
+				},
+				"EdgeOperandsNestedDeeperThanMaxDepth": {
+					in:     "graph { " + strings.Repeat("A -- {", 100_000) + strings.Repeat("}", 100_000) + " }",
+					errMsg: "subgraphs are nested deeper than the max depth of 1000",
+				},
 			}
 
 			for name, test := range tests {
@@ -1962,12 +1993,20 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
 	})
 
+	t.Run("SubgraphsNestedUpToMaxDepth", func(t *testing.T) {
+		in := "graph { " + strings.Repeat("{", 1000) + strings.Repeat("}", 1000) + " }"
+
+		_, err := dot.Parse([]byte(in))
+
+		require.NoErrorf(t, err, "Parse(%q)", in)
+	})
+
 	t.Run("Comment", func(t *testing.T) {
 		tests := map[string]struct {
 			in   string
@@ -2062,15 +2101,437 @@ graph {
 					_, err = p.Parse()
 
 					require.NotNilf(t, err, "Parse(%q)", test.in)
-					assertContains(t, err.Error(), test.errMsg)
+					assertx.Contains(t, err.Error(), test.errMsg)
 				})
 			}
 		})
 	})
 }
 
-func assertContains(t *testing.T, got, want string) {
-	if !strings.Contains(got, want) {
-		t.Errorf("got %q which does not contain %q", got, want)
+func TestParserUnclosedGraph(t *testing.T) {
+	type parsed struct {
+		graph  string // graph is the graph formatted using its String method
+		errMsg string // errMsg is empty if no error is expected
+	}
+	tests := map[string]struct {
+		in   string
+		want []parsed
+	}{
+		"TruncatedAfterStatement": {
+			in: `digraph {
+	a -> b
+	b -> c`,
+			want: []parsed{
+				{
+					graph: `digraph {
+	a -> b
+	b -> c
+}`,
+					errMsg: "3:8: expected '}' to close the '{' at 1:9 but reached the end of the file",
+				},
+			},
+		},
+		"TruncatedAfterComment": {
+			in: `digraph {
+	a -> b
+	// generator crashed here
+`,
+			want: []parsed{
+				{
+					graph: `digraph {
+	a -> b
+}`,
+					errMsg: "3:27: expected '}' to close the '{' at 1:9 but reached the end of the file",
+				},
+			},
+		},
+		"TruncatedInSubgraph": {
+			in: `graph {
+	a
+	subgraph cluster_b {
+		b -- c`,
+			want: []parsed{
+				{
+					graph: `graph {
+	a
+	subgraph cluster_b {b -- c}
+}`,
+					errMsg: "4:9: expected '}' to close the '{' at 3:21 but reached the end of the file",
+				},
+			},
+		},
+		"NextGraphParsedAsSibling": {
+			in: `digraph first {
+	a -> b
+digraph second {
+	c -> d
+}`,
+			want: []parsed{
+				{
+					graph: `digraph first {
+	a -> b
+}`,
+					errMsg: "3:1: expected '}' to close the '{' at 1:15 but got the next graph",
+				},
+				{
+					graph: `digraph second {
+	c -> d
+}`,
+				},
+				{
+					graph: `graph {}`,
+				},
+			},
+		},
+		"NextGraphWithoutStatementsParsedAsSibling": {
+			in: `graph {
+strict graph { a }`,
+			want: []parsed{
+				{
+					graph:  `graph {}`,
+					errMsg: "2:1: expected '}' to close the '{' at 1:7 but got the next graph",
+				},
+				{
+					graph: `strict graph {
+	a
+}`,
+				},
+			},
+		},
+		"NextGraphInSubgraphParsedAsSibling": {
+			in: `graph {
+	subgraph {
+		a
+graph {
+	b
+}`,
+			want: []parsed{
+				{
+					graph: `graph {
+	subgraph {a}
+}`,
+					errMsg: "4:1: expected '}' to close the '{' at 2:11 but got the next graph",
+				},
+				{
+					graph: `graph {
+	b
+}`,
+				},
+			},
+		},
+		"GraphAttributeStatementIsNoNextGraph": {
+			in: `graph {
+	a
+	graph [rankdir=LR]`,
+			want: []parsed{
+				{
+					graph: `graph {
+	a
+	graph [rankdir=LR]
+}`,
+					errMsg: "3:20: expected '}' to close the '{' at 1:7 but reached the end of the file",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := dot.NewParser(strings.NewReader(test.in))
+			require.NoErrorf(t, err, "NewParser(%q)", test.in)
+
+			for i, want := range test.want {
+				g, err := p.Parse()
+
+				if want.errMsg == "" {
+					require.NoErrorf(t, err, "Parse(%q) graph %d", test.in, i)
+				} else {
+					require.NotNilf(t, err, "Parse(%q) graph %d", test.in, i)
+					assert.EqualValuesf(t, err.Error(), want.errMsg, "Parse(%q) graph %d", test.in, i)
+				}
+				assert.EqualValuesf(t, g.String(), want.graph, "Parse(%q) graph %d", test.in, i)
+			}
+		})
 	}
 }
+
+func TestParse(t *testing.T) {
+	src := `digraph { A -> B }`
+	want := ast.Graph{
+		GraphStart: token.Position{Row: 1, Column: 1},
+		Directed:   true,
+		LeftBrace:  token.Position{Row: 1, Column: 9},
+		Stmts: []ast.Stmt{
+			&ast.EdgeStmt{
+				Left: ast.NodeID{
+					ID: ast.ID{
+						Literal:  "A",
+						StartPos: token.Position{Row: 1, Column: 11},
+						EndPos:   token.Position{Row: 1, Column: 11},
+					},
+				},
+				Right: ast.EdgeRHS{
+					StartPos: token.Position{Row: 1, Column: 13},
+					Directed: true,
+					Right: ast.NodeID{
+						ID: ast.ID{
+							Literal:  "B",
+							StartPos: token.Position{Row: 1, Column: 16},
+							EndPos:   token.Position{Row: 1, Column: 16},
+						},
+					},
+				},
+			},
+		},
+		RightBrace: token.Position{Row: 1, Column: 18},
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		g, err := dot.Parse([]byte(src))
+
+		require.NoErrorf(t, err, "Parse(%q)", src)
+		assert.EqualValuesf(t, g, want, "Parse(%q)", src)
+	})
+
+	t.Run("ParseReader", func(t *testing.T) {
+		g, err := dot.ParseReader(strings.NewReader(src))
+
+		require.NoErrorf(t, err, "ParseReader(%q)", src)
+		assert.EqualValuesf(t, g, want, "ParseReader(%q)", src)
+	})
+
+	t.Run("ParseFile", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "graph.dot")
+		err := os.WriteFile(path, []byte(src), 0o600)
+		require.NoErrorf(t, err, "WriteFile(%q)", path)
+
+		g, err := dot.ParseFile(path)
+
+		require.NoErrorf(t, err, "ParseFile(%q)", path)
+		assert.EqualValuesf(t, g, want, "ParseFile(%q)", path)
+	})
+
+	t.Run("ParseFileWithInvalidSource", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "graph.dot")
+		err := os.WriteFile(path, []byte("graph { A -> B }"), 0o600)
+		require.NoErrorf(t, err, "WriteFile(%q)", path)
+
+		_, err = dot.ParseFile(path)
+
+		require.NotNilf(t, err, "ParseFile(%q)", path)
+		assertx.Contains(t, err.Error(), path+": 1:11: undirected graph cannot contain directed edges")
+	})
+}
+
+func TestParseNewID(t *testing.T) {
+	tests := map[string]string{
+		"Keyword":                "node",
+		"Quotes":                 `say "hi"`,
+		"TrailingBackslash":      `a\`,
+		"BackslashBeforeNewline": "a\\\nb",
+		"BackslashBeforeQuote":   `a\"`,
+	}
+
+	for name, id := range tests {
+		t.Run(name, func(t *testing.T) {
+			in := "graph { " + ast.NewID(id).Literal + " }"
+
+			g, err := dot.Parse([]byte(in))
+
+			require.NoErrorf(t, err, "Parse(%q)", in)
+			require.EqualValuesf(t, len(g.Stmts), 1, "Parse(%q)", in)
+			stmt, ok := g.Stmts[0].(*ast.NodeStmt)
+			require.Truef(t, ok, "Parse(%q) want a node statement instead got %T", in, g.Stmts[0])
+			assert.EqualValuesf(t, stmt.NodeID.ID.Unquoted(), id, "Parse(%q)", in)
+		})
+	}
+}
+
+func TestParserKeywordAsID(t *testing.T) {
+	t.Run("Quoted", func(t *testing.T) {
+		in := `digraph "graph" {
+	"node" -> "Edge" [label="subgraph"]
+	subgraph "STRICT" { "digraph":"node" }
+}`
+		_, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+	})
+
+	tests := map[string]struct {
+		in      string
+		wantErr string
+	}{
+		"GraphID": {
+			in:      "graph graph {}",
+			wantErr: `1:7: keyword graph cannot be used as an identifier unless quoted like "graph"`,
+		},
+		"SubgraphID": {
+			in:      "graph { subgraph Node {} }",
+			wantErr: `1:18: keyword Node cannot be used as an identifier unless quoted like "Node"`,
+		},
+		"EdgeOperand": {
+			in:      "graph { A -- EDGE }",
+			wantErr: `1:14: keyword EDGE cannot be used as an identifier unless quoted like "EDGE"`,
+		},
+		"AttributeName": {
+			in:      "graph { A [node=b] }",
+			wantErr: `1:12: keyword node cannot be used as an identifier unless quoted like "node"`,
+		},
+		"AttributeValue": {
+			in:      "graph { A [label=strict] }",
+			wantErr: `1:18: keyword strict cannot be used as an identifier unless quoted like "strict"`,
+		},
+		"Port": {
+			in:      "graph { A:subgraph }",
+			wantErr: `1:11: keyword subgraph cannot be used as an identifier unless quoted like "subgraph"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := dot.Parse([]byte(test.in))
+
+			require.NotNilf(t, err, "Parse(%q)", test.in)
+			assert.EqualValuesf(t, err.Error(), test.wantErr, "Parse(%q)", test.in)
+			var dotErr dot.Error
+			require.Truef(t, errors.As(err, &dotErr), "Parse(%q) should return a dot.Error", test.in)
+			assert.EqualValuesf(t, dotErr.Category, dot.KeywordAsID, "Parse(%q)", test.in)
+		})
+	}
+}
+
+func TestParserSyntaxError(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want dot.Error
+	}{
+		"UnexpectedToken": {
+			in: "graph { A -- }",
+			want: dot.Error{
+				LineNr:      1,
+				CharacterNr: 14,
+				Character:   '}',
+				Reason:      `expected next token to be one of ["IDENTIFIER" "subgraph" "{"] but got "}" instead`,
+				Category:    dot.InvalidSyntax,
+				Resume:      token.Position{Row: 1, Column: 15},
+			},
+		},
+		"EdgeOperator": {
+			in: "graph {\n\tA -> B\n}",
+			want: dot.Error{
+				LineNr:      2,
+				CharacterNr: 4,
+				Character:   '-',
+				Reason:      "undirected graph cannot contain directed edges",
+				Category:    dot.InvalidSyntax,
+				Resume:      token.Position{Row: 2, Column: 6},
+			},
+		},
+		"EndOfFile": {
+			in: "graph {\n\tA",
+			want: dot.Error{
+				LineNr:      2,
+				CharacterNr: 3,
+				Reason:      "expected '}' to close the '{' at 1:7 but reached the end of the file",
+				Category:    dot.InvalidSyntax,
+				Resume:      token.Position{Row: 2, Column: 3},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := dot.Parse([]byte(test.in))
+
+			var got dot.Error
+			require.Truef(t, errors.As(err, &got), "Parse(%q) should return a dot.Error instead got %v", test.in, err)
+			assert.EqualValuesf(t, got, test.want, "Parse(%q)", test.in)
+		})
+	}
+}
+
+func TestParserWithTokens(t *testing.T) {
+	in := `graph { // comment
+	A -- B }`
+
+	p, err := dot.NewParser(strings.NewReader(in), dot.WithTokens())
+	require.NoErrorf(t, err, "NewParser(%q)", in)
+	g, err := p.Parse()
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	want := []token.Token{
+		{Type: token.Graph, Literal: "graph", Start: token.Position{Row: 1, Column: 1}, End: token.Position{Row: 1, Column: 5}},
+		{Type: token.LeftBrace, Literal: "{", Start: token.Position{Row: 1, Column: 7}, End: token.Position{Row: 1, Column: 7}},
+		{Type: token.Comment, Literal: "// comment", Start: token.Position{Row: 1, Column: 9}, End: token.Position{Row: 1, Column: 18}},
+		{Type: token.Identifier, Literal: "A", Start: token.Position{Row: 2, Column: 2}, End: token.Position{Row: 2, Column: 2}},
+		{Type: token.UndirectedEgde, Literal: "--", Start: token.Position{Row: 2, Column: 4}, End: token.Position{Row: 2, Column: 5}},
+		{Type: token.Identifier, Literal: "B", Start: token.Position{Row: 2, Column: 7}, End: token.Position{Row: 2, Column: 7}},
+		{Type: token.RightBrace, Literal: "}", Start: token.Position{Row: 2, Column: 9}, End: token.Position{Row: 2, Column: 9}},
+	}
+	assert.EqualValuesf(t, p.Tokens(), want, "Tokens(%q)", in)
+
+	// navigate from the edge statement to the preceding comment
+	i, ok := token.Index(p.Tokens(), g.Stmts[0].Start())
+	require.Truef(t, ok, "Index(%s)", g.Stmts[0].Start())
+	assert.EqualValuesf(t, p.Tokens()[i-1].Type, token.Comment, "Tokens()[%d]", i-1)
+
+	t.Run("WithoutOption", func(t *testing.T) {
+		p, err := dot.NewParser(strings.NewReader(in))
+		require.NoErrorf(t, err, "NewParser(%q)", in)
+		_, err = p.Parse()
+		require.NoErrorf(t, err, "Parse(%q)", in)
+
+		assert.EqualValuesf(t, len(p.Tokens()), 0, "Tokens(%q)", in)
+	})
+}
+
+func TestParserWithGrammarHints(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		errMsg string
+	}{
+		"AttrStmtWithoutAttrList": {
+			in:     "graph { node }",
+			errMsg: `expected next token to be "[" but got "}" instead; in attr_stmt : (graph | node | edge) attr_list see https://graphviz.org/doc/info/lang.html`,
+		},
+		"AttributeWithoutValue": {
+			in:     "graph { A [b=] }",
+			errMsg: `expected next token to be "IDENTIFIER" but got "]" instead; in a_list : ID '=' ID [ (';' | ',') ] [ a_list ] see https://graphviz.org/doc/info/lang.html`,
+		},
+		"EdgeWithoutHead": {
+			in:     "graph { A -- }",
+			errMsg: `but got "}" instead; in edgeRHS : edgeop (node_id | subgraph) [ edgeRHS ]`,
+		},
+		"PortWithInvalidCompassPoint": {
+			in:     "graph { A:n:x }",
+			errMsg: `instead got "x"; in port : ':' ID [ ':' compass_pt ] | ':' compass_pt`,
+		},
+		"GraphWithoutBrace": {
+			in:     "graph A B",
+			errMsg: `but got "B" instead; in graph : [ strict ] (graph | digraph) [ ID ] '{' stmt_list '}'`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := dot.NewParser(strings.NewReader(test.in), dot.WithGrammarHints())
+			require.NoErrorf(t, err, "NewParser(%q)", test.in)
+
+			_, err = p.Parse()
+
+			require.NotNilf(t, err, "Parse(%q)", test.in)
+			assertx.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+
+	t.Run("WithoutOption", func(t *testing.T) {
+		in := "graph { node }"
+		p, err := dot.NewParser(strings.NewReader(in))
+		require.NoErrorf(t, err, "NewParser(%q)", in)
+
+		_, err = p.Parse()
+
+		require.NotNilf(t, err, "Parse(%q)", in)
+		assert.EqualValuesf(t, err.Error(), `1:14: expected next token to be "[" but got "}" instead`, "Parse(%q)", in)
+	})
+}
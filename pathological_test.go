@@ -0,0 +1,118 @@
+package dot_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/printer"
+)
+
+// pathological generates inputs that machine-generated graphs are known to contain. Each generator
+// takes a size n and returns a graph whose source grows linearly with n. Scanning, parsing and
+// printing them is linear in time and space with respect to n. The benchmarks below guard against
+// regressions:
+//
+//	go test -run '^$' -bench Pathological -benchmem
+//
+// The throughput in MB/s of each benchmark is about the same for n=1<<10 and n=1<<16 which is about
+// 1MB of input. Each allocates less than 32 bytes per input byte.
+var pathological = map[string]func(n int) string{
+	// AlternatingQuotes is a label made of escaped quotes alternating with other characters.
+	"AlternatingQuotes": func(n int) string {
+		// quoted strings are limited to maxUnquotedStringLen runes
+		n = min(n, 16000/3)
+		return `digraph { A [label="` + strings.Repeat(`a\"`, n) + `"] }`
+	},
+	// ManyQuotedIDs is a single line of quoted node IDs.
+	"ManyQuotedIDs": func(n int) string {
+		var out strings.Builder
+		out.WriteString("digraph {")
+		for i := range n {
+			fmt.Fprintf(&out, ` "%d"->"%d"`, i, i+1)
+		}
+		out.WriteString("}")
+		return out.String()
+	},
+	// LongLineComment is a line comment of n*16 bytes.
+	"LongLineComment": func(n int) string {
+		return "digraph {\n// " + strings.Repeat("0123456789abcdef", n) + "\nA\n}"
+	},
+	// LongMultiLineComment is a multi-line comment of n lines.
+	"LongMultiLineComment": func(n int) string {
+		return "digraph {\n/*" + strings.Repeat(" * a line\n", n) + "*/\nA\n}"
+	},
+	// ManyAttributes is a single attribute list holding n attributes.
+	"ManyAttributes": func(n int) string {
+		var out strings.Builder
+		out.WriteString("digraph { A [")
+		for i := range n {
+			if i > 0 {
+				out.WriteRune(',')
+			}
+			fmt.Fprintf(&out, `a%d="%d"`, i, i)
+		}
+		out.WriteString("] }")
+		return out.String()
+	},
+	// ManyAttributeLists are n attribute lists chained together.
+	"ManyAttributeLists": func(n int) string {
+		return "digraph { A " + strings.Repeat("[a=b]", n) + " }"
+	},
+	// LongEdgeChain is an edge statement connecting n nodes.
+	"LongEdgeChain": func(n int) string {
+		var out strings.Builder
+		out.WriteString("digraph { A")
+		for i := range n {
+			fmt.Fprintf(&out, " -> N%d", i)
+		}
+		out.WriteString(" }")
+		return out.String()
+	},
+}
+
+func TestPathological(t *testing.T) {
+	for name, generate := range pathological {
+		t.Run(name, func(t *testing.T) {
+			in := generate(1 << 12)
+
+			_, err := dot.Parse([]byte(in))
+			require.NoErrorf(t, err, "Parse(%s)", name)
+
+			p := printer.NewPrinter(strings.NewReader(in), io.Discard)
+			err = p.Print()
+			require.NoErrorf(t, err, "Print(%s)", name)
+		})
+	}
+}
+
+func BenchmarkPathological(b *testing.B) {
+	for name, generate := range pathological {
+		for _, n := range []int{1 << 10, 1 << 16} {
+			in := []byte(generate(n))
+
+			b.Run(fmt.Sprintf("Parse/%s/%d", name, n), func(b *testing.B) {
+				b.SetBytes(int64(len(in)))
+				for range b.N {
+					_, err := dot.Parse(in)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run(fmt.Sprintf("Print/%s/%d", name, n), func(b *testing.B) {
+				b.SetBytes(int64(len(in)))
+				for range b.N {
+					p := printer.NewPrinter(bytes.NewReader(in), io.Discard)
+					if err := p.Print(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Package plantuml converts dot graphs into [PlantUML component diagrams].
+//
+// [PlantUML component diagrams]: https://plantuml.com/component-diagram
+package plantuml
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// Write writes the graph g as a PlantUML component diagram to w. Nodes become components, edges
+// become relations and clusters become packages. Labels of nodes, edges and clusters are kept.
+//
+// Components are given an alias like n1 in the order their nodes first appear in the graph as
+// PlantUML aliases cannot contain arbitrary characters. A node belongs to the first cluster it
+// appears in. Edges connecting subgraphs are expanded into an edge per pair of nodes.
+func Write(w io.Writer, g ast.Graph) error {
+	c := newConverter()
+	c.convertStmts(g.Stmts, c.root)
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("@startuml\n")
+	c.writeCluster(bw, c.root, 0)
+	relation := " -- "
+	if g.Directed {
+		relation = " --> "
+	}
+	for _, e := range c.edges {
+		bw.WriteString(c.aliases[e.tail])
+		bw.WriteString(relation)
+		bw.WriteString(c.aliases[e.head])
+		if e.label != "" {
+			bw.WriteString(" : ")
+			bw.WriteString(escape(e.label))
+		}
+		bw.WriteRune('\n')
+	}
+	bw.WriteString("@enduml\n")
+	return bw.Flush()
+}
+
+type cluster struct {
+	label    string
+	clusters []*cluster
+}
+
+type edge struct {
+	tail, head, label string
+}
+
+type converter struct {
+	root       *cluster
+	nodes      []string // nodes in the order they first appear
+	aliases    map[string]string
+	labels     map[string]string
+	membership map[string]*cluster
+	edges      []edge
+}
+
+func newConverter() *converter {
+	return &converter{
+		root:       &cluster{},
+		aliases:    make(map[string]string),
+		labels:     make(map[string]string),
+		membership: make(map[string]*cluster),
+	}
+}
+
+func (c *converter) convertStmts(stmts []ast.Stmt, parent *cluster) {
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *ast.NodeStmt:
+			id := st.NodeID.ID.Unquoted()
+			c.addNode(id, parent)
+			if label, ok := attr(st.AttrList, "label"); ok {
+				c.labels[id] = label
+			}
+		case *ast.EdgeStmt:
+			c.convertEdge(st, parent)
+		case ast.Subgraph:
+			c.convertSubgraph(st, parent)
+		}
+	}
+}
+
+// convertSubgraph converts the subgraph s nested in parent and returns the IDs of the nodes it
+// contains.
+func (c *converter) convertSubgraph(s ast.Subgraph, parent *cluster) []string {
+	if s.ID != nil && strings.HasPrefix(s.ID.Unquoted(), "cluster") {
+		label := s.ID.Unquoted()
+		if l, ok := subgraphLabel(s); ok {
+			label = l
+		}
+		cl := &cluster{label: label}
+		parent.clusters = append(parent.clusters, cl)
+		parent = cl
+	}
+	c.convertStmts(s.Stmts, parent)
+	return subgraphNodes(s)
+}
+
+func (c *converter) convertEdge(e *ast.EdgeStmt, parent *cluster) {
+	label, _ := attr(e.AttrList, "label")
+
+	tails := c.convertOperand(e.Left, parent)
+	for rhs := &e.Right; rhs != nil; rhs = rhs.Next {
+		heads := c.convertOperand(rhs.Right, parent)
+		for _, tail := range tails {
+			for _, head := range heads {
+				c.edges = append(c.edges, edge{tail: tail, head: head, label: label})
+			}
+		}
+		tails = heads
+	}
+}
+
+func (c *converter) convertOperand(op ast.EdgeOperand, parent *cluster) []string {
+	switch op := op.(type) {
+	case ast.NodeID:
+		id := op.ID.Unquoted()
+		c.addNode(id, parent)
+		return []string{id}
+	case ast.Subgraph:
+		return c.convertSubgraph(op, parent)
+	}
+	return nil
+}
+
+func (c *converter) addNode(id string, parent *cluster) {
+	cl, ok := c.membership[id]
+	if !ok {
+		c.nodes = append(c.nodes, id)
+		c.aliases[id] = "n" + strconv.Itoa(len(c.nodes))
+		c.membership[id] = parent
+		return
+	}
+	if cl == c.root {
+		c.membership[id] = parent
+	}
+}
+
+func (c *converter) writeCluster(bw *bufio.Writer, cl *cluster, depth int) {
+	indent := strings.Repeat("\t", depth)
+	for _, id := range c.nodes {
+		if c.membership[id] != cl {
+			continue
+		}
+		label := id
+		if l, ok := c.labels[id]; ok {
+			label = l
+		}
+		bw.WriteString(indent)
+		bw.WriteString("component ")
+		bw.WriteString(quote(label))
+		bw.WriteString(" as ")
+		bw.WriteString(c.aliases[id])
+		bw.WriteRune('\n')
+	}
+	for _, child := range cl.clusters {
+		bw.WriteString(indent)
+		bw.WriteString("package ")
+		bw.WriteString(quote(child.label))
+		bw.WriteString(" {\n")
+		c.writeCluster(bw, child, depth+1)
+		bw.WriteString(indent)
+		bw.WriteString("}\n")
+	}
+}
+
+// subgraphNodes returns the IDs of all nodes contained in the subgraph s including the ones of
+// nested subgraphs.
+func subgraphNodes(s ast.Subgraph) []string {
+	var result []string
+	seen := make(map[string]bool)
+	ast.Inspect(s, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case ast.NodeID:
+			if id := n.ID.Unquoted(); !seen[id] {
+				seen[id] = true
+				result = append(result, id)
+			}
+			return false
+		case *ast.AttrList, ast.Attribute:
+			return false
+		}
+		return true
+	})
+	return result
+}
+
+// subgraphLabel returns the label of the subgraph s set via label=... or graph [label=...].
+func subgraphLabel(s ast.Subgraph) (string, bool) {
+	var label string
+	var found bool
+	for _, stmt := range s.Stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			if st.Name.Unquoted() == "label" {
+				label, found = st.Value.Unquoted(), true
+			}
+		case *ast.AttrStmt:
+			if token.Lookup(st.ID.Literal) == token.Graph {
+				if l, ok := attr(&st.AttrList, "label"); ok {
+					label, found = l, true
+				}
+			}
+		}
+	}
+	return label, found
+}
+
+// attr returns the value of the last attribute with given name in the attribute lists.
+func attr(attrList *ast.AttrList, name string) (string, bool) {
+	var value string
+	var found bool
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			if aList.Attribute.Name.Unquoted() == name {
+				value, found = aList.Attribute.Value.Unquoted(), true
+			}
+		}
+	}
+	return value, found
+}
+
+// quote quotes s for use as a PlantUML name. PlantUML has no escape for quotes inside of quoted
+// names so they are replaced by single quotes.
+func quote(s string) string {
+	return `"` + escape(strings.ReplaceAll(s, `"`, "'")) + `"`
+}
+
+// escape replaces newlines which PlantUML does not allow within a statement by the \n escape
+// sequence which PlantUML and Graphviz both render as a line break.
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
@@ -0,0 +1,120 @@
+package plantuml_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/plantuml"
+)
+
+func TestWrite(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"Empty": {
+			in: `digraph {}`,
+			want: `@startuml
+@enduml
+`,
+		},
+		"NodesAndEdges": {
+			in: `digraph {
+	A [label="Web Server"]
+	A -> "B" -> C [label="http"]
+	D
+}`,
+			want: `@startuml
+component "Web Server" as n1
+component "B" as n2
+component "C" as n3
+component "D" as n4
+n1 --> n2 : http
+n2 --> n3 : http
+@enduml
+`,
+		},
+		"UndirectedEdges": {
+			in: `graph {
+	A -- B
+}`,
+			want: `@startuml
+component "A" as n1
+component "B" as n2
+n1 -- n2
+@enduml
+`,
+		},
+		"ClustersBecomePackages": {
+			in: `digraph {
+	A -> B
+	subgraph cluster_backend {
+		label="Backend"
+		B
+		subgraph cluster_db {
+			C
+		}
+	}
+	subgraph cluster_other {
+		B
+	}
+}`,
+			want: `@startuml
+component "A" as n1
+package "Backend" {
+	component "B" as n2
+	package "cluster_db" {
+		component "C" as n3
+	}
+}
+package "cluster_other" {
+}
+n1 --> n2
+@enduml
+`,
+		},
+		"SubgraphOperandsAreExpanded": {
+			in: `digraph {
+	{A B} -> subgraph cluster_c { graph [label="C\nD"]; C; D }
+}`,
+			want: `@startuml
+component "A" as n1
+component "B" as n2
+package "C\nD" {
+	component "C" as n3
+	component "D" as n4
+}
+n1 --> n3
+n1 --> n4
+n2 --> n3
+n2 --> n4
+@enduml
+`,
+		},
+		"QuotesAndNewlinesInLabels": {
+			in: "digraph {\n\tA [label=\"say \\\"hi\\\"\"]\n\tA -> B [label=\"a\nb\"]\n}",
+			want: `@startuml
+component "say 'hi'" as n1
+component "B" as n2
+n1 --> n2 : a\nb
+@enduml
+`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			var out strings.Builder
+			err = plantuml.Write(&out, g)
+
+			require.NoErrorf(t, err, "Write(%q)", test.in)
+			assert.EqualValuesf(t, out.String(), test.want, "Write(%q)", test.in)
+		})
+	}
+}
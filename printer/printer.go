@@ -4,39 +4,335 @@ package printer
 import (
 	"fmt"
 	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/teleivo/dot"
 	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/internal/width"
 	"github.com/teleivo/dot/token"
 )
 
-// maxColumn is the max number of runes after which lines are broken up into multiple lines. Not
-// every dot construct can be broken up though.
-const maxColumn = 100
+// CanonicalVersion is the version of the canonical form of dot code. The canonical form is the
+// output of a [Printer] using the default options. It only changes if CanonicalVersion is
+// incremented so the canonical form can be hashed for caching across versions of this package.
+const CanonicalVersion = 4
+
+// defaultMaxColumn is the default max number of columns after which lines are broken up into
+// multiple lines. Not every dot construct can be broken up though.
+const defaultMaxColumn = 100
 
 // Printer formats dot code.
 type Printer struct {
-	r            io.Reader       // r reader to parse dot code from
-	w            io.Writer       // w writer to output formatted dot code to
-	row          int             // row is the current one-indexed row the printer is at i.e. how many newlines it has printed. 0 means nothing has been printed
-	column       int             // column is the current one-indexed column in terms of runes the printer is at. 0 means no rune has been printed on the current row
-	indentLevel  int             // indentLevel is the current level of indentation to be applied when indenting
-	prevToken    token.TokenType // prevToken is the type of the last printed token
-	prevPosition token.Position  // prevPosition is the position of the last printed token
-	newline      bool            // newline indicates a buffered newline that should be printed
-	commentIndex int             // commentIndex points to the next comment to be printed
-	comments     []ast.Comment   // comments lists all comments in the Graph to be printed
+	r             io.Reader               // r reader to parse dot code from
+	w             io.Writer               // w writer to output formatted dot code to
+	emptyBraces   EmptyStyle              // emptyBraces defines how an empty graph or subgraph is printed
+	emptyBrackets EmptyStyle              // emptyBrackets defines how an empty attribute list is printed
+	quoting       QuoteStyle              // quoting defines how identifiers are quoted
+	lineEnding    LineEnding              // lineEnding defines the line ending used when printing
+	eol           string                  // eol is the line ending written for every newline
+	crlf          bool                    // crlf indicates that "\r\n" is the dominant line ending of the input
+	provenance    *Provenance             // provenance is printed as a header comment if not nil
+	stripPrefixes []string                // stripPrefixes lists the prefixes of attribute names that are not printed
+	compact       bool                    // compact indicates that redundant node statements are not printed
+	finalNewline  bool                    // finalNewline indicates that the output ends with a line ending
+	indent        int                     // indent is the number of spaces per level of indentation. 0 indents using tabs
+	maxColumn     int                     // maxColumn is the max number of columns after which lines are broken up
+	attrLists     AttrListStyle           // attrLists defines when attribute lists are broken up into multiple lines
+	maxAttrs      int                     // maxAttrs is the max number of attributes kept on a single line. 0 means no limit
+	alignAttrs    bool                    // alignAttrs indicates that the '=' of attributes on multiple lines are aligned
+	semicolons    SemicolonStyle          // semicolons defines whether statements are terminated by a ';'
+	row           int                     // row is the current one-indexed row the printer is at i.e. how many newlines it has printed. 0 means nothing has been printed
+	column        int                     // column is the current one-indexed column in terms of display width the printer is at. 0 means no rune has been printed on the current row
+	indentLevel   int                     // indentLevel is the current level of indentation to be applied when indenting
+	prevToken     token.TokenType         // prevToken is the type of the last printed token
+	prevPosition  token.Position          // prevPosition is the position of the last printed token
+	newline       bool                    // newline indicates a buffered newline that should be printed
+	ownLine       bool                    // ownLine indicates that the last printed comment started on a new line
+	commented     token.TokenType         // commented is the type of the last token printed before a comment
+	commentIndex  int                     // commentIndex points to the next comment to be printed
+	comments      []ast.Comment           // comments lists all comments in the Graph to be printed
+	standalone    map[token.Position]bool // standalone marks the start of comments not attached to a statement
+	detached      bool                    // detached indicates that the last printed comment is standalone
+	stmtStart     token.Position          // stmtStart is the start of the statement being printed
+	breaks        *[]Break                // breaks records why lines are broken up if not nil
+}
+
+// EmptyStyle defines how empty graphs, subgraphs and attribute lists are printed. Empty graphs and
+// subgraphs are always printed on a single line unless they contain comments.
+type EmptyStyle int
+
+const (
+	EmptyCompact EmptyStyle = iota // EmptyCompact prints the delimiters without whitespace like {} or [].
+	EmptySpaced                    // EmptySpaced separates the delimiters by a single space like { } or [ ].
+)
+
+// QuoteStyle defines how identifiers are quoted.
+type QuoteStyle int
+
+const (
+	QuotePreserve QuoteStyle = iota // QuotePreserve prints identifiers quoted only if they were quoted.
+	QuoteMinimal                    // QuoteMinimal removes quotes from identifiers that are valid without them.
+	QuoteAlways                     // QuoteAlways quotes all identifiers except for keywords.
+)
+
+// LineEnding defines the line endings of the printed dot code.
+type LineEnding int
+
+const (
+	LineEndingLF       LineEnding = iota // LineEndingLF normalizes all line endings to "\n".
+	LineEndingCRLF                       // LineEndingCRLF normalizes all line endings to "\r\n".
+	LineEndingPreserve                   // LineEndingPreserve uses the dominant line ending of the input. Input without a dominant "\r\n" uses "\n".
+)
+
+// AttrListStyle defines when attribute lists are broken up into multiple lines.
+type AttrListStyle int
+
+const (
+	AttrListsMultiLine AttrListStyle = iota // AttrListsMultiLine puts every attribute on its own line if there is more than one.
+	AttrListsFit                            // AttrListsFit keeps attributes on the line of their statement separated by ", " if they fit into the max column.
+)
+
+// SemicolonStyle defines whether statements are terminated by a ';'.
+type SemicolonStyle int
+
+const (
+	SemicolonsNone   SemicolonStyle = iota // SemicolonsNone drops the optional ';' after statements.
+	SemicolonsAlways                       // SemicolonsAlways terminates every statement by a ';'.
+)
+
+// Construct is a dot construct the printer can break up into multiple lines.
+type Construct int
+
+const (
+	ConstructAttrList Construct = iota // ConstructAttrList is an attribute list like [color=red, style=filled].
+	ConstructID                        // ConstructID is a quoted or HTML identifier.
+	ConstructComment                   // ConstructComment is a comment.
+)
+
+var constructStrings = map[Construct]string{
+	ConstructAttrList: "attrlist",
+	ConstructID:       "id",
+	ConstructComment:  "comment",
+}
+
+func (c Construct) String() string {
+	return constructStrings[c]
+}
+
+// BreakReason is the reason a construct is broken up into multiple lines.
+type BreakReason int
+
+const (
+	BreakWidth   BreakReason = iota // BreakWidth breaks up a construct that would exceed the max column.
+	BreakForced                     // BreakForced breaks up a construct regardless of the max column like an attribute list in the [AttrListsMultiLine] style or a newline in a quoted identifier.
+	BreakComment                    // BreakComment breaks up a construct because it contains comments.
+)
+
+var breakReasonStrings = map[BreakReason]string{
+	BreakWidth:   "width",
+	BreakForced:  "forced",
+	BreakComment: "comment",
+}
+
+func (r BreakReason) String() string {
+	return breakReasonStrings[r]
 }
 
-func NewPrinter(r io.Reader, w io.Writer) *Printer {
-	return &Printer{
-		r: r,
-		w: w,
+// Break records why the printer broke up a construct into multiple lines. It helps understand why
+// the printer wrapped a line.
+type Break struct {
+	Output    token.Position // Output is the position of the last rune printed before the line break.
+	Source    token.Position // Source is the start of the construct in the source.
+	Construct Construct      // Construct is the kind of construct that is broken up.
+	Reason    BreakReason    // Reason is why the construct is broken up.
+}
+
+// Provenance describes the origin of generated dot code. It is printed as a header comment that
+// follows the [convention] used by Go so tools can detect generated dot code using
+// [ast.IsGenerated]. The printer records the [ast.Checksum] of the graph it prints in the header so
+// the lint package can warn about generated dot code that was edited by hand.
+//
+// [convention]: https://go.dev/s/generatedcode
+type Provenance struct {
+	Tool       string    // Tool is the name of the program that generated the dot code.
+	Version    string    // Version is the optional version of the tool.
+	SourceHash string    // SourceHash is the optional hash of the source the dot code was generated from.
+	Time       time.Time // Time is the optional time at which the dot code was generated.
+	Checksum   string    // Checksum is the checksum of the generated graph. The printer sets it to the checksum of the graph it prints.
+}
+
+// String returns the provenance as a comment like
+//
+//	// Code generated by dotgen v1.0.0 from source sha256:4f2a at 2024-08-01T10:00:00Z with checksum 5d6f9a1c2b3e4d7f. DO NOT EDIT.
+func (pv Provenance) String() string {
+	var out strings.Builder
+
+	out.WriteString("// Code generated by ")
+	out.WriteString(pv.Tool)
+	if pv.Version != "" {
+		out.WriteRune(' ')
+		out.WriteString(pv.Version)
+	}
+	if pv.SourceHash != "" {
+		out.WriteString(" from source ")
+		out.WriteString(pv.SourceHash)
+	}
+	if !pv.Time.IsZero() {
+		out.WriteString(" at ")
+		out.WriteString(pv.Time.UTC().Format(time.RFC3339))
+	}
+	if pv.Checksum != "" {
+		out.WriteString(" with checksum ")
+		out.WriteString(pv.Checksum)
+	}
+	out.WriteString(". DO NOT EDIT.")
+
+	return out.String()
+}
+
+// Option configures a [Printer].
+type Option func(*Printer)
+
+// WithEmptyBraces sets the style used for printing graphs and subgraphs without statements.
+func WithEmptyBraces(style EmptyStyle) Option {
+	return func(p *Printer) {
+		p.emptyBraces = style
+	}
+}
+
+// WithEmptyBrackets sets the style used for printing attribute lists without attributes.
+func WithEmptyBrackets(style EmptyStyle) Option {
+	return func(p *Printer) {
+		p.emptyBrackets = style
 	}
 }
 
+// WithQuoting sets the style used for quoting identifiers. The literal of an identifier is never
+// changed apart from adding or removing its enclosing quotes.
+func WithQuoting(style QuoteStyle) Option {
+	return func(p *Printer) {
+		p.quoting = style
+	}
+}
+
+// WithLineEnding sets the line ending used when printing.
+func WithLineEnding(lineEnding LineEnding) Option {
+	return func(p *Printer) {
+		p.lineEnding = lineEnding
+	}
+}
+
+// WithProvenance prints given provenance as a header comment marking the dot code as generated.
+// Generated comments of the input are replaced by it.
+func WithProvenance(provenance Provenance) Option {
+	return func(p *Printer) {
+		p.provenance = &provenance
+	}
+}
+
+// WithIndent indents using given number of spaces per level of indentation instead of a tab. An
+// indent smaller than 1 indents using tabs.
+func WithIndent(spaces int) Option {
+	return func(p *Printer) {
+		p.indent = max(spaces, 0)
+	}
+}
+
+// WithMaxColumn sets the max number of columns after which lines are broken up into multiple lines.
+// Comments, quoted identifiers and attribute lists are broken up while other constructs can exceed
+// it. Columns are counted by display width so East Asian wide characters and emoji count as two
+// columns while combining marks count as none. A column smaller than 1 keeps the default of 100.
+func WithMaxColumn(column int) Option {
+	return func(p *Printer) {
+		if column > 0 {
+			p.maxColumn = column
+		}
+	}
+}
+
+// WithAttrLists sets the style used for breaking up attribute lists into multiple lines.
+func WithAttrLists(style AttrListStyle) Option {
+	return func(p *Printer) {
+		p.attrLists = style
+	}
+}
+
+// WithMaxAttrsPerLine breaks up attribute lists with more than given number of attributes into
+// one attribute per line even if they fit into the max column. It only affects the style
+// [AttrListsFit] as [AttrListsMultiLine] breaks up every list with more than one attribute. A
+// number less than 1 does not limit the number of attributes.
+func WithMaxAttrsPerLine(n int) Option {
+	return func(p *Printer) {
+		p.maxAttrs = max(n, 0)
+	}
+}
+
+// WithAlignedAttrs aligns the '=' of attributes in attribute lists broken up into multiple lines by
+// padding the attribute names with spaces.
+func WithAlignedAttrs() Option {
+	return func(p *Printer) {
+		p.alignAttrs = true
+	}
+}
+
+// WithSemicolons sets the style used for terminating statements.
+func WithSemicolons(style SemicolonStyle) Option {
+	return func(p *Printer) {
+		p.semicolons = style
+	}
+}
+
+// WithStripAttrs removes all attributes with a name starting with given prefix before printing.
+// Use it to remove attributes that are kept for tooling like x-owner=alice from the output
+// passed to strict consumers. Refer to [ast.StripAttrs] for details.
+func WithStripAttrs(prefix string) Option {
+	return func(p *Printer) {
+		p.stripPrefixes = append(p.stripPrefixes, prefix)
+	}
+}
+
+// WithBreaks records why lines are broken up into breaks. Breaks are appended in the order they
+// occur in the output.
+func WithBreaks(breaks *[]Break) Option {
+	return func(p *Printer) {
+		p.breaks = breaks
+	}
+}
+
+// WithCompact removes redundant node statements like A of nodes that are also used in edges before
+// printing. Refer to [ast.FoldNodeStmts] for details.
+func WithCompact() Option {
+	return func(p *Printer) {
+		p.compact = true
+	}
+}
+
+// WithFinalNewline ends the output with a line ending like gofmt does. Files are expected to end
+// with one by POSIX and most editors.
+func WithFinalNewline() Option {
+	return func(p *Printer) {
+		p.finalNewline = true
+	}
+}
+
+func NewPrinter(r io.Reader, w io.Writer, opts ...Option) *Printer {
+	p := &Printer{
+		r:         r,
+		w:         w,
+		maxColumn: defaultMaxColumn,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
 func (pr *Printer) Print() error {
-	ps, err := dot.NewParser(pr.r)
+	lc := &lineEndingCounter{r: pr.r}
+	ps, err := dot.NewParser(lc)
 	if err != nil {
 		return err
 	}
@@ -45,17 +341,152 @@ func (pr *Printer) Print() error {
 	if err != nil {
 		return err
 	}
+	if lc.crlf > lc.lf {
+		pr.crlf = true
+	}
+	return pr.printSource(g)
+}
+
+// elision is the comment printed in place of the statements left out by [Preview].
+const elision = "// ..."
+
+// Preview formats the header and the first n statements of the graph read from r to w. Statements
+// are counted at the top level of the graph so a subgraph counts as a single statement. The
+// statements that are left out are replaced by the comment // ... before the closing brace of the
+// graph. Only the statements that are printed and the statement following them are parsed which
+// makes it suitable for showing a snippet of a large file. Comments are not printed.
+func Preview(r io.Reader, w io.Writer, n int, opts ...Option) error {
+	sp, err := dot.NewStreamParser(r)
+	if err != nil {
+		return err
+	}
+
+	var stmts []ast.Stmt
+	var truncated bool
+	for {
+		stmt, err := sp.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if len(stmts) == n {
+			truncated = true
+			break
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	g := sp.Graph()
+	if g.GraphStart.Row == 0 { // no graph to preview
+		return nil
+	}
+	g.Stmts = stmts
+	if truncated {
+		// place the elision on its own line after the last statement printed
+		last := g.LeftBrace
+		if len(stmts) > 0 {
+			last = stmts[len(stmts)-1].End()
+		}
+		g.Comments = []ast.Comment{{
+			Text:     elision,
+			StartPos: token.Position{Row: last.Row + 1, Column: 1},
+			EndPos:   token.Position{Row: last.Row + 1, Column: len(elision)},
+		}}
+		g.RightBrace = token.Position{Row: last.Row + 2, Column: 1}
+	}
+
+	return NewPrinter(nil, w, opts...).printSource(g)
+}
+
+// Fprint formats the graph g to w. Unlike [Printer.Print] the graph is not parsed from source so
+// it can be built in code. Positions are only used to place comments. An error is returned if
+// subgraphs are nested deeper than [dot.MaxDepth] like the parser does.
+func Fprint(w io.Writer, g ast.Graph, opts ...Option) error {
+	return NewPrinter(nil, w, opts...).printSource(g)
+}
+
+// printSource prints the parsed graph together with its comments.
+func (pr *Printer) printSource(g ast.Graph) error {
+	// the AST might not come from the parser so check its depth before walking it recursively
+	if depth(g) > dot.MaxDepth {
+		return fmt.Errorf("subgraphs are nested deeper than the max depth of %d", dot.MaxDepth)
+	}
+
 	pr.comments = g.Comments
+	for _, prefix := range pr.stripPrefixes {
+		ast.StripAttrs(&g, prefix)
+	}
+	if pr.compact {
+		ast.FoldNodeStmts(&g)
+	}
+	pr.standalone = make(map[token.Position]bool)
+	for _, c := range ast.NewCommentMap(g).Standalone {
+		pr.standalone[c.StartPos] = true
+	}
 
-	err = pr.printNode(g)
+	pr.eol = "\n"
+	if pr.lineEnding == LineEndingCRLF || (pr.lineEnding == LineEndingPreserve && pr.crlf) {
+		pr.eol = "\r\n"
+	}
+
+	if pr.provenance != nil {
+		pr.comments = slices.DeleteFunc(pr.comments, func(c ast.Comment) bool {
+			return c.StartPos.Before(g.Start()) && ast.IsGeneratedComment(c)
+		})
+		provenance := *pr.provenance
+		provenance.Checksum = ast.Checksum(g)
+		pr.printString(provenance.String())
+		pr.forceNewline()
+	}
+
+	err := pr.printNode(g)
 	if err != nil {
 		return err
 	}
 	pr.printRemainingComments()
+	if pr.finalNewline {
+		pr.forceNewline()
+	}
 
 	return nil
 }
 
+// depth returns the max number of nested subgraphs of the graph. It walks the graph using a stack
+// instead of recursion so it is safe on graphs nested arbitrarily deep.
+func depth(g ast.Graph) int {
+	type block struct {
+		stmts []ast.Stmt
+		depth int
+	}
+
+	var result int
+	stack := []block{{stmts: g.Stmts}}
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		result = max(result, cur.depth)
+
+		push := func(operand ast.EdgeOperand) {
+			if subgraph, ok := operand.(ast.Subgraph); ok {
+				stack = append(stack, block{stmts: subgraph.Stmts, depth: cur.depth + 1})
+			}
+		}
+		for _, stmt := range cur.stmts {
+			switch st := stmt.(type) {
+			case ast.Subgraph:
+				push(st)
+			case *ast.EdgeStmt:
+				push(st.Left)
+				for rhs := &st.Right; rhs != nil; rhs = rhs.Next {
+					push(rhs.Right)
+				}
+			}
+		}
+	}
+	return result
+}
+
 func (p *Printer) printNode(node ast.Node) error {
 	switch n := node.(type) {
 	case ast.Graph:
@@ -85,17 +516,32 @@ func (p *Printer) printGraph(graph ast.Graph) error {
 		p.printSpace()
 	}
 
-	p.printToken(token.LeftBrace, graph.LeftBrace)
+	return p.printBlock(graph.LeftBrace, graph.Stmts, graph.RightBrace)
+}
+
+// printBlock prints the statements enclosed in braces of a graph or subgraph. A block without
+// statements and comments is printed on a single line.
+func (p *Printer) printBlock(leftBrace token.Position, stmts []ast.Stmt, rightBrace token.Position) error {
+	p.printToken(token.LeftBrace, leftBrace)
+
+	if len(stmts) == 0 && !p.hasCommentsBefore(rightBrace) {
+		if p.emptyBraces == EmptySpaced {
+			p.printSpace()
+		}
+		p.printToken(token.RightBrace, rightBrace)
+		return nil
+	}
+
 	p.increaseIndentation()
 
-	err := p.printStmts(graph.Stmts)
+	err := p.printStmts(stmts)
 	if err != nil {
 		return err
 	}
 
 	p.decreaseIndentation()
 	p.printNewline()
-	p.printToken(token.RightBrace, graph.RightBrace)
+	p.printToken(token.RightBrace, rightBrace)
 	return nil
 }
 
@@ -109,14 +555,24 @@ func (p *Printer) printStmts(stmts []ast.Stmt) error {
 	return nil
 }
 
+// printID prints the identifier quoted according to the [QuoteStyle] of the printer.
 func (p *Printer) printID(id ast.ID) error {
+	return p.printIDAs(id, p.quote(id))
+}
+
+// printIDAs prints the literal in place of the identifier.
+func (p *Printer) printIDAs(id ast.ID, literal string) error {
 	p.printComments(id.StartPos)
 
 	p.prevToken = token.Identifier
 	p.prevPosition = id.EndPos
 
-	if id.Literal[0] != '"' { // print unquoted identifiers as is
-		p.print(id)
+	if id.IsHTML() {
+		p.printHTML(id, literal)
+		return nil
+	}
+	if literal[0] != '"' { // print unquoted identifiers as is
+		p.printString(literal)
 		return nil
 	}
 
@@ -125,43 +581,83 @@ func (p *Printer) printID(id ast.ID) error {
 
 	const offset = 1 // as opening " was printed
 	start, end := offset, offset
-	runeCount := 0
-	for curRuneIdx, curRune := range id.Literal[offset:] {
+	wordWidth := 0
+	for curRuneIdx, curRune := range literal[offset:] {
 		if curRune == '\n' {
 			// TODO why do I need the +1, the newline should be printed by forceNewline
-			p.printStringWithoutIndent(id.Literal[start : curRuneIdx+1])
+			end := curRuneIdx + 1
+			if end > start && literal[end-1] == '\r' { // the line ending is printed by forceNewline
+				end--
+			}
+			p.printStringWithoutIndent(literal[start:end])
+			p.recordBreak(ConstructID, BreakForced, id.StartPos)
 			p.forceNewline()
 			start = curRuneIdx + offset + 1
 			end = start
-			runeCount = 0
-		} else if isWhitespace(curRune) {
-			if p.column+runeCount > maxColumn {
+			wordWidth = 0
+		} else if curRune != '\r' && isWhitespace(curRune) {
+			if p.column+wordWidth > p.maxColumn {
 				// standard C convention of a backslash immediately preceding a newline character
 				p.printRuneWithoutIndent('\\')
+				p.recordBreak(ConstructID, BreakWidth, id.StartPos)
 				p.forceNewline() // immediately print the newline as there cannot be any interspersed comment
 			}
-			p.printStringWithoutIndent(id.Literal[start : curRuneIdx+1])
+			p.printStringWithoutIndent(literal[start : curRuneIdx+1])
 			start = curRuneIdx + offset
 			end = start
-			runeCount = 0
+			wordWidth = 0
 		}
-		runeCount++
+		wordWidth += width.Rune(curRune)
 	}
 
 	// TODO scrutinize this, not sure if there is a flaw in here
-	if end < len(id.Literal) {
-		if p.column+runeCount > maxColumn {
+	if end < len(literal) {
+		if p.column+wordWidth > p.maxColumn {
 			// standard C convention of a backslash immediately preceding a newline character
 			p.printRuneWithoutIndent('\\')
+			p.recordBreak(ConstructID, BreakWidth, id.StartPos)
 			p.forceNewline() // immediately print the newline as there cannot be any interspersed comment
 		}
-		p.printStringWithoutIndent(id.Literal[start:])
+		p.printStringWithoutIndent(literal[start:])
 	}
 
 	return nil
 }
 
+// printHTML prints the HTML string keeping its lines as is. Line endings are printed by
+// forceNewline so they match the configured line ending.
+func (p *Printer) printHTML(id ast.ID, literal string) {
+	for i, line := range strings.Split(literal, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if i == 0 {
+			p.printString(line)
+			continue
+		}
+		p.recordBreak(ConstructID, BreakForced, id.StartPos)
+		p.forceNewline()
+		p.printStringWithoutIndent(line)
+	}
+}
+
+// quote returns the literal of given identifier with its quotes added or removed according to the
+// [QuoteStyle] of the printer.
+func (p *Printer) quote(id ast.ID) string {
+	literal := id.Literal
+	switch p.quoting {
+	case QuoteMinimal:
+		if id.IsQuoted() && len(literal) > 2 && ast.CanUnquote(literal[1:len(literal)-1]) {
+			return literal[1 : len(literal)-1]
+		}
+	case QuoteAlways:
+		if !id.IsQuoted() && !id.IsHTML() {
+			return `"` + literal + `"`
+		}
+	}
+	return literal
+}
+
 func (p *Printer) printStmt(stmt ast.Stmt) error {
+	p.stmtStart = stmt.Start()
 	var err error
 	switch st := stmt.(type) {
 	case *ast.NodeStmt:
@@ -172,11 +668,14 @@ func (p *Printer) printStmt(stmt ast.Stmt) error {
 		err = p.printAttrStmt(st)
 	case ast.Attribute:
 		p.printNewline()
-		err = p.printAttribute(st)
+		err = p.printAttribute(st, 0)
 	case ast.Subgraph:
 		p.printNewline()
 		err = p.printSubgraph(st)
 	}
+	if err == nil && p.semicolons == SemicolonsAlways {
+		p.printToken(token.Semicolon, stmt.End())
+	}
 	return err
 }
 
@@ -219,28 +718,66 @@ func (p *Printer) printAttrList(attrList *ast.AttrList) error {
 		return nil
 	}
 
-	// TODO that is not 100% true as an attrList can solely be a chain of []
-	var hasMultipleAttrs bool
-	if attrList.Next != nil {
-		hasMultipleAttrs = true
+	// multiple attribute lists like [color=blue] [] [style=filled] are merged into one so only the
+	// total number of attributes decides on the layout
+	var attrCount int
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			attrCount++
+		}
 	}
 
 	p.printSpace()
 	p.printToken(token.LeftBracket, attrList.LeftBracket)
+
+	if attrCount == 0 {
+		if p.emptyBrackets == EmptySpaced {
+			p.printSpace()
+		}
+		p.printToken(token.RightBracket, attrList.End())
+		return nil
+	}
+
+	// a single attribute stays on the same line as the brackets
+	isMultiLine := attrCount > 1
+	reason := BreakForced
+	if isMultiLine && p.attrLists == AttrListsFit && (p.maxAttrs == 0 || attrCount <= p.maxAttrs) {
+		var fits bool
+		fits, reason = p.fitsOnLine(attrList, attrCount)
+		isMultiLine = !fits
+	}
+	if isMultiLine {
+		p.recordBreak(ConstructAttrList, reason, attrList.LeftBracket)
+	}
+	var nameWidth int
+	if isMultiLine && p.alignAttrs {
+		for cur := attrList; cur != nil; cur = cur.Next {
+			for aList := cur.AList; aList != nil; aList = aList.Next {
+				nameWidth = max(nameWidth, width.String(p.quote(aList.Attribute.Name)))
+			}
+		}
+	}
 	p.increaseIndentation()
 
+	first := true
 	for cur := attrList; cur != nil; cur = cur.Next {
-		split, err := p.printAList(cur.AList, hasMultipleAttrs)
-		if err != nil {
-			return err
-		}
-		if split {
-			hasMultipleAttrs = true
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			if isMultiLine {
+				p.printNewline()
+			} else if !first {
+				p.printRune(',')
+				p.printSpace()
+			}
+			err := p.printAttribute(aList.Attribute, nameWidth)
+			if err != nil {
+				return err
+			}
+			first = false
 		}
 	}
 
 	p.decreaseIndentation()
-	if hasMultipleAttrs {
+	if isMultiLine {
 		p.printNewline()
 	}
 	// TODO if I remember correctly I am merging A [color=blue] [style=filled] into A [color=blue,
@@ -250,25 +787,25 @@ func (p *Printer) printAttrList(attrList *ast.AttrList) error {
 	return nil
 }
 
-func (p *Printer) printAList(aList *ast.AList, hasMultipleAttrs bool) (bool, error) {
-	for cur := aList; cur != nil; cur = cur.Next {
-		if aList.Next != nil {
-			hasMultipleAttrs = true
-		}
+// fitsOnLine reports whether the attributes of the list fit on the current line in the form
+// [a=b, c=d] without exceeding the max column. Lists containing comments or multi-line identifiers
+// never fit. The reason for breaking up the list is returned if it does not fit.
+func (p *Printer) fitsOnLine(attrList *ast.AttrList, attrCount int) (bool, BreakReason) {
+	if p.hasCommentsBefore(attrList.End()) {
+		return false, BreakComment
+	}
 
-		if hasMultipleAttrs {
-			p.printNewline()
-		}
-		err := p.printAttribute(cur.Attribute)
-		if err != nil {
-			return hasMultipleAttrs, err
-		}
-		if !hasMultipleAttrs && cur.Next != nil {
-			p.printSpace()
+	columns := p.column + len(" [") + len(", ")*(attrCount-1) + len("]")
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			name, value := p.quote(aList.Attribute.Name), p.quote(aList.Attribute.Value)
+			if strings.ContainsRune(name, '\n') || strings.ContainsRune(value, '\n') {
+				return false, BreakForced
+			}
+			columns += width.String(name) + len("=") + width.String(value)
 		}
 	}
-
-	return hasMultipleAttrs, nil
+	return columns <= p.maxColumn, BreakWidth
 }
 
 func (p *Printer) printEdgeStmt(edgeStmt *ast.EdgeStmt) error {
@@ -322,18 +859,23 @@ func (p *Printer) printEdgeOperand(edgeOperand ast.EdgeOperand) error {
 
 func (p *Printer) printAttrStmt(attrStmt *ast.AttrStmt) error {
 	p.printNewline()
-	err := p.printID(attrStmt.ID)
+	// the ID is one of the keywords graph, node or edge which must not be quoted
+	err := p.printIDAs(attrStmt.ID, attrStmt.ID.Literal)
 	if err != nil {
 		return err
 	}
 	return p.printAttrList(&attrStmt.AttrList)
 }
 
-func (p *Printer) printAttribute(attribute ast.Attribute) error {
+// printAttribute prints the attribute padding its name with spaces up to given width.
+func (p *Printer) printAttribute(attribute ast.Attribute, nameWidth int) error {
 	err := p.printID(attribute.Name)
 	if err != nil {
 		return err
 	}
+	for range nameWidth - width.String(p.quote(attribute.Name)) {
+		p.printSpace()
+	}
 	// TODO fix this using the correct position of the '=' which I need to know the position of equal
 	// to support a comment before it. Add the position info to the ast
 	p.printToken(token.Equal, attribute.Name.EndPos)
@@ -352,54 +894,60 @@ func (p *Printer) printSubgraph(subraph ast.Subgraph) error {
 		p.printSpace()
 	}
 
-	p.printToken(token.LeftBrace, subraph.LeftBrace)
-	p.increaseIndentation()
-
-	err := p.printStmts(subraph.Stmts)
-	if err != nil {
-		return err
-	}
-
-	p.decreaseIndentation()
-	p.printNewline()
-	p.printToken(token.RightBrace, subraph.RightBrace)
-	return nil
+	return p.printBlock(subraph.LeftBrace, subraph.Stmts, subraph.RightBrace)
 }
 
 func (p *Printer) printComment(comment ast.Comment) error {
-	text := comment.Text
-	// discard markers
-	if text[0] == '#' {
-		text = text[1:]
-	} else if text[1] == '/' {
-		text = text[2:]
-	} else { // discard multi-line markers
-		text = text[2 : len(text)-2]
-	}
+	text := commentText(comment)
 
 	// put a comment only on a new line if that was the intent! a comment starting on the same
 	// line as the previous token is seen as the intent of keeping them together
 	putOnNewLine := p.prevPosition.Row > 0 && p.prevPosition.Row != comment.StartPos.Row
+	// separate sections by exactly one blank line unless the section starts the block or continues
+	// a comment on its own line like a header framed by lines of dashes
+	ownLine := putOnNewLine || p.prevPosition.Row == 0
+	prevToken := p.prevToken
+	if prevToken == token.Comment && !p.ownLine { // look past comments trailing a token
+		prevToken = p.commented
+	}
+	if putOnNewLine && isSectionHeader(comment) && prevToken != token.LeftBrace && prevToken != token.Comment {
+		p.forceNewline()
+	}
+	// keep the blank line separating standalone comments from the comments of the next statement so
+	// formatting does not attach them to it. Section headers are kept on top of their section.
+	isHeader := isSectionHeader(comment)
+	standalone := p.standalone[comment.StartPos] && !isHeader
+	if putOnNewLine && !isHeader && !standalone && p.prevToken == token.Comment && p.ownLine && p.detached {
+		p.forceNewline()
+	}
+	// breaking up a comment marking the code as generated would remove the marker
+	maxColumn := p.maxColumn
+	if ast.IsGeneratedComment(comment) {
+		maxColumn = math.MaxInt
+	}
 	isFirstWord := true
 	var inWord bool
-	var start, runeCount int
+	var start, wordWidth int
 	for i, r := range text {
 		if !inWord && !isWhitespace(r) {
 			inWord = true
 			start = i
-			runeCount = 1
+			wordWidth = width.Rune(r)
 		} else if inWord && !isWhitespace(r) {
-			runeCount++
+			wordWidth += width.Rune(r)
 		} else if inWord && isWhitespace(r) { // word boundary
-			col := p.column + 1 + runeCount // 1 for the space separating words
+			col := p.column + 1 + wordWidth // 1 for the space separating words
 
 			// breakup long comment or start new one with the intent to be on a new line
 			if col > maxColumn || (isFirstWord && putOnNewLine) {
+				if !isFirstWord || !putOnNewLine {
+					p.recordBreak(ConstructComment, BreakWidth, comment.StartPos)
+				}
 				p.forceNewline()
 			}
 			// separate comment from previous token on the same line except for comments at the start of a
-			// file
-			if isFirstWord && !putOnNewLine && p.row > 0 {
+			// line
+			if isFirstWord && !putOnNewLine && p.column > 0 {
 				p.printSpace()
 			}
 			// start comment
@@ -420,15 +968,18 @@ func (p *Printer) printComment(comment ast.Comment) error {
 	}
 
 	if inWord {
-		col := p.column + 1 + runeCount // 1 for the space separating words
+		col := p.column + 1 + wordWidth // 1 for the space separating words
 
 		// breakup long comment or start new one with the intent to be on a new line
 		if col > maxColumn || (isFirstWord && putOnNewLine) {
+			if !isFirstWord || !putOnNewLine {
+				p.recordBreak(ConstructComment, BreakWidth, comment.StartPos)
+			}
 			p.forceNewline()
 		}
 		// separate comment from previous token on the same line except for comments at the start of a
-		// file
-		if isFirstWord && !putOnNewLine && p.row > 0 {
+		// line
+		if isFirstWord && !putOnNewLine && p.column > 0 {
 			p.printSpace()
 		}
 		// start comment
@@ -444,14 +995,78 @@ func (p *Printer) printComment(comment ast.Comment) error {
 		}
 	}
 
+	if p.prevToken != token.Comment {
+		p.commented = p.prevToken
+	}
 	p.prevToken = token.Comment
 	p.prevPosition = comment.EndPos
+	p.ownLine = ownLine
+	p.detached = standalone
 
 	return nil
 }
 
+// commentText returns the text of the comment without its markers.
+func commentText(comment ast.Comment) string {
+	text := comment.Text
+	if text[0] == '#' {
+		return text[1:]
+	} else if text[1] == '/' {
+		return text[2:]
+	}
+	// discard multi-line markers
+	return text[2 : len(text)-2]
+}
+
+// isSectionHeader determines if the comment is a section header. Section headers are lines of
+// dashes or equal signs like "// -----" or names framed by them like "// --- nodes ---".
+func isSectionHeader(comment ast.Comment) bool {
+	fields := strings.Fields(commentText(comment))
+	if len(fields) == 0 {
+		return false
+	}
+	isRule := func(s string) bool {
+		return len(s) >= 3 && (strings.Trim(s, "-") == "" || strings.Trim(s, "=") == "")
+	}
+	return isRule(fields[0]) && isRule(fields[len(fields)-1])
+}
+
+// isBlankComment determines if the comment only consists of whitespace. Such comments are not
+// printed.
+func isBlankComment(comment ast.Comment) bool {
+	for _, r := range commentText(comment) {
+		if !isWhitespace(r) {
+			return false
+		}
+	}
+	return true
+}
+
 func isWhitespace(r rune) bool {
-	return r == ' ' || r == '\t' || r == '\n'
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// lineEndingCounter counts the line endings in the dot code read through it.
+type lineEndingCounter struct {
+	r    io.Reader
+	prev byte // prev is the last byte read
+	lf   int  // lf counts the line endings consisting of only '\n'
+	crlf int  // crlf counts the line endings "\r\n"
+}
+
+func (lc *lineEndingCounter) Read(b []byte) (int, error) {
+	n, err := lc.r.Read(b)
+	for _, c := range b[:n] {
+		if c == '\n' {
+			if lc.prev == '\r' {
+				lc.crlf++
+			} else {
+				lc.lf++
+			}
+		}
+		lc.prev = c
+	}
+	return n, err
 }
 
 func (p *Printer) increaseIndentation() {
@@ -490,9 +1105,13 @@ func (p *Printer) printSpace() {
 
 // TODO should this be aware of r being a newline?
 func (p *Printer) printRune(r rune) {
-	for p.column < p.indentLevel {
-		fmt.Fprintf(p.w, "%c", '\t')
-		p.column++
+	unit, unitWidth := "\t", 1
+	if p.indent > 0 {
+		unit, unitWidth = strings.Repeat(" ", p.indent), p.indent
+	}
+	for p.column < p.indentLevel*unitWidth {
+		fmt.Fprint(p.w, unit)
+		p.column += unitWidth
 	}
 
 	p.printRuneWithoutIndent(r)
@@ -503,7 +1122,7 @@ func (p *Printer) printRuneWithoutIndent(a rune) {
 	if p.row == 0 {
 		p.row = 1
 	}
-	p.column++
+	p.column += width.Rune(a)
 }
 
 func (p *Printer) printToken(tokenType token.TokenType, pos token.Position) {
@@ -516,6 +1135,17 @@ func (p *Printer) printToken(tokenType token.TokenType, pos token.Position) {
 	p.prevPosition = withColumnOffset(pos, len(tok))
 }
 
+// hasCommentsBefore reports whether there are comments that are not yet printed preceding the
+// given position. Blank comments are ignored as they are not printed.
+func (p *Printer) hasCommentsBefore(pos token.Position) bool {
+	for i := p.commentIndex; i < len(p.comments) && p.comments[i].StartPos.Before(pos); i++ {
+		if !isBlankComment(p.comments[i]) {
+			return true
+		}
+	}
+	return false
+}
+
 // printComments print all comments preceding the next token to be printed.
 func (p *Printer) printComments(nextTokenPos token.Position) {
 	// TODO replace all print with positional print funcs
@@ -526,13 +1156,19 @@ func (p *Printer) printComments(nextTokenPos token.Position) {
 	for ; err == nil && p.commentIndex < len(p.comments) && p.comments[p.commentIndex].StartPos.Before(nextTokenPos); p.commentIndex++ {
 		comment := p.comments[p.commentIndex]
 		err = p.printComment(comment)
-		printed = true
+		if !isBlankComment(comment) {
+			printed = true
+		}
 	}
 
 	// TODO I might not want the newline once I bring block comments back
 	if printed || p.newline {
 		p.printNewline()
 		p.flushNewline()
+		// keep standalone comments separated from the statement that follows
+		if printed && p.detached && nextTokenPos == p.stmtStart {
+			p.forceNewline()
+		}
 	} else {
 		p.newline = false
 	}
@@ -566,12 +1202,26 @@ func (p *Printer) flushNewline() bool {
 // forceNewline immediately writes a newline to [Printer.w] and clears a newline queued by
 // [Printer.printNewline].
 func (p *Printer) forceNewline() {
-	fmt.Fprintln(p.w)
+	fmt.Fprint(p.w, p.eol)
 	p.column = 0
 	p.row++
 	p.newline = false
 }
 
+// recordBreak records why the construct starting at given source position is broken up after the
+// last printed rune.
+func (p *Printer) recordBreak(construct Construct, reason BreakReason, source token.Position) {
+	if p.breaks == nil {
+		return
+	}
+	*p.breaks = append(*p.breaks, Break{
+		Output:    token.Position{Row: max(p.row, 1), Column: p.column},
+		Source:    source,
+		Construct: construct,
+		Reason:    reason,
+	})
+}
+
 // withColumnOffset returns a new position with the added offset to the given positions column.
 func withColumnOffset(pos token.Position, columnOffset int) token.Position {
 	return token.Position{
@@ -2,16 +2,26 @@ package printer_test
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/teleivo/assertive/assert"
 	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/graph"
 	"github.com/teleivo/dot/printer"
+	"github.com/teleivo/dot/token"
 )
 
 func TestPrint(t *testing.T) {
 	tests := map[string]struct {
 		in   string
+		opts []printer.Option
 		want string
 	}{
 		"GraphEmpty": {
@@ -20,14 +30,12 @@ func TestPrint(t *testing.T) {
 
 
 			`,
-			want: `strict graph {
-}`,
+			want: `strict graph {}`,
 		},
 		"GraphWithID": {
 			in: `strict graph 
 					"galaxy"     {}`,
-			want: `strict graph "galaxy" {
-}`,
+			want: `strict graph "galaxy" {}`,
 		},
 		"NodeWithUnquotedIDPastMaxColumn": {
 			in: `graph {
@@ -46,10 +54,10 @@ bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb
 		// World in Chinese each rune is 3 bytes long 世界
 		"NodeWithQuotedIDOfMaxColumn": {
 			in: `graph {
-	"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa世界aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa世界aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 }`,
 			want: `graph {
-	"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa世界aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa世界aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
 }`,
 		},
 		"NodeStmtWithAttributeIDPastMaxColumn": {
@@ -84,6 +92,316 @@ bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb
 		// 		l."]
 		// }`,
 		// 		},
+		"GraphEmptyWithSpacedBraces": {
+			in:   `graph {}`,
+			opts: []printer.Option{printer.WithEmptyBraces(printer.EmptySpaced)},
+			want: `graph { }`,
+		},
+		"GraphEmptyWithComment": {
+			in: `graph { // nothing to see
+}`,
+			want: `graph { // nothing to see
+}`,
+		},
+		"SubgraphsEmpty": {
+			in: `graph {
+	subgraph {
+	}
+	A -- { }
+	subgraph "empty" {
+	}
+}`,
+			want: `graph {
+	subgraph {}
+	A -- subgraph {}
+	subgraph "empty" {}
+}`,
+		},
+		"SubgraphsEmptyWithSpacedBraces": {
+			in: `graph {
+	subgraph {
+	}
+	A -- {}
+}`,
+			opts: []printer.Option{printer.WithEmptyBraces(printer.EmptySpaced)},
+			want: `graph {
+	subgraph { }
+	A -- subgraph { }
+}`,
+		},
+		"AttrListsEmpty": {
+			in: `graph {
+	node [ ]
+	A [] []
+}`,
+			want: `graph {
+	node []
+	A []
+}`,
+		},
+		"AttrListsEmptyWithSpacedBrackets": {
+			in: `graph {
+	node []
+	A [] [ ]
+}`,
+			opts: []printer.Option{printer.WithEmptyBrackets(printer.EmptySpaced)},
+			want: `graph {
+	node [ ]
+	A [ ]
+}`,
+		},
+		"AttrListsWithSingleAttributeStayInline": {
+			in: `graph {
+	A [] [color=blue]
+	B [style=filled] [ ]
+	C -- D [] [label="edge"] []
+}`,
+			want: `graph {
+	A [color=blue]
+	B [style=filled]
+	C -- D [label="edge"]
+}`,
+		},
+		"QuotingIsPreservedByDefault": {
+			in: `graph "G" {
+	"A" -- B
+	"C" [label=blue, "color"="red"]
+}`,
+			want: `graph "G" {
+	"A" -- B
+	"C" [
+		label=blue
+		"color"="red"
+	]
+}`,
+		},
+		"QuotingMinimal": {
+			in: `digraph "G" {
+	"A":"p1":n -> "B 2" -> "-1.5" -> "1a" -> "node" -> "\"x\"" -> "_ä1"
+	"node" ["label"="blue", color=""]
+	node ["shape"="box"]
+}`,
+			opts: []printer.Option{printer.WithQuoting(printer.QuoteMinimal)},
+			want: `digraph G {
+	A:p1:n -> "B 2" -> -1.5 -> "1a" -> "node" -> "\"x\"" -> _ä1
+	"node" [
+		label=blue
+		color=""
+	]
+	node [shape=box]
+}`,
+		},
+		"QuotingAlways": {
+			in: `graph G {
+	A:p1:n -- "B 2" -- -1.5
+	node [shape=box]
+	subgraph cluster {
+		label=C
+	}
+}`,
+			opts: []printer.Option{printer.WithQuoting(printer.QuoteAlways)},
+			want: `graph "G" {
+	"A":"p1":n -- "B 2" -- "-1.5"
+	node ["shape"="box"]
+	subgraph "cluster" {
+		"label"="C"
+	}
+}`,
+		},
+		"HTMLStringsAreNeverQuoted": {
+			in: `graph {
+	A [label=<<table>
+		<tr><td>A</td></tr>
+	</table>>]
+}`,
+			opts: []printer.Option{printer.WithQuoting(printer.QuoteAlways)},
+			want: `graph {
+	"A" ["label"=<<table>
+		<tr><td>A</td></tr>
+	</table>>]
+}`,
+		},
+		"LineEndingsAreNormalizedToLF": {
+			in:   "graph {\r\n\tA -- B // edge\r\n\tC [label=\"multi\r\nline\"]\n}\r\n",
+			want: "graph {\n\tA -- B // edge\n\tC [label=\"multi\nline\"]\n}",
+		},
+		"LineEndingsAreNormalizedToCRLF": {
+			in:   "graph {\n\tA -- B /* multi-line\n\tcomment */\n\tC [label=\"multi\r\nline\"]\n}\n",
+			opts: []printer.Option{printer.WithLineEnding(printer.LineEndingCRLF)},
+			want: "graph {\r\n\tA -- B // multi-line comment\r\n\tC [label=\"multi\r\nline\"]\r\n}",
+		},
+		"LineEndingsPreserveDominantCRLF": {
+			in:   "graph {\r\n\tA -- B\n\tC\r\n}\r\n",
+			opts: []printer.Option{printer.WithLineEnding(printer.LineEndingPreserve)},
+			want: "graph {\r\n\tA -- B\r\n\tC\r\n}",
+		},
+		"LineEndingsPreserveDominantLF": {
+			in:   "graph {\r\n\tA -- B\n\tC\n}\r\n",
+			opts: []printer.Option{printer.WithLineEnding(printer.LineEndingPreserve)},
+			want: "graph {\n\tA -- B\n\tC\n}",
+		},
+		"ProvenanceIsPrintedAsHeader": {
+			in: `// my graph
+graph {
+	A
+}`,
+			opts: []printer.Option{printer.WithProvenance(printer.Provenance{
+				Tool:       "dotgen",
+				Version:    "v1.0.0",
+				SourceHash: "sha256:4f2a",
+				Time:       time.Date(2024, 8, 1, 10, 0, 0, 0, time.UTC),
+			})},
+			want: `// Code generated by dotgen v1.0.0 from source sha256:4f2a at 2024-08-01T10:00:00Z with checksum dbf93633ac0731a0. DO NOT EDIT.
+// my graph
+graph {
+	A
+}`,
+		},
+		"ProvenanceReplacesGeneratedComment": {
+			in: `# Code generated by dotgen v0.9.0. DO NOT EDIT.
+graph {
+	A // Code generated by dotgen v0.9.0. DO NOT EDIT.
+}`,
+			opts: []printer.Option{printer.WithProvenance(printer.Provenance{
+				Tool: "dotgen",
+			})},
+			want: `// Code generated by dotgen with checksum dbf93633ac0731a0. DO NOT EDIT.
+graph {
+	A // Code generated by dotgen v0.9.0. DO NOT EDIT.
+}`,
+		},
+		"StripAttrs": {
+			in: `graph {
+	"x-owner"=alice
+	A ["x-link"="https://example.com", color=blue, "y-id"=1]
+	B ["x-owner"=bob]
+}`,
+			opts: []printer.Option{printer.WithStripAttrs("x-"), printer.WithStripAttrs("y-")},
+			want: `graph {
+	A [color=blue]
+	B
+}`,
+		},
+		"Compact": {
+			in: `digraph {
+	A
+	B
+	A -> B -> C
+	// C is documented
+	C
+}`,
+			opts: []printer.Option{printer.WithCompact()},
+			want: `digraph {
+	A -> B -> C
+	// C is documented
+	C
+}`,
+		},
+		"FinalNewline": {
+			in:   "graph {\n\tA\n} // end",
+			opts: []printer.Option{printer.WithFinalNewline()},
+			want: "graph {\n\tA\n} // end\n",
+		},
+		"FinalNewlineWithLineEndingCRLF": {
+			in:   "graph {\n\tA\n}\n",
+			opts: []printer.Option{printer.WithFinalNewline(), printer.WithLineEnding(printer.LineEndingCRLF)},
+			want: "graph {\r\n\tA\r\n}\r\n",
+		},
+		"IndentWithSpaces": {
+			in:   `graph { subgraph { A [color=blue, style=filled] } }`,
+			opts: []printer.Option{printer.WithIndent(2)},
+			want: `graph {
+  subgraph {
+    A [
+      color=blue
+      style=filled
+    ]
+  }
+}`,
+		},
+		"MaxColumnBreaksUpComments": {
+			in:   "graph { A // a comment that is too long for the max column\n}",
+			opts: []printer.Option{printer.WithMaxColumn(20)},
+			want: `graph {
+	A // a comment that
+// is too long for
+// the max column
+}`,
+		},
+		"AttrListsFitOnLine": {
+			in: `graph {
+	A [color=blue, style=filled]
+	B [label="a label that does not fit", color=blue] [style=filled]
+	C [color=blue, // comment
+	style=filled]
+}`,
+			opts: []printer.Option{printer.WithAttrLists(printer.AttrListsFit), printer.WithMaxColumn(40)},
+			want: `graph {
+	A [color=blue, style=filled]
+	B [
+		label="a label that does not fit"
+		color=blue
+		style=filled
+	]
+	C [
+		color=blue // comment
+		style=filled
+	]
+}`,
+		},
+		"AttrListsAreMerged": {
+			in:   `graph { A [color=blue] [] [style=filled]; B [label=b][shape=box] }`,
+			opts: []printer.Option{printer.WithAttrLists(printer.AttrListsFit)},
+			want: `graph {
+	A [color=blue, style=filled]
+	B [label=b, shape=box]
+}`,
+		},
+		"MaxAttrsPerLine": {
+			in: `graph {
+	A [color=blue] [style=filled]
+	B [color=blue, style=filled, shape=box]
+	C [shape=box]
+}`,
+			opts: []printer.Option{printer.WithAttrLists(printer.AttrListsFit), printer.WithMaxAttrsPerLine(2)},
+			want: `graph {
+	A [color=blue, style=filled]
+	B [
+		color=blue
+		style=filled
+		shape=box
+	]
+	C [shape=box]
+}`,
+		},
+		"AlignedAttrs": {
+			in:   `graph { A [color=blue, fontname=Arial, "x y"=1] B [fontcolor=red] }`,
+			opts: []printer.Option{printer.WithAlignedAttrs()},
+			want: `graph {
+	A [
+		color   =blue
+		fontname=Arial
+		"x y"   =1
+	]
+	B [fontcolor=red]
+}`,
+		},
+		"SemicolonsAlways": {
+			in: `graph {
+	A -- B // edge
+	subgraph { C }
+	rank=same; node [shape=box]
+}`,
+			opts: []printer.Option{printer.WithSemicolons(printer.SemicolonsAlways)},
+			want: `graph {
+	A -- B; // edge
+	subgraph {
+		C;
+	};
+	rank=same;
+	node [shape=box];
+}`,
+		},
 		"NodeStatementsWithPorts": {
 			in: `graph {
 		
@@ -228,6 +546,18 @@ Grandparent1  -> Parent1; Grandparent2 -> Parent1;
 		A -- B
 		C -- E
 	}
+}`,
+		},
+		"EmptyStatementsAreDropped": {
+			in: `graph {
+	;;
+	A;;; B -- C ; ;
+	subgraph { ; }
+}`,
+			want: `graph {
+	A
+	B -- C
+	subgraph {}
 }`,
 		},
 		"CommentsWithOnlyWhitespaceAreDiscarded": {
@@ -238,8 +568,7 @@ Grandparent1  -> Parent1; Grandparent2 -> Parent1;
 
 			*/
 }`,
-			want: `graph {
-}`,
+			want: `graph {}`,
 		},
 		"CommentsSingleLineAreChangedToCppMarker": {
 			in: `graph {
@@ -337,6 +666,45 @@ Grandparent1  -> Parent1; Grandparent2 -> Parent1;
 		style="filled" // always
 		color="pink" // what else!
 	] // keep me
+}`,
+		},
+		"CommentsSectionHeadersArePrecededByExactlyOneBlankLine": {
+			in: `digraph {
+// --- nodes ---
+
+
+	A
+	B
+	C [color=red] // ---
+	// ---- subgraphs ----
+	subgraph {
+		// -----
+		D
+	}
+
+
+	// =====
+	// edges
+	// =====
+
+	A -> B
+}`,
+			want: `digraph {
+	// --- nodes ---
+	A
+	B
+	C [color=red] // ---
+
+	// ---- subgraphs ----
+	subgraph {
+		// -----
+		D
+	}
+
+	// =====
+	// edges
+	// =====
+	A -> B
 }`,
 		},
 		"CommentsBeforeGraph": {
@@ -350,16 +718,14 @@ Grandparent1  -> Parent1; Grandparent2 -> Parent1;
 `,
 			want: `// this is my graph
 // and I do what I want to!
-graph {
-}`,
+graph {}`,
 		},
 		"CommentsAfterGraph": {
 			in: `graph {
 		}//this is kept here
 
 			//	 oh wait !`,
-			want: `graph {
-} // this is kept here
+			want: `graph {} // this is kept here
 // oh wait !`,
 		},
 	}
@@ -367,7 +733,7 @@ graph {
 	for name, test := range tests {
 		t.Run(name, func(t *testing.T) {
 			var got bytes.Buffer
-			p := printer.NewPrinter(strings.NewReader(test.in), &got)
+			p := printer.NewPrinter(strings.NewReader(test.in), &got, test.opts...)
 			err := p.Print()
 			require.NoErrorf(t, err, "Print(%q)", test.in)
 
@@ -377,3 +743,278 @@ graph {
 		})
 	}
 }
+
+func TestPrintIdempotent(t *testing.T) {
+	in := `graph {
+	A [color=blue] [] [style=filled]
+	B [label="a label that does not fit", color=blue] [style=filled]
+	C [color=blue, style=filled, shape=box] D [shape=box]
+	E [color=blue, // comment
+	style=filled]
+}`
+	tests := map[string][]printer.Option{
+		"Default":         nil,
+		"AttrListsFit":    {printer.WithAttrLists(printer.AttrListsFit), printer.WithMaxColumn(40)},
+		"MaxAttrsPerLine": {printer.WithAttrLists(printer.AttrListsFit), printer.WithMaxAttrsPerLine(2)},
+		"AlignedAttrs":    {printer.WithAlignedAttrs(), printer.WithMaxAttrsPerLine(1)},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			var first bytes.Buffer
+			p := printer.NewPrinter(strings.NewReader(in), &first, opts...)
+			err := p.Print()
+			require.NoErrorf(t, err, "Print(%q)", in)
+
+			var second bytes.Buffer
+			p = printer.NewPrinter(bytes.NewReader(first.Bytes()), &second, opts...)
+			err = p.Print()
+			require.NoErrorf(t, err, "Print(%q)", first.String())
+
+			if second.String() != first.String() {
+				t.Errorf("formatting the output again changed it\n\ngot:\n%s\n\n\nwant:\n%s\n", second.String(), first.String())
+			}
+		})
+	}
+}
+
+// FuzzPrintPreservesMeaning checks that formatting does not change what a graph means to Graphviz
+// like moving an attribute into another statement. Formatting being idempotent is not enough as
+// the formatter could change the meaning the same way every time. Run it using
+//
+//	go test -run '^$' -fuzz FuzzPrintPreservesMeaning ./printer
+func FuzzPrintPreservesMeaning(f *testing.F) {
+	files, err := filepath.Glob(filepath.Join("testdata", "canonical", "*", "*.dot"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		in, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(in)
+	}
+	f.Add([]byte(`strict digraph "G" { node [shape=box] a:p:n -> {b c} -> d [color=red] [style=dashed]; subgraph cluster_x { label="x"; e; f } }`))
+	f.Add([]byte("graph { a [label=\"multi\nline\" xlabel=<<b>bold</b>>] // comment\n b -- a; edge [color=blue] /* block */ c -- d }"))
+	f.Add([]byte("graph { /*/ not closed by the opening marker */ a }"))
+
+	f.Fuzz(func(t *testing.T, in []byte) {
+		want, err := dot.Parse(in)
+		if err != nil {
+			t.Skip("invalid dot code")
+		}
+
+		var out bytes.Buffer
+		err = printer.NewPrinter(bytes.NewReader(in), &out).Print()
+		require.NoErrorf(t, err, "Print(%q)", in)
+		got, err := dot.Parse(out.Bytes())
+		require.NoErrorf(t, err, "Parse(%q) of the formatted %q", out.String(), in)
+
+		wantGraph, gotGraph := graph.Build(want), graph.Build(got)
+		assert.EqualValuesf(t, gotGraph.ID, wantGraph.ID, "ID of formatted %q", in)
+		assert.EqualValuesf(t, gotGraph.Directed, wantGraph.Directed, "Directed of formatted %q", in)
+		assert.EqualValuesf(t, gotGraph.Strict, wantGraph.Strict, "Strict of formatted %q", in)
+		assert.EqualValuesf(t, gotGraph.Stats(), wantGraph.Stats(), "Stats() of formatted %q", in)
+		if changes := graph.Diff(wantGraph, gotGraph); len(changes) > 0 {
+			t.Errorf("formatting %q as\n%s\nchanged its meaning: %v", in, out.String(), changes)
+		}
+	})
+}
+
+// TestCanonical guards the stability of the canonical form. Every input in the directory of the
+// current [printer.CanonicalVersion] must print exactly as its golden file. Do not update the
+// golden files of a released version. Increment the version instead and add a directory with
+// golden files of the new canonical form.
+func TestCanonical(t *testing.T) {
+	dir := filepath.Join("testdata", "canonical", fmt.Sprintf("v%d", printer.CanonicalVersion))
+	files, err := filepath.Glob(filepath.Join(dir, "*.dot"))
+	require.NoErrorf(t, err, "Glob(%q)", dir)
+	require.Truef(t, len(files) > 0, "no golden files for CanonicalVersion %d in %q", printer.CanonicalVersion, dir)
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			in, err := os.ReadFile(file)
+			require.NoErrorf(t, err, "ReadFile(%q)", file)
+			golden := strings.TrimSuffix(file, ".dot") + ".golden"
+			want, err := os.ReadFile(golden)
+			require.NoErrorf(t, err, "ReadFile(%q)", golden)
+
+			var got bytes.Buffer
+			p := printer.NewPrinter(bytes.NewReader(in), &got)
+			err = p.Print()
+			require.NoErrorf(t, err, "Print(%q)", file)
+
+			if got.String() != string(want) {
+				t.Errorf("canonical form of %q changed, increment CanonicalVersion if this is intended\n\ngot:\n%s\n\n\nwant:\n%s\n", file, got.String(), want)
+			}
+
+			var again bytes.Buffer
+			p = printer.NewPrinter(bytes.NewReader(want), &again)
+			err = p.Print()
+			require.NoErrorf(t, err, "Print(%q)", golden)
+
+			if again.String() != string(want) {
+				t.Errorf("formatting the canonical form of %q changed it\n\ngot:\n%s\n\n\nwant:\n%s\n", file, again.String(), want)
+			}
+		})
+	}
+}
+
+func TestPrintBreaks(t *testing.T) {
+	in := `digraph {
+	A [color=red, style=filled]
+	B [label="a b c d e f"] // gg hh ii jj kk
+	C [color=red, // why
+	style=filled]
+}`
+	var got []printer.Break
+	var out bytes.Buffer
+	p := printer.NewPrinter(strings.NewReader(in), &out, printer.WithMaxColumn(20), printer.WithAttrLists(printer.AttrListsFit), printer.WithBreaks(&got))
+	err := p.Print()
+	require.NoErrorf(t, err, "Print(%q)", in)
+
+	want := []printer.Break{
+		{Output: token.Position{Row: 2, Column: 4}, Source: token.Position{Row: 2, Column: 4}, Construct: printer.ConstructAttrList, Reason: printer.BreakWidth},
+		{Output: token.Position{Row: 6, Column: 21}, Source: token.Position{Row: 3, Column: 11}, Construct: printer.ConstructID, Reason: printer.BreakWidth},
+		{Output: token.Position{Row: 7, Column: 19}, Source: token.Position{Row: 3, Column: 26}, Construct: printer.ConstructComment, Reason: printer.BreakWidth},
+		{Output: token.Position{Row: 9, Column: 4}, Source: token.Position{Row: 4, Column: 4}, Construct: printer.ConstructAttrList, Reason: printer.BreakComment},
+	}
+	assert.EqualValuesf(t, got, want, "Print(%q) breaks of\n%s", in, out.String())
+}
+
+func TestPreview(t *testing.T) {
+	in := `digraph G {
+	rankdir=LR
+	A -> B // edge
+	subgraph cluster_a { C; D }
+	E -> F
+}`
+
+	tests := map[string]struct {
+		n    int
+		want string
+	}{
+		"NoStatements": {
+			n:    0,
+			want: "digraph G {\n// ...\n}",
+		},
+		"SubgraphCountsAsOneStatement": {
+			n: 3,
+			want: `digraph G {
+	rankdir=LR
+	A -> B
+	subgraph cluster_a {
+		C
+		D
+	}
+// ...
+}`,
+		},
+		"AllStatements": {
+			n: 4,
+			want: `digraph G {
+	rankdir=LR
+	A -> B
+	subgraph cluster_a {
+		C
+		D
+	}
+	E -> F
+}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got bytes.Buffer
+			err := printer.Preview(strings.NewReader(in), &got, test.n)
+			require.NoErrorf(t, err, "Preview(%q, %d)", in, test.n)
+
+			assert.EqualValuesf(t, got.String(), test.want, "Preview(%q, %d)", in, test.n)
+		})
+	}
+
+	t.Run("StopsReadingAfterTheNextStatement", func(t *testing.T) {
+		in := "digraph { A; B; C -> }"
+
+		var got bytes.Buffer
+		err := printer.Preview(strings.NewReader(in), &got, 1)
+		require.NoErrorf(t, err, "Preview(%q, 1)", in)
+
+		assert.EqualValuesf(t, got.String(), "digraph {\n\tA\n// ...\n}", "Preview(%q, 1)", in)
+	})
+}
+
+func TestFprint(t *testing.T) {
+	t.Run("GraphBuiltInCode", func(t *testing.T) {
+		g := ast.Graph{
+			Directed: true,
+			ID:       &ast.ID{Literal: "G"},
+			Stmts: []ast.Stmt{
+				&ast.NodeStmt{
+					NodeID: ast.NodeID{ID: ast.NewID("a b")},
+					AttrList: &ast.AttrList{AList: &ast.AList{
+						Attribute: ast.Attribute{Name: ast.NewID("color"), Value: ast.NewID("red")},
+					}},
+				},
+				ast.Subgraph{
+					ID: &ast.ID{Literal: "cluster_x"},
+					Stmts: []ast.Stmt{
+						&ast.EdgeStmt{
+							Left:  ast.NodeID{ID: ast.NewID("a b")},
+							Right: ast.EdgeRHS{Directed: true, Right: ast.NodeID{ID: ast.NewID("c")}},
+						},
+					},
+				},
+			},
+		}
+
+		var got bytes.Buffer
+		err := printer.Fprint(&got, g)
+		require.NoErrorf(t, err, "Fprint(%s)", g)
+
+		want := `digraph G {
+	"a b" [color=red]
+	subgraph cluster_x {
+		"a b" -> c
+	}
+}`
+		assert.EqualValuesf(t, got.String(), want, "Fprint(%s)", g)
+	})
+
+	t.Run("TransformsLeaveGraphUnchanged", func(t *testing.T) {
+		in := `graph { A ["x-o"=1, color=red]; "x-a"=2; B; A -- B ["x-o"=3]; {C} -- D; C }`
+		g, err := dot.Parse([]byte(in))
+		require.NoErrorf(t, err, "Parse(%q)", in)
+		want := g.String()
+		opts := []printer.Option{printer.WithStripAttrs("x-"), printer.WithCompact()}
+
+		var first, second bytes.Buffer
+		err = printer.Fprint(&first, g, opts...)
+		require.NoErrorf(t, err, "Fprint(%q)", in)
+		err = printer.Fprint(&second, g, opts...)
+		require.NoErrorf(t, err, "Fprint(%q)", in)
+
+		assert.EqualValuesf(t, second.String(), first.String(), "Fprint(%q) twice", in)
+		assert.EqualValuesf(t, g.String(), want, "Fprint(%q) modified the graph", in)
+	})
+
+	// an AST built in code is not limited in depth by the parser
+	t.Run("SubgraphsNestedTooDeep", func(t *testing.T) {
+		var subgraph ast.Subgraph
+		for range 100_000 {
+			subgraph = ast.Subgraph{Stmts: []ast.Stmt{subgraph}}
+		}
+		g := ast.Graph{Stmts: []ast.Stmt{&ast.EdgeStmt{
+			Left:  ast.NodeID{ID: ast.NewID("a")},
+			Right: ast.EdgeRHS{Right: subgraph},
+		}}}
+
+		var got bytes.Buffer
+		err := printer.Fprint(&got, g)
+		require.NotNilf(t, err, "Fprint of subgraphs nested 100000 deep")
+		assert.EqualValuesf(t, err.Error(), "subgraphs are nested deeper than the max depth of 1000", "Fprint of subgraphs nested 100000 deep")
+		assert.EqualValuesf(t, got.Len(), 0, "Fprint of subgraphs nested 100000 deep")
+	})
+}
@@ -0,0 +1,219 @@
+// Package query selects statements of dot graphs using selectors like edge[color=red]. It allows
+// grep-style analysis of large graphs without writing code that walks the AST.
+//
+// A selector is made of the kind of statement followed by any number of attribute filters
+//
+//	selector = kind { filter }
+//	kind     = node | edge | attr | subgraph | *
+//	filter   = '[' ID [ ('=' | '!=') ID ] ']'
+//
+// The kinds node and edge select node and edge statements, attr selects attribute statements like
+// node [shape=box] and * selects statements of any of the kinds. A filter [name] selects statements
+// setting the attribute, [name=value] statements setting it to the value and [name!=value]
+// statements not setting it to the value. IDs are unquoted strings or quoted strings like "a b" as
+// defined in https://graphviz.org/doc/info/lang.html#ids. The attributes of a subgraph are the
+// attribute statements like label=L directly in its body.
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/teleivo/dot/ast"
+)
+
+// Selector selects statements of a graph. Create one using [Parse].
+type Selector struct {
+	kind    string
+	filters []filter
+}
+
+type filter struct {
+	name  string
+	op    string // op is either empty, = or !=
+	value string
+}
+
+// kinds are the kinds of statements a selector can select.
+var kinds = []string{"node", "edge", "attr", "subgraph", "*"}
+
+// Parse parses the selector.
+func Parse(in string) (Selector, error) {
+	p := parser{in: in}
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.in) && (p.in[p.pos] == '*' || isLetter(p.in[p.pos])) {
+		p.pos++
+	}
+	s := Selector{kind: p.in[start:p.pos]}
+	if !slices.Contains(kinds, s.kind) {
+		return Selector{}, p.errorf("expected one of %q at offset %d", kinds, start)
+	}
+
+	for p.skipSpace(); p.pos < len(p.in); p.skipSpace() {
+		if !p.consume("[") {
+			return Selector{}, p.errorf("expected '[' at offset %d", p.pos)
+		}
+		var f filter
+		var err error
+		f.name, err = p.id()
+		if err != nil {
+			return Selector{}, err
+		}
+		p.skipSpace()
+		if p.consume("=") {
+			f.op = "="
+		} else if p.consume("!=") {
+			f.op = "!="
+		}
+		if f.op != "" {
+			if f.value, err = p.id(); err != nil {
+				return Selector{}, err
+			}
+			p.skipSpace()
+		}
+		if !p.consume("]") {
+			return Selector{}, p.errorf("expected ']' at offset %d", p.pos)
+		}
+		s.filters = append(s.filters, f)
+	}
+	return s, nil
+}
+
+// Match reports whether the selector selects the statement.
+func (s Selector) Match(stmt ast.Stmt) bool {
+	var attrs map[string]string
+	switch st := stmt.(type) {
+	case *ast.NodeStmt:
+		if s.kind != "node" && s.kind != "*" {
+			return false
+		}
+		attrs = listAttrs(st.AttrList)
+	case *ast.EdgeStmt:
+		if s.kind != "edge" && s.kind != "*" {
+			return false
+		}
+		attrs = listAttrs(st.AttrList)
+	case *ast.AttrStmt:
+		if s.kind != "attr" && s.kind != "*" {
+			return false
+		}
+		attrs = listAttrs(&st.AttrList)
+	case ast.Subgraph:
+		if s.kind != "subgraph" && s.kind != "*" {
+			return false
+		}
+		attrs = make(map[string]string)
+		for _, stmt := range st.Stmts {
+			if a, ok := stmt.(ast.Attribute); ok {
+				attrs[a.Name.Unquoted()] = a.Value.Unquoted()
+			}
+		}
+	default:
+		return false
+	}
+
+	for _, f := range s.filters {
+		value, ok := attrs[f.name]
+		switch f.op {
+		case "":
+			if !ok {
+				return false
+			}
+		case "=":
+			if !ok || value != f.value {
+				return false
+			}
+		case "!=":
+			if ok && value == f.value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Select returns the statements of the graph the selector selects in the order they appear in the
+// source. Statements of subgraphs including subgraphs used as edge operands are selected as well.
+func Select(g ast.Graph, s Selector) []ast.Stmt {
+	var result []ast.Stmt
+	ast.Inspect(g, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AttrList, ast.Attribute:
+			return false
+		case ast.Stmt:
+			if s.Match(n) {
+				result = append(result, n)
+			}
+		}
+		return true
+	})
+	return result
+}
+
+// listAttrs returns the unquoted attributes of all attribute lists. Later attributes override
+// earlier ones of the same name as they do in Graphviz.
+func listAttrs(attrList *ast.AttrList) map[string]string {
+	result := make(map[string]string)
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			result[aList.Attribute.Name.Unquoted()] = aList.Attribute.Value.Unquoted()
+		}
+	}
+	return result
+}
+
+type parser struct {
+	in  string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid selector %q: "+format, append([]any{p.in}, args...)...)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.in) && (p.in[p.pos] == ' ' || p.in[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// consume advances past the prefix and reports whether the input continues with it.
+func (p *parser) consume(prefix string) bool {
+	if !strings.HasPrefix(p.in[p.pos:], prefix) {
+		return false
+	}
+	p.pos += len(prefix)
+	return true
+}
+
+// id parses an unquoted or quoted ID and returns it unquoted.
+func (p *parser) id() (string, error) {
+	p.skipSpace()
+	start := p.pos
+	if p.consume(`"`) {
+		for p.pos < len(p.in) && p.in[p.pos] != '"' {
+			if p.in[p.pos] == '\\' && p.pos+1 < len(p.in) {
+				p.pos++
+			}
+			p.pos++
+		}
+		if !p.consume(`"`) {
+			return "", p.errorf("missing closing quote of the ID at offset %d", start)
+		}
+		return ast.ID{Literal: p.in[start:p.pos]}.Unquoted(), nil
+	}
+
+	for p.pos < len(p.in) && !strings.ContainsRune(`[]=!" `+"\t", rune(p.in[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected an ID at offset %d", start)
+	}
+	return p.in[start:p.pos], nil
+}
+
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,99 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/query"
+)
+
+func TestSelect(t *testing.T) {
+	in := `digraph {
+	node [shape=box]
+	A [color=red]
+	B [color=blue,label="B B"]
+	A -> B [color=red]
+	A -> subgraph cluster_x {
+		label=X
+		C [color=red]
+	}
+}`
+	g, err := dot.Parse([]byte(in))
+	require.NoErrorf(t, err, "Parse(%q)", in)
+
+	tests := map[string]struct {
+		selector string
+		want     []string
+	}{
+		"Nodes": {
+			selector: "node",
+			want:     []string{"3:2: A [color=red]", `4:2: B [color=blue,label="B B"]`, "8:3: C [color=red]"},
+		},
+		"EdgesWithAttribute": {
+			selector: "edge[color=red]",
+			want:     []string{"5:2: A -> B [color=red]"},
+		},
+		"AnyWithAttribute": {
+			selector: "*[color = red]",
+			want:     []string{"3:2: A [color=red]", "5:2: A -> B [color=red]", "8:3: C [color=red]"},
+		},
+		"QuotedValue": {
+			selector: `node[label="B B"]`,
+			want:     []string{`4:2: B [color=blue,label="B B"]`},
+		},
+		"NotEqual": {
+			selector: "node[color!=red]",
+			want:     []string{`4:2: B [color=blue,label="B B"]`},
+		},
+		"MultipleFilters": {
+			selector: "node[color][label]",
+			want:     []string{`4:2: B [color=blue,label="B B"]`},
+		},
+		"Subgraphs": {
+			selector: "subgraph[label=X]",
+			want:     []string{"6:7: subgraph cluster_x {label=X C [color=red]}"},
+		},
+		"AttributeStatements": {
+			selector: "attr[shape]",
+			want:     []string{"2:2: node [shape=box]"},
+		},
+		"NoMatch": {
+			selector: "edge[style]",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := query.Parse(test.selector)
+			require.NoErrorf(t, err, "Parse(%q)", test.selector)
+
+			var got []string
+			for _, stmt := range query.Select(g, s) {
+				got = append(got, stmt.Start().String()+": "+stmt.String())
+			}
+
+			assert.EqualValuesf(t, got, test.want, "Select(%q)", test.selector)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]string{
+		"":               `invalid selector "": expected one of ["node" "edge" "attr" "subgraph" "*"] at offset 0`,
+		"nodes":          `invalid selector "nodes": expected one of ["node" "edge" "attr" "subgraph" "*"] at offset 0`,
+		"node color":     `invalid selector "node color": expected '[' at offset 5`,
+		"node[]":         `invalid selector "node[]": expected an ID at offset 5`,
+		"node[color":     `invalid selector "node[color": expected ']' at offset 10`,
+		"node[color=]":   `invalid selector "node[color=]": expected an ID at offset 11`,
+		`node[label="a]`: `invalid selector "node[label=\"a]": missing closing quote of the ID at offset 11`,
+	}
+
+	for in, want := range tests {
+		_, err := query.Parse(in)
+
+		require.NotNilf(t, err, "Parse(%q)", in)
+		assert.EqualValuesf(t, err.Error(), want, "Parse(%q)", in)
+	}
+}
@@ -93,7 +93,7 @@ func (sc *Scanner) Next() (token.Token, error) {
 			}
 			return tok, err
 		} else {
-			err = sc.error(unquotedStringErr)
+			err = sc.error(InvalidCharacter, unquotedStringErr)
 		}
 	}
 
@@ -147,15 +147,21 @@ func (sc *Scanner) skipWhitespace() {
 }
 
 // isWhitespace determines if the rune is considered whitespace. It does not include non-breaking
-// whitespace \240 which is considered whitespace by [unicode.isWhitespace].
+// whitespace \240 which is considered whitespace by [unicode.isWhitespace]. A carriage return is
+// whitespace so line endings can either be '\n' or "\r\n".
 func isWhitespace(r rune) bool {
 	switch r {
-	case ' ', '\t', '\n':
+	case ' ', '\t', '\n', '\r':
 		return true
 	}
 	return false
 }
 
+// isEndOfLine determines if the runes start a line ending which is either '\n' or "\r\n".
+func isEndOfLine(first, second rune) bool {
+	return first == '\n' || (first == '\r' && second == '\n')
+}
+
 func (sc *Scanner) hasNext() bool {
 	return !sc.eof || sc.cur != 0
 }
@@ -180,17 +186,18 @@ func (sc *Scanner) tokenizeComment() (token.Token, error) {
 	var hasClosingMarker bool
 
 	if sc.cur == '/' && sc.hasNext() && sc.next != '/' && sc.next != '*' {
-		return token.Token{}, sc.error("missing '/' for single-line or a '*' for a multi-line comment")
+		return token.Token{}, sc.error(InvalidComment, "missing '/' for single-line or a '*' for a multi-line comment")
 	}
 
 	start := token.Position{Row: sc.curRow, Column: sc.curColumn}
 	var end token.Position
 	isMultiLine := sc.cur == '/' && sc.hasNext() && sc.next == '*'
-	for ; sc.hasNext() && err == nil && (isMultiLine || sc.cur != '\n'); err = sc.readRune() {
+	for ; sc.hasNext() && err == nil && (isMultiLine || !isEndOfLine(sc.cur, sc.next)); err = sc.readRune() {
 		end = token.Position{Row: sc.curRow, Column: sc.curColumn}
 		comment = append(comment, sc.cur)
 
-		if isMultiLine && sc.cur == '*' && sc.hasNext() && sc.next == '/' {
+		// the '*' of the opening marker cannot start the closing marker as in /*/
+		if isMultiLine && len(comment) > 2 && sc.cur == '*' && sc.hasNext() && sc.next == '/' {
 			hasClosingMarker = true
 			comment = append(comment, sc.next)
 			err = sc.readRune() // consume last rune '/' of closing marker
@@ -200,7 +207,7 @@ func (sc *Scanner) tokenizeComment() (token.Token, error) {
 	}
 
 	if isMultiLine && !hasClosingMarker {
-		err = sc.error("missing closing marker '*/' for multi-line comment")
+		err = sc.error(UnclosedComment, "missing closing marker '*/' for multi-line comment")
 	}
 	if err != nil {
 		return tok, err
@@ -246,7 +253,8 @@ func (sc *Scanner) tokenizeEdgeOperator() (token.Token, error) {
 func isStartofIdentifier(r rune) bool {
 	if isStartOfUnquotedString(r) ||
 		isStartOfNumeral(r) ||
-		isStartOfQuotedString(r) {
+		isStartOfQuotedString(r) ||
+		isStartOfHTMLString(r) {
 		return true
 	}
 
@@ -271,6 +279,12 @@ func isStartOfQuotedString(r rune) bool {
 	return r == '"'
 }
 
+// isStartOfHTMLString determines if the rune starts an HTML string like <<b>bold</b>> as defined in
+// https://graphviz.org/doc/info/shapes.html#html.
+func isStartOfHTMLString(r rune) bool {
+	return r == '<'
+}
+
 func (sc *Scanner) tokenizeIdentifier() (token.Token, error) {
 	if isStartOfUnquotedString(sc.cur) {
 		return sc.tokenizeUnquotedString()
@@ -278,19 +292,37 @@ func (sc *Scanner) tokenizeIdentifier() (token.Token, error) {
 		return sc.tokenizeNumeral()
 	} else if isStartOfQuotedString(sc.cur) {
 		return sc.tokenizeQuotedString()
+	} else if isStartOfHTMLString(sc.cur) {
+		return sc.tokenizeHTMLString()
 	}
 
 	var tok token.Token
-	return tok, sc.error("invalid token")
+	return tok, sc.error(InvalidCharacter, "invalid token")
 }
 
-func (sc *Scanner) error(reason string) Error {
-	return Error{
+// error creates an error of given category at the current rune. The scanner advances past the
+// erroneous input to compute the position scanning could resume at as it does not tokenize
+// anything after an error.
+func (sc *Scanner) error(category ErrorCategory, reason string) Error {
+	err := Error{
 		LineNr:      sc.curRow,
 		CharacterNr: sc.curColumn,
 		Character:   sc.cur,
 		Reason:      reason,
+		Category:    category,
+	}
+
+	switch category {
+	case InvalidCharacter, InvalidNumeral, InvalidComment:
+		for sc.hasNext() && !isWhitespace(sc.cur) && !isTerminal(sc.cur) {
+			if sc.readRune() != nil {
+				break
+			}
+		}
 	}
+	err.Resume = token.Position{Row: sc.curRow, Column: sc.curColumn}
+
+	return err
 }
 
 // tokenizeUnquotedString considers the current rune(s) as an identifier that might be a dot
@@ -305,7 +337,7 @@ func (sc *Scanner) tokenizeUnquotedString() (token.Token, error) {
 	for ; sc.hasNext() && err == nil && !isUnquotedStringSeparator(sc.cur); err = sc.readRune() {
 		end = token.Position{Row: sc.curRow, Column: sc.curColumn}
 		if !isLegalInUnquotedString(sc.cur) {
-			return tok, sc.error(unquotedStringErr)
+			return tok, sc.error(InvalidCharacter, unquotedStringErr)
 		}
 
 		id = append(id, sc.cur)
@@ -364,15 +396,15 @@ func (sc *Scanner) tokenizeNumeral() (token.Token, error) {
 	for pos, hasDot := 0, false; sc.hasNext() && err == nil && !sc.isNumeralSeparator(); err, pos = sc.readRune(), pos+1 {
 		end = token.Position{Row: sc.curRow, Column: sc.curColumn}
 		if sc.cur == '-' && pos != 0 {
-			return tok, sc.error("a numeral can only be prefixed with a `-`")
+			return tok, sc.error(InvalidNumeral, "a numeral can only be prefixed with a `-`")
 		}
 
 		if sc.cur == '.' && hasDot {
-			return tok, sc.error("a numeral can only have one `.` that is at least preceded or followed by digits")
+			return tok, sc.error(InvalidNumeral, "a numeral can only have one `.` that is at least preceded or followed by digits")
 		}
 
 		if sc.cur != '-' && sc.cur != '.' && !unicode.IsDigit(sc.cur) { // otherwise only digits are allowed
-			return tok, sc.error("a numeral can optionally lead with a `-`, has to have at least one digit before or after a `.` which must only be followed by digits")
+			return tok, sc.error(InvalidNumeral, "a numeral can optionally lead with a `-`, has to have at least one digit before or after a `.` which must only be followed by digits")
 		}
 
 		if sc.cur == '.' {
@@ -385,7 +417,7 @@ func (sc *Scanner) tokenizeNumeral() (token.Token, error) {
 	}
 
 	if !hasDigit {
-		err = sc.error("a numeral must have at least one digit")
+		err = sc.error(InvalidNumeral, "a numeral must have at least one digit")
 	}
 	if err != nil {
 		return tok, err
@@ -421,13 +453,56 @@ func (sc *Scanner) tokenizeQuotedString() (token.Token, error) {
 			break
 		}
 		if pos > maxUnquotedStringLen {
-			return tok, sc.error(fmt.Sprintf("potentially missing closing quote, found none after max %d characters", maxUnquotedStringLen+1))
+			return tok, sc.error(UnclosedString, fmt.Sprintf("potentially missing closing quote, found none after max %d characters", maxUnquotedStringLen+1))
 		}
 		prev = sc.cur
 	}
 
 	if !hasClosingQuote {
-		err = sc.error("missing closing quote")
+		err = sc.error(UnclosedString, "missing closing quote")
+	}
+	if err != nil {
+		return tok, err
+	}
+
+	return token.Token{
+		Type:    token.Identifier,
+		Literal: string(id),
+		Start:   start,
+		End:     end,
+	}, nil
+}
+
+// tokenizeHTMLString tokenizes an HTML string which is delimited by '<' and '>'. The '<' and '>'
+// inside must be balanced. Like Graphviz, the scanner does not know about quotes or comments
+// inside the HTML string so <<b title=">">x</b>> ends after the '>' following the title. The
+// literal includes the delimiters. The contents are not validated as HTML-like label.
+func (sc *Scanner) tokenizeHTMLString() (token.Token, error) {
+	var tok token.Token
+	var err error
+	var id []rune
+	var hasClosingBracket bool
+	start := token.Position{Row: sc.curRow, Column: sc.curColumn}
+	var end token.Position
+
+	for depth := 0; sc.hasNext() && err == nil; err = sc.readRune() {
+		end = token.Position{Row: sc.curRow, Column: sc.curColumn}
+		id = append(id, sc.cur)
+
+		if sc.cur == '<' {
+			depth++
+		} else if sc.cur == '>' {
+			depth--
+		}
+		if depth == 0 {
+			hasClosingBracket = true
+			err = sc.readRune() // consume closing '>'
+			break
+		}
+	}
+
+	if !hasClosingBracket {
+		err = sc.error(UnclosedHTMLString, "missing closing '>' of HTML string")
 	}
 	if err != nil {
 		return tok, err
@@ -441,11 +516,30 @@ func (sc *Scanner) tokenizeQuotedString() (token.Token, error) {
 	}, nil
 }
 
+// ErrorCategory classifies errors so tools can decide how to recover from them.
+type ErrorCategory int
+
+const (
+	InvalidCharacter   ErrorCategory = iota // InvalidCharacter is a character that cannot start or be part of an identifier.
+	InvalidNumeral                          // InvalidNumeral is a malformed numeral identifier.
+	InvalidComment                          // InvalidComment is a '/' that is not followed by a comment marker.
+	UnclosedComment                         // UnclosedComment is a multi-line comment without its closing marker.
+	UnclosedString                          // UnclosedString is a quoted string identifier without its closing quote.
+	KeywordAsID                             // KeywordAsID is an unquoted keyword like node used as an identifier. It is reported by the parser.
+	UnclosedHTMLString                      // UnclosedHTMLString is an HTML string identifier without its closing '>'.
+	InvalidSyntax                           // InvalidSyntax is a token the grammar does not allow at its position like a missing '}'. It is reported by the parser.
+)
+
+// Error is an error found while scanning or parsing dot source code. The parser reports keywords
+// used as identifiers as an Error of category [KeywordAsID] and any other syntax error as an Error
+// of category [InvalidSyntax].
 type Error struct {
-	LineNr      int    // Line number the error was found.
-	CharacterNr int    // Character number the error was found.
-	Character   rune   // Character that caused the error.
-	Reason      string // Reason for the error.
+	LineNr      int            // Line number the error was found.
+	CharacterNr int            // Character number the error was found.
+	Character   rune           // Character that caused the error.
+	Reason      string         // Reason for the error.
+	Category    ErrorCategory  // Category of the error.
+	Resume      token.Position // Resume is the position after the erroneous input. Invalid characters, numerals and comments end at the next whitespace or terminal. Unclosed comments and strings end where scanning stopped.
 }
 
 func (e Error) Error() string {
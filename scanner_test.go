@@ -602,6 +602,42 @@ func TestScanner(t *testing.T) {
 				},
 			},
 		},
+		"CRLFLineEndings": {
+			in: "graph {\r\n\tA # comment\r\n}\r\n",
+			want: []token.Token{
+				{
+					Type:    token.Graph,
+					Literal: "graph",
+					Start:   token.Position{Row: 1, Column: 1},
+					End:     token.Position{Row: 1, Column: 5},
+				},
+				{
+					Type:    token.LeftBrace,
+					Literal: "{",
+					Start:   token.Position{Row: 1, Column: 7},
+					End:     token.Position{Row: 1, Column: 7},
+				},
+				{
+					Type:    token.Identifier,
+					Literal: "A",
+					Start:   token.Position{Row: 2, Column: 2},
+					End:     token.Position{Row: 2, Column: 2},
+				},
+				{
+					Type:    token.Comment,
+					Literal: "# comment",
+					Start:   token.Position{Row: 2, Column: 4},
+					End:     token.Position{Row: 2, Column: 12},
+				},
+				{
+					Type:    token.RightBrace,
+					Literal: "}",
+					Start:   token.Position{Row: 3, Column: 1},
+					End:     token.Position{Row: 3, Column: 1},
+				},
+				{Type: token.EOF},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -682,6 +718,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 3,
 						Character:   '',
 						Reason:      `unquoted string identifiers can contain alphabetic ([a-zA-Z\200-\377]) characters, underscores ('_') or digits([0-9]), but not begin with a digit`,
+						Category:    InvalidCharacter,
+						Resume:      token.Position{Row: 1, Column: 4},
 					},
 				},
 				{
@@ -691,6 +729,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 7,
 						Character:   '',
 						Reason:      `unquoted string identifiers can contain alphabetic ([a-zA-Z\200-\377]) characters, underscores ('_') or digits([0-9]), but not begin with a digit`,
+						Category:    InvalidCharacter,
+						Resume:      token.Position{Row: 1, Column: 9},
 					},
 				},
 				{
@@ -700,6 +740,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 1,
 						Character:   'Ā',
 						Reason:      `unquoted string identifiers can contain alphabetic ([a-zA-Z\200-\377]) characters, underscores ('_') or digits([0-9]), but not begin with a digit`,
+						Category:    InvalidCharacter,
+						Resume:      token.Position{Row: 1, Column: 2},
 					},
 				},
 				{
@@ -709,6 +751,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 2,
 						Character:   'Ā',
 						Reason:      `unquoted string identifiers can contain alphabetic ([a-zA-Z\200-\377]) characters, underscores ('_') or digits([0-9]), but not begin with a digit`,
+						Category:    InvalidCharacter,
+						Resume:      token.Position{Row: 1, Column: 3},
 					},
 				},
 				{
@@ -718,6 +762,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 2,
 						Character:   '\000',
 						Reason:      `unquoted string identifiers can contain alphabetic ([a-zA-Z\200-\377]) characters, underscores ('_') or digits([0-9]), but not begin with a digit`,
+						Category:    InvalidCharacter,
+						Resume:      token.Position{Row: 1, Column: 4},
 					},
 				},
 			}
@@ -855,6 +901,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 4,
 						Character:   'A',
 						Reason:      "a numeral can optionally lead with a `-`, has to have at least one digit before or after a `.` which must only be followed by digits",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 1, Column: 5},
 					},
 				},
 				{
@@ -864,6 +912,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 2,
 						Character:   '-',
 						Reason:      "a numeral can only be prefixed with a `-`",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 1, Column: 5},
 					},
 				},
 				{
@@ -873,6 +923,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 4,
 						Character:   '.',
 						Reason:      "a numeral can only have one `.` that is at least preceded or followed by digits",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 1, Column: 6},
 					},
 				},
 				{
@@ -881,7 +933,9 @@ func TestScanner(t *testing.T) {
 						LineNr:      1,
 						CharacterNr: 3,
 						// Character:   '.',
-						Reason: "a numeral must have at least one digit",
+						Reason:   "a numeral must have at least one digit",
+						Category: InvalidNumeral,
+						Resume:   token.Position{Row: 1, Column: 3},
 					},
 				},
 				{
@@ -891,6 +945,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 2,
 						Character:   ' ',
 						Reason:      "a numeral must have at least one digit",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 2, Column: 2},
 					},
 				},
 				{
@@ -900,6 +956,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 4,
 						Character:   ' ',
 						Reason:      "a numeral can optionally lead with a `-`, has to have at least one digit before or after a `.` which must only be followed by digits",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 1, Column: 8},
 					},
 				},
 				{
@@ -909,6 +967,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 5,
 						Character:   ' ',
 						Reason:      "a numeral must have at least one digit",
+						Category:    InvalidNumeral,
+						Resume:      token.Position{Row: 4, Column: 5},
 					},
 				},
 			}
@@ -1090,6 +1150,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 6,
 						Character:   0,
 						Reason:      "missing closing quote",
+						Category:    UnclosedString,
+						Resume:      token.Position{Row: 1, Column: 6},
 					},
 				},
 				{
@@ -1100,6 +1162,8 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 4,
 						Character:   0,
 						Reason:      "missing closing quote",
+						Category:    UnclosedString,
+						Resume:      token.Position{Row: 2, Column: 4},
 					},
 				},
 				{
@@ -1109,6 +1173,108 @@ func TestScanner(t *testing.T) {
 						CharacterNr: 16349,
 						Character:   'a',
 						Reason:      "potentially missing closing quote, found none after max 16348 characters",
+						Category:    UnclosedString,
+						Resume:      token.Position{Row: 1, Column: 16349},
+					},
+				},
+			}
+
+			for i, test := range tests {
+				t.Run(strconv.Itoa(i), func(t *testing.T) {
+					scanner, err := NewScanner(strings.NewReader(test.in))
+
+					require.NoErrorf(t, err, "NewScanner(%q)", test.in)
+
+					assertError(t, scanner, test.want)
+				})
+			}
+		})
+	})
+
+	// https://graphviz.org/doc/info/shapes.html#html
+	t.Run("HTMLIdentifiers", func(t *testing.T) {
+		t.Run("Valid", func(t *testing.T) {
+			tests := []struct {
+				in   string
+				want token.Token
+			}{
+				{
+					in: `<A>`,
+					want: token.Token{
+						Type:    token.Identifier,
+						Literal: `<A>`,
+						Start:   token.Position{Row: 1, Column: 1},
+						End:     token.Position{Row: 1, Column: 3},
+					},
+				},
+				{
+					in: `<<b>"bold"</b> -- ; [x]>`,
+					want: token.Token{
+						Type:    token.Identifier,
+						Literal: `<<b>"bold"</b> -- ; [x]>`,
+						Start:   token.Position{Row: 1, Column: 1},
+						End:     token.Position{Row: 1, Column: 24},
+					},
+				},
+				{
+					in: `<<table>
+	<tr><td>A</td></tr>
+</table>>`,
+					want: token.Token{
+						Type: token.Identifier,
+						Literal: `<<table>
+	<tr><td>A</td></tr>
+</table>>`,
+						Start: token.Position{Row: 1, Column: 1},
+						End:   token.Position{Row: 3, Column: 9},
+					},
+				},
+				{
+					in: `<<td title='"<x>"'>it's "A<br/>"</td><!-- <c> -->>`,
+					want: token.Token{
+						Type:    token.Identifier,
+						Literal: `<<td title='"<x>"'>it's "A<br/>"</td><!-- <c> -->>`,
+						Start:   token.Position{Row: 1, Column: 1},
+						End:     token.Position{Row: 1, Column: 50},
+					},
+				},
+			}
+
+			for i, test := range tests {
+				t.Run(strconv.Itoa(i), func(t *testing.T) {
+					scanner, err := NewScanner(strings.NewReader(test.in))
+
+					require.NoErrorf(t, err, "NewScanner(%q)", test.in)
+
+					assertTokens(t, scanner, []token.Token{test.want})
+				})
+			}
+		})
+
+		t.Run("QuotesDoNotAffectNesting", func(t *testing.T) {
+			in := `<<b title=">">x</b>>`
+			scanner, err := NewScanner(strings.NewReader(in))
+			require.NoErrorf(t, err, "NewScanner(%q)", in)
+
+			tok, err := scanner.Next()
+			require.NoErrorf(t, err, "Next()")
+			assert.EqualValuesf(t, tok.Literal, `<<b title=">">`, "Next() for %q", in)
+		})
+
+		t.Run("Invalid", func(t *testing.T) {
+			tests := []struct {
+				in   string
+				want Error
+			}{
+				{
+					in: `<a<b>`,
+					want: Error{
+						LineNr:      1,
+						CharacterNr: 6,
+						Character:   0,
+						Reason:      "missing closing '>' of HTML string",
+						Category:    UnclosedHTMLString,
+						Resume:      token.Position{Row: 1, Column: 6},
 					},
 				},
 			}
@@ -1199,6 +1365,8 @@ spacious
 						CharacterNr: 1,
 						Character:   '/',
 						Reason:      "missing '/' for single-line or a '*' for a multi-line comment",
+						Category:    InvalidComment,
+						Resume:      token.Position{Row: 1, Column: 2},
 					},
 				},
 				{
@@ -1220,6 +1388,8 @@ spacious
 						CharacterNr: 2,
 						Character:   '/',
 						Reason:      "missing '/' for single-line or a '*' for a multi-line comment",
+						Category:    InvalidComment,
+						Resume:      token.Position{Row: 1, Column: 3},
 					},
 				},
 				{
@@ -1229,6 +1399,8 @@ spacious
 						CharacterNr: 1,
 						Character:   '/',
 						Reason:      "missing '/' for single-line or a '*' for a multi-line comment",
+						Category:    InvalidComment,
+						Resume:      token.Position{Row: 1, Column: 3},
 					},
 				},
 				{
@@ -1238,6 +1410,19 @@ spacious
 						CharacterNr: 26,
 						Character:   0,
 						Reason:      "missing closing marker '*/' for multi-line comment",
+						Category:    UnclosedComment,
+						Resume:      token.Position{Row: 1, Column: 26},
+					},
+				},
+				{
+					in: "/*/ is not a valid comment",
+					wantError: Error{
+						LineNr:      1,
+						CharacterNr: 27,
+						Character:   0,
+						Reason:      "missing closing marker '*/' for multi-line comment",
+						Category:    UnclosedComment,
+						Resume:      token.Position{Row: 1, Column: 27},
 					},
 				},
 			}
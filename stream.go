@@ -0,0 +1,92 @@
+package dot
+
+import (
+	"io"
+
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// StreamParser parses the statements of a graph one at a time. Only the statement that is being
+// parsed is held in memory which allows processing graphs that do not fit into memory. Statements
+// are parsed as a whole so a subgraph is returned once all its statements are parsed. Comments are
+// discarded.
+type StreamParser struct {
+	p     *Parser
+	graph ast.Graph
+	done  bool
+}
+
+// NewStreamParser creates a parser reading dot source code from r. The graph header up to and
+// including the opening '{' is parsed right away.
+func NewStreamParser(r io.Reader) (*StreamParser, error) {
+	p, err := NewParser(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := StreamParser{p: p}
+	if p.peekTokenIs(token.EOF) {
+		sp.done = true
+		return &sp, nil
+	}
+
+	sp.graph, err = p.parseHeader()
+	if err != nil {
+		return nil, err
+	}
+	err = p.expectPeekTokenIsOneOf(token.LeftBrace)
+	if err != nil {
+		return nil, err
+	}
+	sp.graph.LeftBrace = p.curToken.Start
+	err = p.nextToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return &sp, nil
+}
+
+// Graph returns the graph without its statements. The position of its closing brace is only set
+// once [StreamParser.Next] returned [io.EOF].
+func (sp *StreamParser) Graph() ast.Graph {
+	return sp.graph
+}
+
+// Next parses and returns the next statement of the graph. [io.EOF] is returned once all statements
+// have been parsed. An error is returned instead if the graph is not closed before the end of the
+// file or the header of the next graph.
+func (sp *StreamParser) Next() (ast.Stmt, error) {
+	p := sp.p
+	for !sp.done {
+		if p.curTokenIs(token.RightBrace) {
+			sp.graph.RightBrace = p.curToken.End
+			sp.done = true
+			break
+		}
+		if p.isEOF() {
+			sp.done = true
+			return nil, p.syntaxError(p.curToken, "expected '}' to close the '{' at %s but reached the end of the file", sp.graph.LeftBrace)
+		}
+		if p.curTokenStartsGraph() {
+			sp.done = true
+			return nil, p.syntaxError(p.curToken, "expected '}' to close the '{' at %s but got the next graph", sp.graph.LeftBrace)
+		}
+
+		stmt, err := p.parseStatement(sp.graph)
+		if err != nil {
+			return nil, err
+		}
+		err = p.nextToken()
+		if err != nil {
+			return nil, err
+		}
+		p.comments = nil // release comments as they are not returned
+
+		if stmt != nil {
+			return stmt, nil
+		}
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,89 @@
+package dot_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/internal/assertx"
+)
+
+func TestStreamParser(t *testing.T) {
+	t.Run("StatementsMatchParse", func(t *testing.T) {
+		src := `strict digraph deps {
+	// comments are discarded
+	rankdir=LR;;
+	node [shape=box]
+	A -> B -> {C D} [color=red]
+	subgraph cluster_a { label=A; E; F -> G }
+	H
+}`
+		want, err := dot.Parse([]byte(src))
+		require.NoErrorf(t, err, "Parse(%q)", src)
+
+		sp, err := dot.NewStreamParser(strings.NewReader(src))
+		require.NoErrorf(t, err, "NewStreamParser(%q)", src)
+		var got []ast.Stmt
+		for {
+			stmt, err := sp.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoErrorf(t, err, "Next()")
+			got = append(got, stmt)
+		}
+
+		assert.EqualValuesf(t, got, want.Stmts, "Next() for %q", src)
+		g := sp.Graph()
+		want.Stmts, want.Comments = nil, nil
+		assert.EqualValuesf(t, g, want, "Graph() for %q", src)
+
+		_, err = sp.Next()
+		assert.Truef(t, errors.Is(err, io.EOF), "Next() after the last statement returned %v instead of io.EOF", err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		sp, err := dot.NewStreamParser(strings.NewReader(""))
+		require.NoErrorf(t, err, "NewStreamParser(%q)", "")
+
+		_, err = sp.Next()
+
+		assert.Truef(t, errors.Is(err, io.EOF), "Next() returned %v instead of io.EOF", err)
+	})
+
+	t.Run("InvalidHeader", func(t *testing.T) {
+		_, err := dot.NewStreamParser(strings.NewReader("graph A B {}"))
+
+		require.NotNilf(t, err, "NewStreamParser(%q)", "graph A B {}")
+	})
+
+	t.Run("InvalidStatement", func(t *testing.T) {
+		src := "graph { A -- B; = C }"
+		sp, err := dot.NewStreamParser(strings.NewReader(src))
+		require.NoErrorf(t, err, "NewStreamParser(%q)", src)
+
+		_, err = sp.Next()
+		require.NoErrorf(t, err, "Next()")
+		_, err = sp.Next()
+
+		require.NotNilf(t, err, "Next() for %q", src)
+		assert.Falsef(t, errors.Is(err, io.EOF), "Next() for %q returned io.EOF instead of an error", src)
+	})
+	t.Run("UnclosedGraph", func(t *testing.T) {
+		src := "graph { A -- B\n"
+		sp, err := dot.NewStreamParser(strings.NewReader(src))
+		require.NoErrorf(t, err, "NewStreamParser(%q)", src)
+
+		_, err = sp.Next()
+		require.NoErrorf(t, err, "Next()")
+		_, err = sp.Next()
+
+		require.NotNilf(t, err, "Next() for %q", src)
+		assertx.Contains(t, err.Error(), "expected '}' to close the '{' at 1:7")
+	})
+}
@@ -0,0 +1,381 @@
+// Package theme applies themes to dot graphs. A theme sets presentational attributes like colors
+// or fonts on the components of a graph so graphs themselves can focus on their content.
+//
+// Themes are written in a syntax borrowing from dot. A rule selects the kind of component it
+// applies to, optionally narrows it down using attribute predicates in brackets and lists the
+// attributes to set in braces
+//
+//	// dark theme
+//	graph { bgcolor=black fontcolor=white }
+//	node { color=white fontcolor=white }
+//	node [shape=box] { style=filled fillcolor="#333333" }
+//	edge [style=dashed] { color=gray }
+//	cluster { color=white }
+//
+// Components are the graph, nodes, edges and clusters which are subgraphs with an ID starting with
+// cluster.
+package theme
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/ast"
+	"github.com/teleivo/dot/token"
+)
+
+// Kind is the kind of graph component a [Rule] applies to.
+type Kind int
+
+const (
+	Graph   Kind = iota // Graph selects the root graph.
+	Node                // Node selects nodes.
+	Edge                // Edge selects edges.
+	Cluster             // Cluster selects clusters.
+)
+
+var kindStrings = map[Kind]string{
+	Graph:   "graph",
+	Node:    "node",
+	Edge:    "edge",
+	Cluster: "cluster",
+}
+
+func (k Kind) String() string {
+	return kindStrings[k]
+}
+
+// Rule sets attributes on the components of a kind that match all its predicates.
+type Rule struct {
+	Kind       Kind            // Kind of component the rule applies to.
+	Predicates []ast.Attribute // Predicates are attributes a component must have set to the same value.
+	Attrs      []ast.Attribute // Attrs are the attributes set on matching components.
+}
+
+// Theme is a list of rules applied in order.
+type Theme struct {
+	Rules []Rule
+}
+
+// Parse parses a theme from r.
+func Parse(r io.Reader) (Theme, error) {
+	sc, err := dot.NewScanner(r)
+	if err != nil {
+		return Theme{}, err
+	}
+	p := parser{sc: sc}
+	if err := p.next(); err != nil {
+		return Theme{}, err
+	}
+
+	var t Theme
+	for p.cur.Type != token.EOF {
+		rule, err := p.parseRule()
+		if err != nil {
+			return Theme{}, err
+		}
+		t.Rules = append(t.Rules, rule)
+	}
+	return t, nil
+}
+
+type parser struct {
+	sc  *dot.Scanner
+	cur token.Token
+}
+
+// next advances to the next token skipping comments.
+func (p *parser) next() error {
+	for {
+		tok, err := p.sc.Next()
+		if err != nil {
+			return err
+		}
+		if tok.Type != token.Comment {
+			p.cur = tok
+			return nil
+		}
+	}
+}
+
+func (p *parser) expect(tokenType token.TokenType) (token.Token, error) {
+	tok := p.cur
+	if tok.Type != tokenType {
+		return tok, p.errorf("expected %q instead got %q", tokenType, tok)
+	}
+	return tok, p.next()
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	// the EOF token has no position
+	if p.cur.Type == token.EOF {
+		return fmt.Errorf(format, args...)
+	}
+	return fmt.Errorf("%d:%d: %s", p.cur.Start.Row, p.cur.Start.Column, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseRule() (Rule, error) {
+	var rule Rule
+	switch {
+	case p.cur.Type == token.Graph:
+		rule.Kind = Graph
+	case p.cur.Type == token.Node:
+		rule.Kind = Node
+	case p.cur.Type == token.Edge:
+		rule.Kind = Edge
+	case p.cur.Type == token.Identifier && p.cur.Literal == "cluster":
+		rule.Kind = Cluster
+	default:
+		return rule, p.errorf("expected one of graph, node, edge or cluster instead got %q", p.cur)
+	}
+	if err := p.next(); err != nil {
+		return rule, err
+	}
+
+	if p.cur.Type == token.LeftBracket {
+		if err := p.next(); err != nil {
+			return rule, err
+		}
+		predicates, err := p.parseAttrs(token.RightBracket)
+		if err != nil {
+			return rule, err
+		}
+		rule.Predicates = predicates
+	}
+
+	if _, err := p.expect(token.LeftBrace); err != nil {
+		return rule, err
+	}
+	attrs, err := p.parseAttrs(token.RightBrace)
+	if err != nil {
+		return rule, err
+	}
+	rule.Attrs = attrs
+	return rule, nil
+}
+
+// parseAttrs parses name-value pairs separated by an optional ';' or ',' up to and including the
+// closing token.
+func (p *parser) parseAttrs(closing token.TokenType) ([]ast.Attribute, error) {
+	var attrs []ast.Attribute
+	for p.cur.Type != closing {
+		if p.cur.Type == token.EOF {
+			return nil, p.errorf("expected %q instead got %q", closing, p.cur)
+		}
+		name, err := p.expect(token.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(token.Equal); err != nil {
+			return nil, err
+		}
+		value, err := p.expect(token.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, ast.Attribute{
+			Name:  ast.ID{Literal: name.Literal},
+			Value: ast.ID{Literal: value.Literal},
+		})
+		if p.cur.Type == token.Semicolon || p.cur.Type == token.Comma {
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return attrs, p.next()
+}
+
+// Apply applies the theme t to the graph g. The graph is modified in place.
+//
+// Attributes set explicitly in the graph take precedence over the ones of the theme. Rules without
+// predicates for the graph, nodes and edges are thus added as attribute statements like
+// node [color=white] to the start of the graph. Rules for clusters add attributes to the start of
+// matching clusters. Rules with predicates for nodes and edges add attributes to matching node and
+// edge statements which do not set the attribute themselves. Predicates are matched against the
+// unquoted names and values of the attributes set on the graph, cluster or statement itself.
+// Attributes inherited via attribute statements are not considered.
+func Apply(g *ast.Graph, t Theme) {
+	var defaults []ast.Stmt
+	for _, rule := range t.Rules {
+		if len(rule.Predicates) > 0 || rule.Kind == Cluster {
+			continue
+		}
+		defaults = append(defaults, &ast.AttrStmt{
+			ID:       ast.ID{Literal: rule.Kind.String()},
+			AttrList: *newAttrList(rule.Attrs),
+		})
+	}
+
+	for _, rule := range t.Rules {
+		if rule.Kind == Graph && len(rule.Predicates) > 0 && matches(graphAttrs(g.Stmts), rule.Predicates) {
+			for _, attr := range rule.Attrs {
+				defaults = append(defaults, attr)
+			}
+		}
+	}
+
+	g.Stmts = append(defaults, applyStmts(g.Stmts, t)...)
+}
+
+func applyStmts(stmts []ast.Stmt, t Theme) []ast.Stmt {
+	for i, stmt := range stmts {
+		switch st := stmt.(type) {
+		case *ast.NodeStmt:
+			st.AttrList = applyRules(st.AttrList, t, Node)
+		case *ast.EdgeStmt:
+			st.Left = applyEdgeOperand(st.Left, t)
+			for cur := &st.Right; cur != nil; cur = cur.Next {
+				cur.Right = applyEdgeOperand(cur.Right, t)
+			}
+			st.AttrList = applyRules(st.AttrList, t, Edge)
+		case ast.Subgraph:
+			stmts[i] = applySubgraph(st, t)
+		}
+	}
+	return stmts
+}
+
+func applyEdgeOperand(operand ast.EdgeOperand, t Theme) ast.EdgeOperand {
+	subgraph, ok := operand.(ast.Subgraph)
+	if !ok {
+		return operand
+	}
+	return applySubgraph(subgraph, t)
+}
+
+func applySubgraph(subgraph ast.Subgraph, t Theme) ast.Subgraph {
+	var defaults []ast.Stmt
+	if subgraph.ID != nil && strings.HasPrefix(subgraph.ID.Unquoted(), "cluster") {
+		attrs := graphAttrs(subgraph.Stmts)
+		for _, rule := range t.Rules {
+			if rule.Kind == Cluster && matches(attrs, rule.Predicates) {
+				for _, attr := range rule.Attrs {
+					defaults = append(defaults, attr)
+				}
+			}
+		}
+	}
+	subgraph.Stmts = append(defaults, applyStmts(subgraph.Stmts, t)...)
+	return subgraph
+}
+
+// applyRules adds the attributes of the rules of given kind with predicates matching the attribute
+// list. Attributes already present in the attribute list are not changed.
+func applyRules(attrList *ast.AttrList, t Theme, kind Kind) *ast.AttrList {
+	attrs := attributes(attrList)
+	var added []ast.Attribute
+	for _, rule := range t.Rules {
+		if rule.Kind != kind || len(rule.Predicates) == 0 || !matches(attrs, rule.Predicates) {
+			continue
+		}
+		for _, attr := range rule.Attrs {
+			if _, ok := lookup(attrs, attr.Name.Unquoted()); ok {
+				continue
+			}
+			if i := indexOf(added, attr.Name.Unquoted()); i >= 0 {
+				added[i] = attr
+				continue
+			}
+			added = append(added, attr)
+		}
+	}
+	if len(added) == 0 {
+		return attrList
+	}
+
+	if attrList == nil {
+		return newAttrList(added)
+	}
+	last := attrList
+	for last.Next != nil {
+		last = last.Next
+	}
+	if last.AList == nil {
+		last.AList = newAttrList(added).AList
+		return attrList
+	}
+	aList := last.AList
+	for aList.Next != nil {
+		aList = aList.Next
+	}
+	aList.Next = newAttrList(added).AList
+	return attrList
+}
+
+// matches reports whether attrs contain all predicates.
+func matches(attrs, predicates []ast.Attribute) bool {
+	for _, predicate := range predicates {
+		value, ok := lookup(attrs, predicate.Name.Unquoted())
+		if !ok || value != predicate.Value.Unquoted() {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the value of the last attribute with given name.
+func lookup(attrs []ast.Attribute, name string) (string, bool) {
+	var value string
+	var found bool
+	for _, attr := range attrs {
+		if attr.Name.Unquoted() == name {
+			value, found = attr.Value.Unquoted(), true
+		}
+	}
+	return value, found
+}
+
+func indexOf(attrs []ast.Attribute, name string) int {
+	for i, attr := range attrs {
+		if attr.Name.Unquoted() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// graphAttrs returns the attributes set on a graph or subgraph by its statements. These are
+// attribute statements like label=G and attributes of graph attribute statements like
+// graph [label=G]. Nested subgraphs are not considered.
+func graphAttrs(stmts []ast.Stmt) []ast.Attribute {
+	var result []ast.Attribute
+	for _, stmt := range stmts {
+		switch st := stmt.(type) {
+		case ast.Attribute:
+			result = append(result, st)
+		case *ast.AttrStmt:
+			if token.Lookup(st.ID.Literal) == token.Graph {
+				result = append(result, attributes(&st.AttrList)...)
+			}
+		}
+	}
+	return result
+}
+
+// attributes returns the attributes of all the attribute lists chained together.
+func attributes(attrList *ast.AttrList) []ast.Attribute {
+	var result []ast.Attribute
+	for cur := attrList; cur != nil; cur = cur.Next {
+		for aList := cur.AList; aList != nil; aList = aList.Next {
+			result = append(result, aList.Attribute)
+		}
+	}
+	return result
+}
+
+func newAttrList(attrs []ast.Attribute) *ast.AttrList {
+	var first, prev *ast.AList
+	for _, attr := range attrs {
+		cur := &ast.AList{Attribute: attr}
+		if first == nil {
+			first = cur
+		} else {
+			prev.Next = cur
+		}
+		prev = cur
+	}
+	return &ast.AttrList{AList: first}
+}
@@ -0,0 +1,123 @@
+package theme_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+	"github.com/teleivo/assertive/require"
+	"github.com/teleivo/dot"
+	"github.com/teleivo/dot/internal/assertx"
+	"github.com/teleivo/dot/theme"
+)
+
+func TestApply(t *testing.T) {
+	dark := `// dark theme
+graph { bgcolor=black; fontcolor=white }
+node { color=white, fontcolor=white }
+node [shape=box] { style=filled fillcolor="#333333" }
+node [shape=box, label=DB] { fillcolor=gray }
+edge [style=dashed] { color=gray }
+cluster { color=white }
+cluster [label=Backend] { style=dotted }
+`
+
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"Defaults": {
+			in: `digraph {
+	A -> B
+}`,
+			want: `digraph {
+	graph [bgcolor=black,fontcolor=white]
+	node [color=white,fontcolor=white]
+	A -> B
+}`,
+		},
+		"NodesAndEdgesMatchingPredicates": {
+			in: `digraph {
+	A [shape=box]
+	B [shape=box,label=DB,fillcolor=red]
+	C [shape=circle]
+	A -> B [style=dashed]
+}`,
+			want: `digraph {
+	graph [bgcolor=black,fontcolor=white]
+	node [color=white,fontcolor=white]
+	A [shape=box,style=filled,fillcolor="#333333"]
+	B [shape=box,label=DB,fillcolor=red,style=filled]
+	C [shape=circle]
+	A -> B [style=dashed,color=gray]
+}`,
+		},
+		"Clusters": {
+			in: `digraph {
+	subgraph cluster_a {
+		label=Backend
+		subgraph cluster_b {
+			color=red
+			{A [shape=box]} -> B
+		}
+	}
+	subgraph other {
+		C
+	}
+}`,
+			want: `digraph {
+	graph [bgcolor=black,fontcolor=white]
+	node [color=white,fontcolor=white]
+	subgraph cluster_a {color=white style=dotted label=Backend subgraph cluster_b {color=white color=red subgraph {A [shape=box,style=filled,fillcolor="#333333"]} -> B}}
+	subgraph other {C}
+}`,
+		},
+	}
+
+	th, err := theme.Parse(strings.NewReader(dark))
+	require.NoErrorf(t, err, "Parse(%q)", dark)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g, err := dot.Parse([]byte(test.in))
+			require.NoErrorf(t, err, "Parse(%q)", test.in)
+
+			theme.Apply(&g, th)
+
+			assert.EqualValuesf(t, g.String(), test.want, "Apply(%q)", test.in)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		in     string
+		errMsg string
+	}{
+		"UnknownKind": {
+			in:     `subgraph { color=red }`,
+			errMsg: `1:1: expected one of graph, node, edge or cluster instead got "subgraph"`,
+		},
+		"MissingAttributes": {
+			in:     `node [shape=box]`,
+			errMsg: `expected "{" instead got "EOF"`,
+		},
+		"MissingValue": {
+			in:     `node { color= }`,
+			errMsg: `1:15: expected "IDENTIFIER" instead got "}"`,
+		},
+		"UnclosedPredicates": {
+			in:     `edge [style=dashed`,
+			errMsg: `expected "]" instead got "EOF"`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := theme.Parse(strings.NewReader(test.in))
+
+			require.NotNilf(t, err, "Parse(%q)", test.in)
+			assertx.Contains(t, err.Error(), test.errMsg)
+		})
+	}
+}
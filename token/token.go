@@ -3,6 +3,7 @@
 package token
 
 import (
+	"slices"
 	"strings"
 )
 
@@ -139,3 +140,17 @@ func Lookup(identifier string) TokenType {
 
 	return Identifier
 }
+
+// Index returns the index of the token starting at given position. The tokens must be sorted by
+// their start position. The boolean is false if no token starts at the position. The tokens
+// neighboring an AST node can thus be found using the start position of the AST node.
+func Index(tokens []Token, pos Position) (int, bool) {
+	return slices.BinarySearchFunc(tokens, pos, func(tok Token, pos Position) int {
+		if tok.Start.Before(pos) {
+			return -1
+		} else if tok.Start.After(pos) {
+			return 1
+		}
+		return 0
+	})
+}
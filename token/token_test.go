@@ -0,0 +1,57 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/teleivo/assertive/assert"
+)
+
+func TestIndex(t *testing.T) {
+	tokens := []Token{
+		{Type: Graph, Literal: "graph", Start: Position{Row: 1, Column: 1}, End: Position{Row: 1, Column: 5}},
+		{Type: LeftBrace, Literal: "{", Start: Position{Row: 1, Column: 7}, End: Position{Row: 1, Column: 7}},
+		{Type: Identifier, Literal: "A", Start: Position{Row: 2, Column: 2}, End: Position{Row: 2, Column: 2}},
+		{Type: RightBrace, Literal: "}", Start: Position{Row: 3, Column: 1}, End: Position{Row: 3, Column: 1}},
+	}
+
+	tests := map[string]struct {
+		in     Position
+		want   int
+		wantOk bool
+	}{
+		"First": {
+			in:     Position{Row: 1, Column: 1},
+			want:   0,
+			wantOk: true,
+		},
+		"Last": {
+			in:     Position{Row: 3, Column: 1},
+			want:   3,
+			wantOk: true,
+		},
+		"OnOtherRow": {
+			in:     Position{Row: 2, Column: 2},
+			want:   2,
+			wantOk: true,
+		},
+		"InsideToken": {
+			in:     Position{Row: 1, Column: 3},
+			want:   1,
+			wantOk: false,
+		},
+		"AfterLast": {
+			in:     Position{Row: 4, Column: 1},
+			want:   4,
+			wantOk: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := Index(tokens, test.in)
+
+			assert.EqualValuesf(t, got, test.want, "Index(%s)", test.in)
+			assert.EqualValuesf(t, ok, test.wantOk, "Index(%s)", test.in)
+		})
+	}
+}